@@ -0,0 +1,46 @@
+package accounting
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The statuses of the invoice syncs.
+const (
+	SyncStatusSynced = "synced"
+	// SyncStatusFailed syncs are retried from their NextAttemptAt.
+	SyncStatusFailed = "failed"
+	// SyncStatusAbandoned syncs failed too many times, and are only retried on request or once their invoice changes.
+	SyncStatusAbandoned = "abandoned"
+)
+
+// InvoiceSync struct
+//
+// An invoice sync is the state of the export of a booking's invoice to the books of a tenant.
+// The type name makes Tigris name the collection `invoice_syncs`.
+type InvoiceSync struct {
+	ID            string    `json:"id" tigris:"primaryKey:1" example:"0b6f3f5e-2a6e-4c4e-9d3b-1b1e6a7e4c4e|5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	TenantID      string    `json:"tenantId" tigris:"index" example:"0b6f3f5e-2a6e-4c4e-9d3b-1b1e6a7e4c4e"`
+	BookingID     uuid.UUID `json:"bookingId" tigris:"index" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	InvoiceNumber string    `json:"invoiceNumber" example:"BK-5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	Status        string    `json:"status" tigris:"index" example:"synced"`
+	Fingerprint   string    `json:"fingerprint" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	ExternalID    string    `json:"externalId" example:"243216c5-369e-4056-ac67-05388f86dc81"`
+	Attempts      int       `json:"attempts" example:"1"`
+	LastError     string    `json:"lastError,omitempty" example:"accounting export error (status 503): The Xero API is temporarily unavailable"`
+	NextAttemptAt time.Time `json:"nextAttemptAt" example:"2023-01-05T00:00:00.000Z"`
+	SyncedAt      time.Time `json:"syncedAt" example:"2023-01-05T00:00:00.000Z"`
+	CreatedAt     time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt     time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// SyncReport struct
+//
+// A sync report counts what a sync of a tenant did with the invoices of the paid bookings.
+type SyncReport struct {
+	TenantID string `json:"tenantId" example:"0b6f3f5e-2a6e-4c4e-9d3b-1b1e6a7e4c4e"`
+	Exported int    `json:"exported" example:"3"`
+	Skipped  int    `json:"skipped" example:"40"`
+	Failed   int    `json:"failed" example:"1"`
+}
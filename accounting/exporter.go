@@ -0,0 +1,98 @@
+package accounting
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Exporter pushes the invoices to the books of a tenant, e.g. an organisation in an accounting system.
+type Exporter interface {
+	// Export creates the invoice in the tenant's books, or updates it when its external ID, returned by the
+	// previous export of the invoice, is given. It returns the invoice's ID in the tenant's books.
+	Export(ctx context.Context, tenantID string, invoice Invoice, externalID string) (string, error)
+}
+
+// The formats of the FileExporter.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// FileExporter writes the invoices to files, one per invoice in a directory per tenant,
+// e.g. for accounting systems importing files.
+type FileExporter struct {
+	dir    string
+	format string
+}
+
+// NewFileExporter returns a FileExporter writing the invoices under the directory, as CSV or JSON.
+func NewFileExporter(dir, format string) (*FileExporter, error) {
+	if format != FormatCSV && format != FormatJSON {
+		return nil, fmt.Errorf("unknown export format %q, expected %s or %s", format, FormatCSV, FormatJSON)
+	}
+	return &FileExporter{dir: dir, format: format}, nil
+}
+
+// Export writes the invoice to `<dir>/<tenant>/<invoice number>.<format>`, replacing the previous export of the invoice.
+// The CSV files have a row per line item, repeating the invoice's columns.
+func (e *FileExporter) Export(ctx context.Context, tenantID string, invoice Invoice, externalID string) (string, error) {
+	if tenantID == "" || filepath.Base(tenantID) != tenantID {
+		return "", fmt.Errorf("invalid tenant ID %q", tenantID)
+	}
+	dir := filepath.Join(e.dir, tenantID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, ".invoice-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if e.format == FormatJSON {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(invoice)
+	} else {
+		err = writeCSV(f, invoice)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), filepath.Join(dir, invoice.InvoiceNumber+"."+e.format)); err != nil {
+		return "", err
+	}
+	return invoice.InvoiceNumber, nil
+}
+
+func writeCSV(w io.Writer, invoice Invoice) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"invoiceNumber", "bookingId", "reference", "contactName", "contactEmail", "date", "currency", "description", "quantity", "unitAmount", "amount", "total"})
+	for _, item := range invoice.LineItems {
+		_ = cw.Write([]string{
+			invoice.InvoiceNumber,
+			invoice.BookingID.String(),
+			invoice.Reference,
+			invoice.Contact.Name,
+			invoice.Contact.Email,
+			invoice.Date.Format("2006-01-02"),
+			invoice.Currency,
+			item.Description,
+			strconv.FormatInt(item.Quantity, 10),
+			strconv.FormatInt(item.UnitAmount, 10),
+			strconv.FormatInt(item.Amount, 10),
+			strconv.FormatInt(invoice.Total, 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
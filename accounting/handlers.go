@@ -0,0 +1,163 @@
+package accounting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+func GetInvoice(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := bookingID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /accounting/invoices/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.GetInvoice(r.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func GetTenantSyncs(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		xqp, err := xeroQueryParams(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		qp := crud.PaginationQueryParams(r)
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /accounting/syncs - PaginationQueryParams: %+v - XeroQueryParams: %+v - Took: %v\n", qp, xqp, time.Since(begin))
+		}(time.Now())
+		data, metadata, err := s.GetTenantSyncs(r.Context(), qp, xqp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data, Metadata: metadata})
+	}
+}
+
+func SyncTenant(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		xqp, err := xeroQueryParams(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /accounting/syncs - XeroQueryParams: %+v - Took: %v\n", xqp, time.Since(begin))
+		}(time.Now())
+		data, err := s.SyncTenant(r.Context(), xqp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func RetrySync(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := bookingID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		xqp, err := xeroQueryParams(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /accounting/syncs/%s/retry - XeroQueryParams: %+v - Took: %v\n", id, xqp, time.Since(begin))
+		}(time.Now())
+		data, err := s.RetrySync(r.Context(), xqp, id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+// bookingID parses the `id` path variable, an invalid ID being reported as not found.
+func bookingID(r *http.Request) (uuid.UUID, error) {
+	v, ok := mux.Vars(r)["id"]
+	if !ok {
+		panic(ErrBadRouting)
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return uuid.Nil, ErrNotFound
+	}
+	return id, nil
+}
+
+// xeroQueryParams reads and validates the required xeroTenantId query param.
+func xeroQueryParams(r *http.Request) (params.XeroQueryParams, error) {
+	xqp := params.XeroQueryParams{XeroTenantId: r.URL.Query().Get("xeroTenantId")}
+	return xqp, validation.Struct(xqp)
+}
+
+// writeError maps the error to its HTTP status code and writes it as the response.
+func writeError(w http.ResponseWriter, err error) {
+	var status int
+	message := err.Error()
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNotInvoiceable), errors.Is(err, ErrSyncInProgress):
+		status = http.StatusConflict
+	case validation.IsValidationError(err):
+		status = http.StatusUnprocessableEntity
+	default:
+		log.Printf("Unable to handle accounting request: %+v\n", err)
+		status = http.StatusInternalServerError
+		message = "internal error"
+	}
+	writeResponse(w, Response{Status: status, Message: message})
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	// set the content type to application/json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
+
+	// encode the response struct as JSON and write it to the response writer
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		// handle the error
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+		return
+	}
+}
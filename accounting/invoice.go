@@ -0,0 +1,88 @@
+package accounting
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/booking"
+	"github.com/simply-alliv/tigris-go-explore/customer"
+	"github.com/simply-alliv/tigris-go-explore/payment"
+)
+
+// Invoice struct
+//
+// An invoice is the accounting document of a paid booking. The amounts are in the currency's minor unit, e.g. kobo.
+type Invoice struct {
+	InvoiceNumber string     `json:"invoiceNumber" example:"BK-5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	BookingID     uuid.UUID  `json:"bookingId" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	Reference     string     `json:"reference" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	Contact       Contact    `json:"contact"`
+	Date          time.Time  `json:"date" example:"2023-01-05T00:00:00.000Z"`
+	Currency      string     `json:"currency" example:"NGN"`
+	LineItems     []LineItem `json:"lineItems"`
+	Total         int64      `json:"total" example:"500000"`
+}
+
+// Contact struct
+type Contact struct {
+	Name  string `json:"name" example:"Jane Doe"`
+	Email string `json:"email" example:"jane.doe@example.com"`
+}
+
+// LineItem struct
+//
+// The refunds are line items with a negative unit amount.
+type LineItem struct {
+	Description string `json:"description" example:"Grooming of a affenpinscher on 2023-01-05 at 14:30 (60 minutes)"`
+	Quantity    int64  `json:"quantity" example:"1"`
+	UnitAmount  int64  `json:"unitAmount" example:"500000"`
+	Amount      int64  `json:"amount" example:"500000"`
+}
+
+// NewInvoice builds the invoice of a paid booking from its successful, and possibly refunded, payments.
+// It returns false when none of the payments went through.
+func NewInvoice(b booking.Booking, c customer.Customer, payments []payment.Payment) (Invoice, bool) {
+	invoice := Invoice{
+		InvoiceNumber: "BK-" + b.ID.String(),
+		BookingID:     b.ID,
+		Contact:       Contact{Name: c.Name, Email: c.Email},
+		LineItems:     []LineItem{},
+	}
+	if invoice.Contact.Name == "" {
+		// The customer was deleted, the accounting systems require a contact name.
+		invoice.Contact.Name = "Customer " + b.CustomerID.String()
+	}
+	description := fmt.Sprintf("Grooming of a %s on %s at %s (%d minutes)", b.BreedID, b.BookedFor.Format("2006-01-02"), b.BookedForTime, b.Duration)
+	for _, p := range payments {
+		if p.Status != payment.StatusSuccess && p.Status != payment.StatusRefunded {
+			continue
+		}
+		if invoice.Reference == "" {
+			invoice.Reference = p.Reference
+			invoice.Date = p.PaidAt.UTC().Truncate(24 * time.Hour)
+			invoice.Currency = p.Currency
+		}
+		invoice.add(LineItem{Description: description, Quantity: 1, UnitAmount: p.Amount})
+		if p.RefundedAmount > 0 {
+			invoice.add(LineItem{Description: "Refund of payment " + p.Reference, Quantity: 1, UnitAmount: -p.RefundedAmount})
+		}
+	}
+	return invoice, len(invoice.LineItems) > 0
+}
+
+func (i *Invoice) add(item LineItem) {
+	item.Amount = item.Quantity * item.UnitAmount
+	i.LineItems = append(i.LineItems, item)
+	i.Total += item.Amount
+}
+
+// Fingerprint returns a hash of the invoice's content, which changes when it needs exporting again, e.g. once refunded.
+func (i Invoice) Fingerprint() string {
+	b, _ := json.Marshal(i)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,90 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+// ErrNotFound is returned when a booking, or its sync, doesn't exist.
+var ErrNotFound = errors.New("invoice sync not found")
+
+// Repository is responsible for managing the persistence layer of the invoice syncs. (e.g. database operations)
+type Repository interface {
+	GetTenantSyncs(ctx context.Context, tenantID string, qp params.PaginationQueryParams) ([]InvoiceSync, *pagination.PaginationData, error)
+	GetSingleSync(ctx context.Context, tenantID string, bookingID uuid.UUID) (InvoiceSync, error)
+	SaveSingleSync(ctx context.Context, sync InvoiceSync) error
+}
+
+type syncRepository struct {
+	collection *tigris.Collection[InvoiceSync]
+}
+
+// NewSyncRepository returns a concrete implementation of the Repository interface.
+func NewSyncRepository(db *tigris.Database) Repository {
+	return &syncRepository{collection: tigris.GetCollection[InvoiceSync](db)}
+}
+
+func (r syncRepository) GetTenantSyncs(ctx context.Context, tenantID string, qp params.PaginationQueryParams) ([]InvoiceSync, *pagination.PaginationData, error) {
+	var syncs []InvoiceSync = []InvoiceSync{}
+	f := filter.Eq("tenantId", tenantID)
+
+	// Add initial pagination data
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	c, err := r.collection.Count(ctx, f)
+	if err != nil {
+		return syncs, &m, err
+	}
+	if !qp.Paginate {
+		m.Page = 1
+		m.PerPage = c
+	}
+	options := tigris.ReadOptions{
+		Skip:  (m.Page - 1) * m.PerPage,
+		Limit: m.PerPage,
+	}
+	it, err := r.collection.ReadWithOptions(ctx, f, fields.All, &options)
+	if err != nil {
+		return syncs, &m, err
+	}
+	defer it.Close()
+
+	var sync InvoiceSync
+	for it.Next(&sync) {
+		syncs = append(syncs, sync)
+	}
+
+	m = pagination.New(m.Page, m.PerPage, c)
+
+	return syncs, &m, it.Err()
+}
+
+func (r syncRepository) GetSingleSync(ctx context.Context, tenantID string, bookingID uuid.UUID) (InvoiceSync, error) {
+	sync, err := r.collection.ReadOne(ctx, filter.Eq("id", syncID(tenantID, bookingID)))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return InvoiceSync{}, ErrNotFound
+	}
+	if err != nil {
+		return InvoiceSync{}, err
+	}
+	return *sync, nil
+}
+
+func (r syncRepository) SaveSingleSync(ctx context.Context, sync InvoiceSync) error {
+	_, err := r.collection.InsertOrReplace(ctx, &sync)
+	return err
+}
+
+// syncID returns the ID of the sync of a booking's invoice to a tenant.
+func syncID(tenantID string, bookingID uuid.UUID) string {
+	return tenantID + "|" + bookingID.String()
+}
@@ -0,0 +1,344 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/booking"
+	"github.com/simply-alliv/tigris-go-explore/customer"
+	"github.com/simply-alliv/tigris-go-explore/payment"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of attempts at exporting an invoice before its sync is abandoned.
+	DefaultMaxAttempts = 8
+	// DefaultInitialBackoff is the default delay before the first retry, doubled on every following one.
+	DefaultInitialBackoff = time.Minute
+	// DefaultMaxBackoff caps the delay between two retries.
+	DefaultMaxBackoff = 6 * time.Hour
+)
+
+var (
+	// ErrNotInvoiceable is returned for the bookings which have no successful payment to invoice.
+	ErrNotInvoiceable = errors.New("booking has no payment to invoice")
+	// ErrSyncInProgress is returned when the tenant is already being synced.
+	ErrSyncInProgress = errors.New("tenant sync already in progress")
+)
+
+// BookingReader reads the bookings the invoices are made for.
+type BookingReader interface {
+	GetAllBookings(ctx context.Context, qp params.PaginationQueryParams, bqp params.BookingQueryParams) ([]booking.Booking, *pagination.PaginationData, error)
+	GetSingleBooking(ctx context.Context, id uuid.UUID) (booking.Booking, error)
+}
+
+// PaymentReader reads the payments of the bookings.
+type PaymentReader interface {
+	GetBookingPayments(ctx context.Context, bookingID uuid.UUID, qp params.PaginationQueryParams) ([]payment.Payment, *pagination.PaginationData, error)
+}
+
+// CustomerReader reads the customers the invoices are addressed to.
+type CustomerReader interface {
+	GetSingleCustomer(ctx context.Context, id uuid.UUID) (customer.Customer, error)
+}
+
+// Option configures the Service.
+type Option func(*Service)
+
+// WithMaxAttempts sets the number of attempts at exporting an invoice before its sync is abandoned.
+func WithMaxAttempts(n int) Option {
+	return func(s *Service) {
+		s.maxAttempts = n
+	}
+}
+
+type Service struct {
+	r           Repository
+	exporter    Exporter
+	bookings    BookingReader
+	payments    PaymentReader
+	customers   CustomerReader
+	maxAttempts int
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// NewAccountingService returns a service
+func NewAccountingService(r Repository, exporter Exporter, bookings BookingReader, payments PaymentReader, customers CustomerReader, opts ...Option) *Service {
+	s := &Service{
+		r:           r,
+		exporter:    exporter,
+		bookings:    bookings,
+		payments:    payments,
+		customers:   customers,
+		maxAttempts: DefaultMaxAttempts,
+		running:     map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetInvoice godoc
+// @Summary Get the invoice of a booking
+// @Description Get the invoice document of a paid booking, as it is exported
+// @Security Bearer
+// @Tags Accounting
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the booking resource"
+// @Success 200 {object} JSONResultSuccess{data=Invoice} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /accounting/invoices/{id} [get]
+func (s *Service) GetInvoice(ctx context.Context, bookingID uuid.UUID) (Invoice, error) {
+	b, err := s.bookings.GetSingleBooking(ctx, bookingID)
+	if errors.Is(err, booking.ErrNotFound) {
+		return Invoice{}, ErrNotFound
+	}
+	if err != nil {
+		return Invoice{}, err
+	}
+	invoice, ok, err := s.invoice(ctx, b)
+	if err != nil {
+		return Invoice{}, err
+	}
+	if !ok {
+		return Invoice{}, ErrNotInvoiceable
+	}
+	return invoice, nil
+}
+
+// GetTenantSyncs godoc
+// @Summary Get the invoice syncs of a tenant
+// @Description Get the state of the export of every invoice to the books of a tenant
+// @Security Bearer
+// @Tags Accounting
+// @Accept json
+// @Produce json
+// @Param xeroTenantId query string true "ID of the tenant"
+// @Success 200 {object} JSONResultSuccess{data=[]InvoiceSync} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /accounting/syncs [get]
+func (s *Service) GetTenantSyncs(ctx context.Context, qp params.PaginationQueryParams, xqp params.XeroQueryParams) ([]InvoiceSync, *pagination.PaginationData, error) {
+	return s.r.GetTenantSyncs(ctx, xqp.XeroTenantId, qp)
+}
+
+// SyncTenant godoc
+// @Summary Sync the invoices of a tenant
+// @Description Export the invoices of the paid bookings to the books of a tenant. The invoices already synced are skipped unless they changed, e.g. once refunded, and the failed ones are retried once their backoff elapsed
+// @Security Bearer
+// @Tags Accounting
+// @Accept json
+// @Produce json
+// @Param xeroTenantId query string true "ID of the tenant"
+// @Success 200 {object} JSONResultSuccess{data=SyncReport} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /accounting/syncs [post]
+func (s *Service) SyncTenant(ctx context.Context, xqp params.XeroQueryParams) (SyncReport, error) {
+	tenantID := xqp.XeroTenantId
+	report := SyncReport{TenantID: tenantID}
+	if !s.lock(tenantID) {
+		return report, ErrSyncInProgress
+	}
+	defer s.unlock(tenantID)
+
+	paid := true
+	bookings, _, err := s.bookings.GetAllBookings(ctx, params.PaginationQueryParams{Paginate: false}, params.BookingQueryParams{Paid: &paid})
+	if err != nil {
+		return report, err
+	}
+	existing, _, err := s.r.GetTenantSyncs(ctx, tenantID, params.PaginationQueryParams{Paginate: false})
+	if err != nil {
+		return report, err
+	}
+	syncs := make(map[uuid.UUID]InvoiceSync, len(existing))
+	for _, sync := range existing {
+		syncs[sync.BookingID] = sync
+	}
+
+	now := time.Now().UTC()
+	for _, b := range bookings {
+		invoice, ok, err := s.invoice(ctx, b)
+		if err != nil {
+			return report, err
+		}
+		if !ok {
+			continue
+		}
+		sync, found := syncs[b.ID]
+		if found && sync.Fingerprint == invoice.Fingerprint() &&
+			(sync.Status == SyncStatusSynced || sync.Status == SyncStatusAbandoned || now.Before(sync.NextAttemptAt)) {
+			report.Skipped++
+			continue
+		}
+		sync, err = s.export(ctx, tenantID, invoice, sync)
+		if err != nil {
+			return report, err
+		}
+		if sync.Status == SyncStatusSynced {
+			report.Exported++
+		} else {
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// RetrySync godoc
+// @Summary Retry the sync of an invoice
+// @Description Export the invoice of a booking to the books of a tenant now, whatever the state of its sync, e.g. once abandoned
+// @Security Bearer
+// @Tags Accounting
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the booking resource"
+// @Param xeroTenantId query string true "ID of the tenant"
+// @Success 200 {object} JSONResultSuccess{data=InvoiceSync} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /accounting/syncs/{id}/retry [post]
+func (s *Service) RetrySync(ctx context.Context, xqp params.XeroQueryParams, bookingID uuid.UUID) (InvoiceSync, error) {
+	tenantID := xqp.XeroTenantId
+	if !s.lock(tenantID) {
+		return InvoiceSync{}, ErrSyncInProgress
+	}
+	defer s.unlock(tenantID)
+
+	invoice, err := s.GetInvoice(ctx, bookingID)
+	if err != nil {
+		return InvoiceSync{}, err
+	}
+	sync, err := s.r.GetSingleSync(ctx, tenantID, bookingID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return InvoiceSync{}, err
+	}
+	// Give the invoice a fresh set of attempts.
+	sync.Attempts = 0
+	return s.export(ctx, tenantID, invoice, sync)
+}
+
+// Run syncs the tenants every interval, until the context is done.
+func (s *Service) Run(ctx context.Context, tenantIDs []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, tenantID := range tenantIDs {
+				report, err := s.SyncTenant(ctx, params.XeroQueryParams{XeroTenantId: tenantID})
+				if err != nil && ctx.Err() == nil {
+					log.Printf("Unable to sync the invoices of tenant %s: %+v\n", tenantID, err)
+					continue
+				}
+				if report.Exported > 0 || report.Failed > 0 {
+					fmt.Printf("Synced the invoices of tenant %s - Exported: %d - Failed: %d\n", tenantID, report.Exported, report.Failed)
+				}
+			}
+		}
+	}
+}
+
+// export exports the invoice and records the attempt in its sync, the attempts being counted again once the invoice changed.
+// It only fails when the sync can't be saved.
+func (s *Service) export(ctx context.Context, tenantID string, invoice Invoice, sync InvoiceSync) (InvoiceSync, error) {
+	now := time.Now().UTC()
+	fingerprint := invoice.Fingerprint()
+	if sync.ID == "" {
+		sync = InvoiceSync{ID: syncID(tenantID, invoice.BookingID), TenantID: tenantID, BookingID: invoice.BookingID, CreatedAt: now}
+	}
+	if sync.Fingerprint != fingerprint {
+		sync.Attempts = 0
+	}
+	sync.InvoiceNumber = invoice.InvoiceNumber
+	sync.Attempts++
+	sync.UpdatedAt = now
+
+	externalID, err := s.exporter.Export(ctx, tenantID, invoice, sync.ExternalID)
+	if err != nil {
+		sync.LastError = err.Error()
+		sync.Fingerprint = fingerprint
+		if sync.Attempts >= s.maxAttempts {
+			sync.Status = SyncStatusAbandoned
+		} else {
+			sync.Status = SyncStatusFailed
+			sync.NextAttemptAt = now.Add(backoff(sync.Attempts))
+		}
+	} else {
+		sync.Status = SyncStatusSynced
+		sync.Fingerprint = fingerprint
+		sync.ExternalID = externalID
+		sync.LastError = ""
+		sync.NextAttemptAt = time.Time{}
+		sync.SyncedAt = now
+	}
+	if err := s.r.SaveSingleSync(ctx, sync); err != nil {
+		return sync, err
+	}
+	return sync, nil
+}
+
+// invoice builds the invoice of the booking, returning false when it has no successful payment.
+func (s *Service) invoice(ctx context.Context, b booking.Booking) (Invoice, bool, error) {
+	if !b.Paid {
+		return Invoice{}, false, nil
+	}
+	payments, _, err := s.payments.GetBookingPayments(ctx, b.ID, params.PaginationQueryParams{Paginate: false})
+	if err != nil {
+		return Invoice{}, false, err
+	}
+	c, err := s.customers.GetSingleCustomer(ctx, b.CustomerID)
+	if err != nil && !errors.Is(err, customer.ErrNotFound) {
+		return Invoice{}, false, err
+	}
+	invoice, ok := NewInvoice(b, c, payments)
+	return invoice, ok, nil
+}
+
+func (s *Service) lock(tenantID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[tenantID] {
+		return false
+	}
+	s.running[tenantID] = true
+	return true
+}
+
+func (s *Service) unlock(tenantID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, tenantID)
+}
+
+// backoff returns the delay before the retry following the attempt, doubling from the initial backoff.
+func backoff(attempt int) time.Duration {
+	delay := DefaultInitialBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= DefaultMaxBackoff {
+			return DefaultMaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,305 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/booking"
+	"github.com/simply-alliv/tigris-go-explore/customer"
+	"github.com/simply-alliv/tigris-go-explore/payment"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+const (
+	testAccessToken = "xero-access-token"
+	testTenantID    = "tenant-1"
+)
+
+// memoryRepository is a Repository keeping the syncs in memory, for the tests.
+type memoryRepository struct {
+	mu    sync.Mutex
+	syncs map[string]InvoiceSync
+}
+
+func (r *memoryRepository) GetTenantSyncs(ctx context.Context, tenantID string, qp params.PaginationQueryParams) ([]InvoiceSync, *pagination.PaginationData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	syncs := []InvoiceSync{}
+	for _, sync := range r.syncs {
+		if sync.TenantID == tenantID {
+			syncs = append(syncs, sync)
+		}
+	}
+	return syncs, nil, nil
+}
+
+func (r *memoryRepository) GetSingleSync(ctx context.Context, tenantID string, bookingID uuid.UUID) (InvoiceSync, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sync, ok := r.syncs[syncID(tenantID, bookingID)]
+	if !ok {
+		return InvoiceSync{}, ErrNotFound
+	}
+	return sync, nil
+}
+
+func (r *memoryRepository) SaveSingleSync(ctx context.Context, sync InvoiceSync) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.syncs[sync.ID] = sync
+	return nil
+}
+
+// books is a BookingReader, PaymentReader and CustomerReader keeping paid bookings in memory.
+type books struct {
+	bookings []booking.Booking
+	payments map[uuid.UUID][]payment.Payment
+	customer customer.Customer
+}
+
+func newBooks() *books {
+	return &books{
+		payments: map[uuid.UUID][]payment.Payment{},
+		customer: customer.Customer{ID: uuid.New(), Name: "Jane Doe", Email: "jane.doe@example.com"},
+	}
+}
+
+// add adds a booking paid 5000 NGN.
+func (b *books) add() booking.Booking {
+	bk := booking.Booking{
+		ID:            uuid.New(),
+		CustomerID:    b.customer.ID,
+		BreedID:       "affenpinscher",
+		BookedFor:     time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC),
+		BookedForTime: "14:30",
+		Duration:      60,
+		State:         booking.StatePaid,
+		Paid:          true,
+	}
+	b.bookings = append(b.bookings, bk)
+	b.payments[bk.ID] = []payment.Payment{{
+		Reference: uuid.NewString(),
+		BookingID: bk.ID,
+		Amount:    500000,
+		Currency:  "NGN",
+		Status:    payment.StatusSuccess,
+		PaidAt:    time.Date(2023, 1, 4, 10, 0, 0, 0, time.UTC),
+	}}
+	return bk
+}
+
+func (b *books) GetAllBookings(ctx context.Context, qp params.PaginationQueryParams, bqp params.BookingQueryParams) ([]booking.Booking, *pagination.PaginationData, error) {
+	return b.bookings, nil, nil
+}
+
+func (b *books) GetSingleBooking(ctx context.Context, id uuid.UUID) (booking.Booking, error) {
+	for _, bk := range b.bookings {
+		if bk.ID == id {
+			return bk, nil
+		}
+	}
+	return booking.Booking{}, booking.ErrNotFound
+}
+
+func (b *books) GetBookingPayments(ctx context.Context, bookingID uuid.UUID, qp params.PaginationQueryParams) ([]payment.Payment, *pagination.PaginationData, error) {
+	return b.payments[bookingID], nil, nil
+}
+
+func (b *books) GetSingleCustomer(ctx context.Context, id uuid.UUID) (customer.Customer, error) {
+	return b.customer, nil
+}
+
+// newService returns an accounting service of the books, exporting to a XeroStub.
+func newService(t *testing.T, b *books, opts ...Option) (*Service, *XeroStub) {
+	t.Helper()
+	stub := NewXeroStub(testAccessToken)
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+
+	repo := &memoryRepository{syncs: map[string]InvoiceSync{}}
+	return NewAccountingService(repo, NewXero(testAccessToken, WithXeroBaseURL(srv.URL)), b, b, b, opts...), stub
+}
+
+func syncTenant(t *testing.T, s *Service) SyncReport {
+	t.Helper()
+	report, err := s.SyncTenant(context.Background(), params.XeroQueryParams{XeroTenantId: testTenantID})
+	if err != nil {
+		t.Fatalf("SyncTenant() = %v", err)
+	}
+	return report
+}
+
+func syncOf(t *testing.T, s *Service, bookingID uuid.UUID) InvoiceSync {
+	t.Helper()
+	sync, err := s.r.GetSingleSync(context.Background(), testTenantID, bookingID)
+	if err != nil {
+		t.Fatalf("GetSingleSync() = %v", err)
+	}
+	return sync
+}
+
+// save saves the sync as is, e.g. to make its retry due.
+func save(t *testing.T, s *Service, sync InvoiceSync) {
+	t.Helper()
+	if err := s.r.SaveSingleSync(context.Background(), sync); err != nil {
+		t.Fatalf("SaveSingleSync() = %v", err)
+	}
+}
+
+func TestSyncTenantExportsInvoices(t *testing.T) {
+	b := newBooks()
+	s, stub := newService(t, b)
+	first, second := b.add(), b.add()
+
+	if report := syncTenant(t, s); report.Exported != 2 || report.Failed != 0 {
+		t.Fatalf("SyncTenant() = %+v, want 2 exported", report)
+	}
+	invoices := stub.Invoices(testTenantID)
+	for _, bk := range []booking.Booking{first, second} {
+		sync := syncOf(t, s, bk.ID)
+		if sync.Status != SyncStatusSynced || sync.Attempts != 1 {
+			t.Errorf("sync = %+v, want synced at the first attempt", sync)
+		}
+		if invoices[sync.ExternalID] != "BK-"+bk.ID.String() {
+			t.Errorf("Xero invoice %s = %q, want the invoice of booking %s", sync.ExternalID, invoices[sync.ExternalID], bk.ID)
+		}
+	}
+
+	// The unchanged invoices aren't exported again
+	if report := syncTenant(t, s); report.Skipped != 2 || report.Exported != 0 {
+		t.Errorf("second SyncTenant() = %+v, want 2 skipped", report)
+	}
+	if got := len(stub.Invoices(testTenantID)); got != 2 {
+		t.Errorf("%d Xero invoices, want 2", got)
+	}
+}
+
+func TestSyncTenantUpdatesExportedInvoices(t *testing.T) {
+	b := newBooks()
+	s, stub := newService(t, b)
+	bk := b.add()
+	syncTenant(t, s)
+	exported := syncOf(t, s, bk.ID)
+
+	// A refund changes the invoice, which is updated in place by its external ID
+	b.payments[bk.ID][0].RefundedAmount = 200000
+	if report := syncTenant(t, s); report.Exported != 1 {
+		t.Fatalf("SyncTenant() = %+v, want 1 exported", report)
+	}
+	updated := syncOf(t, s, bk.ID)
+	if updated.ExternalID != exported.ExternalID || updated.Fingerprint == exported.Fingerprint {
+		t.Errorf("sync = %+v, want the same Xero invoice with a new fingerprint", updated)
+	}
+	if got := len(stub.Invoices(testTenantID)); got != 1 {
+		t.Errorf("%d Xero invoices, want 1", got)
+	}
+
+	// An invoice whose external ID was lost is found by its number rather than created again
+	b.payments[bk.ID][0].RefundedAmount = 500000
+	lost := syncOf(t, s, bk.ID)
+	lost.ExternalID = ""
+	save(t, s, lost)
+	syncTenant(t, s)
+	if found := syncOf(t, s, bk.ID); found.ExternalID != exported.ExternalID || found.Status != SyncStatusSynced {
+		t.Errorf("sync = %+v, want the Xero invoice %s found again", found, exported.ExternalID)
+	}
+	if got := len(stub.Invoices(testTenantID)); got != 1 {
+		t.Errorf("%d Xero invoices, want 1", got)
+	}
+}
+
+func TestSyncTenantRetriesWithBackoff(t *testing.T) {
+	b := newBooks()
+	s, stub := newService(t, b)
+	bk := b.add()
+	stub.Fail(1)
+
+	begin := time.Now().UTC()
+	if report := syncTenant(t, s); report.Failed != 1 {
+		t.Fatalf("SyncTenant() = %+v, want 1 failed", report)
+	}
+	failed := syncOf(t, s, bk.ID)
+	if failed.Status != SyncStatusFailed || failed.Attempts != 1 || failed.LastError == "" {
+		t.Errorf("sync = %+v, want failed at the first attempt", failed)
+	}
+	if failed.NextAttemptAt.Before(begin.Add(DefaultInitialBackoff)) {
+		t.Errorf("next attempt at %v, want after the %v backoff", failed.NextAttemptAt, DefaultInitialBackoff)
+	}
+
+	// Not retried before its backoff elapsed
+	if report := syncTenant(t, s); report.Skipped != 1 {
+		t.Errorf("SyncTenant() before the backoff = %+v, want 1 skipped", report)
+	}
+
+	failed.NextAttemptAt = time.Now().Add(-time.Second)
+	save(t, s, failed)
+	if report := syncTenant(t, s); report.Exported != 1 {
+		t.Fatalf("SyncTenant() once due = %+v, want 1 exported", report)
+	}
+	if synced := syncOf(t, s, bk.ID); synced.Status != SyncStatusSynced || synced.Attempts != 2 || synced.LastError != "" {
+		t.Errorf("sync = %+v, want synced at the second attempt", synced)
+	}
+}
+
+func TestSyncTenantAbandons(t *testing.T) {
+	b := newBooks()
+	s, stub := newService(t, b, WithMaxAttempts(2))
+	bk := b.add()
+	stub.Fail(100)
+
+	syncTenant(t, s)
+	failed := syncOf(t, s, bk.ID)
+	failed.NextAttemptAt = time.Now().Add(-time.Second)
+	save(t, s, failed)
+	syncTenant(t, s)
+	abandoned := syncOf(t, s, bk.ID)
+	if abandoned.Status != SyncStatusAbandoned || abandoned.Attempts != 2 {
+		t.Fatalf("sync = %+v, want abandoned after 2 attempts", abandoned)
+	}
+
+	// An abandoned sync is only retried on request, or once its invoice changes
+	if report := syncTenant(t, s); report.Skipped != 1 {
+		t.Errorf("SyncTenant() = %+v, want the abandoned sync skipped", report)
+	}
+	stub.Fail(0)
+	retried, err := s.RetrySync(context.Background(), params.XeroQueryParams{XeroTenantId: testTenantID}, bk.ID)
+	if err != nil {
+		t.Fatalf("RetrySync() = %v", err)
+	}
+	if retried.Status != SyncStatusSynced || retried.Attempts != 1 {
+		t.Errorf("retried sync = %+v, want synced with a fresh set of attempts", retried)
+	}
+}
+
+func TestRetrySyncOfUnpaidBooking(t *testing.T) {
+	s, _ := newService(t, newBooks())
+	_, err := s.RetrySync(context.Background(), params.XeroQueryParams{XeroTenantId: testTenantID}, uuid.New())
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("RetrySync() = %v, want ErrNotFound", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Minute},
+		{attempt: 2, want: 2 * time.Minute},
+		{attempt: 3, want: 4 * time.Minute},
+		{attempt: 9, want: 256 * time.Minute},
+		{attempt: 10, want: DefaultMaxBackoff},
+		{attempt: 30, want: DefaultMaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
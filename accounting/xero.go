@@ -0,0 +1,220 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// XeroBaseURL is the base URL of the Xero accounting API.
+	XeroBaseURL = "https://api.xero.com/api.xro/2.0"
+	// XeroTenantHeader is the header selecting the Xero organisation the requests are made for.
+	XeroTenantHeader = "Xero-tenant-id"
+	// DefaultXeroAccountCode is the sales account the line items are booked to by default.
+	DefaultXeroAccountCode = "200"
+)
+
+// XeroOption configures the Xero exporter.
+type XeroOption func(*Xero)
+
+// WithXeroBaseURL sets the base URL of the API, e.g. the URL of a XeroStub.
+func WithXeroBaseURL(baseURL string) XeroOption {
+	return func(x *Xero) {
+		x.baseURL = baseURL
+	}
+}
+
+// WithXeroAccountCode sets the account the line items are booked to.
+func WithXeroAccountCode(code string) XeroOption {
+	return func(x *Xero) {
+		x.accountCode = code
+	}
+}
+
+// Xero is the Exporter creating the invoices as authorised sales invoices (ACCREC) in Xero, or in any API shaped like it.
+// The tenant IDs are the IDs of the Xero organisations.
+type Xero struct {
+	accessToken string
+	baseURL     string
+	accountCode string
+	client      *http.Client
+}
+
+// NewXero returns the Xero Exporter authenticating with the OAuth 2.0 access token.
+func NewXero(accessToken string, opts ...XeroOption) *Xero {
+	x := &Xero{
+		accessToken: accessToken,
+		baseURL:     XeroBaseURL,
+		accountCode: DefaultXeroAccountCode,
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(x)
+	}
+	return x
+}
+
+// xeroInvoice is an invoice, as sent to and returned by the API.
+type xeroInvoice struct {
+	InvoiceID       string         `json:"InvoiceID,omitempty"`
+	Type            string         `json:"Type"`
+	InvoiceNumber   string         `json:"InvoiceNumber"`
+	Reference       string         `json:"Reference,omitempty"`
+	Contact         xeroContact    `json:"Contact"`
+	Date            string         `json:"Date"`
+	DueDate         string         `json:"DueDate"`
+	CurrencyCode    string         `json:"CurrencyCode"`
+	Status          string         `json:"Status"`
+	LineAmountTypes string         `json:"LineAmountTypes"`
+	LineItems       []xeroLineItem `json:"LineItems"`
+}
+
+type xeroContact struct {
+	Name         string `json:"Name"`
+	EmailAddress string `json:"EmailAddress,omitempty"`
+}
+
+type xeroLineItem struct {
+	Description string  `json:"Description"`
+	Quantity    float64 `json:"Quantity"`
+	UnitAmount  float64 `json:"UnitAmount"`
+	AccountCode string  `json:"AccountCode"`
+}
+
+type xeroInvoices struct {
+	Invoices []xeroInvoice `json:"Invoices"`
+}
+
+// Export posts the invoice, keyed by its fingerprint so the retries of an export aren't applied twice. An invoice exported
+// before, whose external ID was lost, is found by its number and updated rather than created again.
+func (x *Xero) Export(ctx context.Context, tenantID string, invoice Invoice, externalID string) (string, error) {
+	if externalID == "" {
+		existing, err := x.find(ctx, tenantID, invoice.InvoiceNumber)
+		if err != nil {
+			return "", err
+		}
+		externalID = existing
+	}
+
+	date := invoice.Date.Format("2006-01-02")
+	xi := xeroInvoice{
+		InvoiceID:       externalID,
+		Type:            "ACCREC",
+		InvoiceNumber:   invoice.InvoiceNumber,
+		Reference:       invoice.Reference,
+		Contact:         xeroContact{Name: invoice.Contact.Name, EmailAddress: invoice.Contact.Email},
+		Date:            date,
+		DueDate:         date,
+		CurrencyCode:    invoice.Currency,
+		Status:          "AUTHORISED",
+		LineAmountTypes: "NoTax",
+	}
+	for _, item := range invoice.LineItems {
+		xi.LineItems = append(xi.LineItems, xeroLineItem{
+			Description: item.Description,
+			Quantity:    float64(item.Quantity),
+			UnitAmount:  float64(item.UnitAmount) / 100,
+			AccountCode: x.accountCode,
+		})
+	}
+
+	var out xeroInvoices
+	if err := x.do(ctx, http.MethodPost, "/Invoices", tenantID, invoice.Fingerprint(), xeroInvoices{Invoices: []xeroInvoice{xi}}, &out); err != nil {
+		return "", err
+	}
+	if len(out.Invoices) == 0 || out.Invoices[0].InvoiceID == "" {
+		return "", fmt.Errorf("xero: no invoice returned for %s", invoice.InvoiceNumber)
+	}
+	return out.Invoices[0].InvoiceID, nil
+}
+
+// find returns the ID of the invoice with the number, empty when there is none.
+func (x *Xero) find(ctx context.Context, tenantID, invoiceNumber string) (string, error) {
+	var out xeroInvoices
+	if err := x.do(ctx, http.MethodGet, "/Invoices?InvoiceNumbers="+url.QueryEscape(invoiceNumber), tenantID, "", nil, &out); err != nil {
+		return "", err
+	}
+	for _, xi := range out.Invoices {
+		if xi.InvoiceNumber == invoiceNumber {
+			return xi.InvoiceID, nil
+		}
+	}
+	return "", nil
+}
+
+func (x *Xero) do(ctx context.Context, method, path, tenantID, idempotencyKey string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, x.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+x.accessToken)
+	req.Header.Set(XeroTenantHeader, tenantID)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return &ExportError{StatusCode: resp.StatusCode, Message: xeroMessage(b)}
+	}
+	return json.Unmarshal(b, out)
+}
+
+// xeroMessage extracts the message of an error response, the validation errors being the most specific.
+func xeroMessage(body []byte) string {
+	var e struct {
+		Message  string `json:"Message"`
+		Detail   string `json:"Detail"`
+		Elements []struct {
+			ValidationErrors []struct {
+				Message string `json:"Message"`
+			} `json:"ValidationErrors"`
+		} `json:"Elements"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil {
+		return string(body)
+	}
+	for _, el := range e.Elements {
+		for _, v := range el.ValidationErrors {
+			return v.Message
+		}
+	}
+	if e.Detail != "" {
+		return e.Detail
+	}
+	return e.Message
+}
+
+// ExportError is an error returned by the accounting system's API.
+type ExportError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ExportError) Error() string {
+	return fmt.Sprintf("accounting export error (status %d): %s", e.StatusCode, e.Message)
+}
@@ -0,0 +1,147 @@
+package accounting
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// XeroStub is an http.Handler implementing the parts of the Xero accounting API used by the Xero exporter,
+// so the exports can be made without a Xero organisation, e.g. in tests and local development. It is served
+// at the root of a server, e.g. an httptest.Server, whose URL the Xero exporter is configured with.
+//
+// Like Xero, it rejects a second invoice with the same number, and replays the response of a request whose
+// Idempotency-Key it has already seen.
+type XeroStub struct {
+	accessToken string
+	mux         *http.ServeMux
+
+	mu       sync.Mutex
+	invoices map[string]map[string]xeroInvoice
+	replays  map[string]xeroInvoices
+	failures int
+}
+
+// NewXeroStub returns a XeroStub accepting the given access token.
+func NewXeroStub(accessToken string) *XeroStub {
+	x := &XeroStub{
+		accessToken: accessToken,
+		invoices:    map[string]map[string]xeroInvoice{},
+		replays:     map[string]xeroInvoices{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Invoices", x.handleInvoices)
+	x.mux = mux
+	return x
+}
+
+// ServeHTTP serves the Xero API.
+func (x *XeroStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	x.mux.ServeHTTP(w, r)
+}
+
+// Fail makes the next n requests fail with a 503 Service Unavailable, e.g. to exercise the retries.
+func (x *XeroStub) Fail(n int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.failures = n
+}
+
+// Invoices returns the numbers of the tenant's invoices, by invoice ID.
+func (x *XeroStub) Invoices(tenantID string) map[string]string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	numbers := map[string]string{}
+	for id, xi := range x.invoices[tenantID] {
+		numbers[id] = xi.InvoiceNumber
+	}
+	return numbers
+}
+
+func (x *XeroStub) handleInvoices(w http.ResponseWriter, r *http.Request) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	tenantID := r.Header.Get(XeroTenantHeader)
+	switch {
+	case r.Header.Get("Authorization") != "Bearer "+x.accessToken:
+		writeXero(w, http.StatusUnauthorized, map[string]interface{}{"Title": "Unauthorized", "Status": 401, "Detail": "AuthenticationUnsuccessful"})
+		return
+	case tenantID == "":
+		writeXero(w, http.StatusForbidden, map[string]interface{}{"Title": "Forbidden", "Status": 403, "Detail": "AuthorizationUnsuccessful"})
+		return
+	case x.failures > 0:
+		x.failures--
+		writeXero(w, http.StatusServiceUnavailable, map[string]interface{}{"Message": "The Xero API is temporarily unavailable"})
+		return
+	}
+	invoices := x.invoices[tenantID]
+	if invoices == nil {
+		invoices = map[string]xeroInvoice{}
+		x.invoices[tenantID] = invoices
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		numbers := strings.Split(r.URL.Query().Get("InvoiceNumbers"), ",")
+		out := xeroInvoices{Invoices: []xeroInvoice{}}
+		for _, xi := range invoices {
+			for _, n := range numbers {
+				if xi.InvoiceNumber == n {
+					out.Invoices = append(out.Invoices, xi)
+				}
+			}
+		}
+		writeXero(w, http.StatusOK, out)
+	case http.MethodPost:
+		key := tenantID + "|" + r.Header.Get("Idempotency-Key")
+		if out, ok := x.replays[key]; ok && r.Header.Get("Idempotency-Key") != "" {
+			writeXero(w, http.StatusOK, out)
+			return
+		}
+		var in xeroInvoices
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeXeroValidation(w, "The request body is invalid")
+			return
+		}
+		out := xeroInvoices{Invoices: []xeroInvoice{}}
+		for _, xi := range in.Invoices {
+			if xi.InvoiceID == "" {
+				for _, existing := range invoices {
+					if existing.InvoiceNumber == xi.InvoiceNumber {
+						writeXeroValidation(w, "Invoice # must be unique.")
+						return
+					}
+				}
+				xi.InvoiceID = uuid.NewString()
+			} else if _, ok := invoices[xi.InvoiceID]; !ok {
+				writeXeroValidation(w, "Invoice not found.")
+				return
+			}
+			invoices[xi.InvoiceID] = xi
+			out.Invoices = append(out.Invoices, xi)
+		}
+		x.replays[key] = out
+		writeXero(w, http.StatusOK, out)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func writeXeroValidation(w http.ResponseWriter, message string) {
+	writeXero(w, http.StatusBadRequest, map[string]interface{}{
+		"ErrorNumber": 10,
+		"Type":        "ValidationException",
+		"Message":     "A validation exception occurred",
+		"Elements":    []interface{}{map[string]interface{}{"ValidationErrors": []interface{}{map[string]string{"Message": message}}}},
+	})
+}
+
+func writeXero(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,132 @@
+package booking
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/resource"
+)
+
+var (
+	// ErrUnknownResource is returned when a booking is made on a resource which doesn't exist.
+	ErrUnknownResource = errors.New("booking resource not found")
+	// ErrSlotTaken is returned when a booking would exceed the capacity of its resource.
+	ErrSlotTaken = errors.New("booking time slot is already taken")
+	// ErrOutsideWorkingHours is returned when a booking isn't within the working hours of its resource.
+	ErrOutsideWorkingHours = errors.New("booking time slot is outside the resource's working hours")
+)
+
+// MaxAvailabilityDays is the maximum number of days the availability is computed for at once.
+const MaxAvailabilityDays = 31
+
+// Slot struct
+//
+// A slot is a time range a booking can be made for, Available being the number of bookings
+// the resource can still take over all of it.
+type Slot struct {
+	Start     time.Time `json:"start" example:"2023-01-05T09:00:00+01:00"`
+	End       time.Time `json:"end" example:"2023-01-05T10:00:00+01:00"`
+	Available int       `json:"available" example:"1"`
+}
+
+// occupying are the states in which a booking holds its time slot.
+var occupying = []string{StateCreated, StateApproved, StatePaid, StateStarted, StateRescheduled}
+
+// Occupies reports whether the booking holds its time slot.
+func (b Booking) Occupies() bool {
+	for _, state := range occupying {
+		if b.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Interval returns the [start, end) time range of the booking, in the location of its resource.
+func (b Booking) Interval(loc *time.Location) (time.Time, time.Time) {
+	start, err := resource.At(b.BookedFor, b.BookedForTime, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+	return start, start.Add(time.Duration(b.Duration) * time.Minute)
+}
+
+// checkSlot returns an error unless the booking fits within the working hours of the resource,
+// and the resource can take it besides the other bookings, the booking itself aside.
+func checkSlot(res resource.Resource, b Booking, bookings []Booking) error {
+	loc := res.Location()
+	start, end := b.Interval(loc)
+	if !res.Open(start, end) {
+		return ErrOutsideWorkingHours
+	}
+	if concurrent(bookings, loc, start, end, b.ID) >= res.Capacity {
+		return ErrSlotTaken
+	}
+	return nil
+}
+
+// concurrent returns the maximum number of the bookings holding the resource at the same time
+// within [start, end), the excluded booking aside.
+func concurrent(bookings []Booking, loc *time.Location, start, end time.Time, exclude uuid.UUID) int {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	var edges []edge
+	for _, b := range bookings {
+		if b.ID == exclude || !b.Occupies() {
+			continue
+		}
+		s, e := b.Interval(loc)
+		if !s.Before(end) || !e.After(start) {
+			continue
+		}
+		if s.Before(start) {
+			s = start
+		}
+		edges = append(edges, edge{s, 1}, edge{e, -1})
+	}
+	// Ends sort before starts at the same time, back-to-back bookings don't overlap.
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].at.Equal(edges[j].at) {
+			return edges[i].delta < edges[j].delta
+		}
+		return edges[i].at.Before(edges[j].at)
+	})
+	n, max := 0, 0
+	for _, e := range edges {
+		n += e.delta
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+// FreeSlots returns the slots of duration the resource can still take a booking for, from the from day
+// to the to day included, starting every slot interval within its working hours and not before now.
+// The excluded booking, e.g. the one being rescheduled, doesn't hold its slot.
+func FreeSlots(res resource.Resource, bookings []Booking, from, to time.Time, duration time.Duration, now time.Time, exclude uuid.UUID) []Slot {
+	loc := res.Location()
+	interval := time.Duration(res.SlotInterval) * time.Minute
+	if interval <= 0 {
+		interval = resource.DefaultSlotInterval * time.Minute
+	}
+	slots := []Slot{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		for _, w := range res.Windows(d) {
+			for start := w[0]; !start.Add(duration).After(w[1]); start = start.Add(interval) {
+				if start.Before(now) {
+					continue
+				}
+				end := start.Add(duration)
+				if available := res.Capacity - concurrent(bookings, loc, start, end, exclude); available > 0 {
+					slots = append(slots, Slot{Start: start, End: end, Available: available})
+				}
+			}
+		}
+	}
+	return slots
+}
@@ -0,0 +1,67 @@
+package booking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Booking struct
+//
+// A booking is a customer's appointment for a breed on a resource, on the BookedFor day at the BookedForTime
+// time of day, in the resource's time zone, for Duration minutes. Its State only changes through the transitions of the state machine, see state.go,
+// which are recorded in its History. Amount is the price of the booking in the Currency's minor unit, set from the resource's
+// hourly rate when the booking is made, so later changes of the rate don't change what is due.
+type Booking struct {
+	ID            uuid.UUID    `json:"id" tigris:"primaryKey:1" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	CustomerID    uuid.UUID    `json:"customerId" tigris:"index" example:"0b6f3f5e-2a6e-4c4e-9d3b-1b1e6a7e4c4e"`
+	BreedID       string       `json:"breedId" tigris:"index" example:"affenpinscher"`
+	ResourceID    uuid.UUID    `json:"resourceId" tigris:"index" example:"7a1c2d3e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	BookedFor     time.Time    `json:"bookedFor" tigris:"index" example:"2023-01-05T00:00:00.000Z"`
+	BookedForTime string       `json:"bookedForTime" tigris:"index" example:"14:30"`
+	Duration      int          `json:"duration" example:"60"`
+	Amount        int64        `json:"amount" example:"500000"`
+	Currency      string       `json:"currency" example:"NGN"`
+	State         string       `json:"state" tigris:"index" example:"created"`
+	Approved      bool         `json:"approved" tigris:"index" example:"false"`
+	Paid          bool         `json:"paid" tigris:"index" example:"false"`
+	Notes         string       `json:"notes" example:"First visit"`
+	History       []Transition `json:"history"`
+	CreatedAt     time.Time    `json:"createdAt" tigris:"index" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt     time.Time    `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// Transition struct
+//
+// A transition is a change of state of a booking, made by one of the actions.
+type Transition struct {
+	Action string    `json:"action" example:"approve"`
+	From   string    `json:"from" example:"created"`
+	To     string    `json:"to" example:"approved"`
+	Reason string    `json:"reason,omitempty" example:"Customer asked to come later"`
+	At     time.Time `json:"at" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// CreateBooking struct
+type CreateBooking struct {
+	CustomerID    uuid.UUID `json:"customerId" validate:"required" example:"0b6f3f5e-2a6e-4c4e-9d3b-1b1e6a7e4c4e"`
+	BreedID       string    `json:"breedId" validate:"required" example:"affenpinscher"`
+	ResourceID    uuid.UUID `json:"resourceId" validate:"required" example:"7a1c2d3e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	BookedFor     string    `json:"bookedFor" validate:"required,datetime=2006-01-02" example:"2023-01-05"`
+	BookedForTime string    `json:"bookedForTime" validate:"required,datetime=15:04" example:"14:30"`
+	Duration      int       `json:"duration" validate:"required,min=5,max=1440" example:"60"`
+	Notes         string    `json:"notes" validate:"omitempty,max=500" example:"First visit"`
+	CreatedAt     time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt     time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// TransitionBooking struct
+//
+// The body of the transition endpoints, the new day and time being required to reschedule a booking.
+type TransitionBooking struct {
+	BookedFor     string    `json:"bookedFor" validate:"required_if=Action reschedule,omitempty,datetime=2006-01-02" example:"2023-01-12"`
+	BookedForTime string    `json:"bookedForTime" validate:"required_if=Action reschedule,omitempty,datetime=15:04" example:"10:00"`
+	Reason        string    `json:"reason" validate:"omitempty,max=500" example:"Customer asked to come later"`
+	Action        string    `json:"-"`
+	UpdatedAt     time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
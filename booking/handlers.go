@@ -0,0 +1,381 @@
+package booking
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+func GetAllBookings(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qp := paginationQueryParams(r)
+		bqp, err := bookingQueryParams(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /bookings - PaginationQueryParams: %+v - BookingQueryParams: %+v - Took: %v\n", qp, bqp, time.Since(begin))
+		}(time.Now())
+		data, metadata, err := s.GetAllBookings(r.Context(), qp, bqp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data, Metadata: metadata})
+	}
+}
+
+func GetSingleBooking(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := bookingID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /bookings/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.GetSingleBooking(r.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{
+			Status:   http.StatusOK,
+			Message:  "success",
+			Data:     data,
+			Metadata: map[string][]string{"actions": Actions(data.State)},
+		})
+	}
+}
+
+func CreateSingleBooking(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var dto CreateBooking
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+		// Set default timestamps
+		now := time.Now().UTC()
+		dto.CreatedAt = now
+		dto.UpdatedAt = now
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /bookings - CreateBookingDTO: %+v - Took: %v\n", dto, time.Since(begin))
+		}(time.Now())
+		data, err := s.CreateSingleBooking(r.Context(), dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusCreated, Message: "success", Data: data})
+	}
+}
+
+// TransitionSingleBooking returns the handler applying the action to a booking, e.g. `POST /bookings/{id}/approve`.
+func TransitionSingleBooking(s *Service, action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := bookingID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		dto, err := transitionBooking(r, action)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /bookings/%s/%s - Took: %v\n", id, action, time.Since(begin))
+		}(time.Now())
+		data, err := s.TransitionSingleBooking(r.Context(), id, action, dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{
+			Status:   http.StatusOK,
+			Message:  "success",
+			Data:     data,
+			Metadata: map[string][]string{"actions": Actions(data.State)},
+		})
+	}
+}
+
+func DeleteSingleBooking(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := bookingID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		dto, err := transitionBooking(r, ActionDelete)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("DELETE /bookings/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		if err := s.DeleteSingleBooking(r.Context(), id, dto); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success"})
+	}
+}
+
+func GetAvailability(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		resourceID, err := uuid.Parse(q.Get("resource"))
+		if err != nil {
+			writeError(w, &queryParamError{fmt.Sprintf("invalid resource query param %q: must be a UUID", q.Get("resource"))})
+			return
+		}
+		from, err := parseDay(q.Get("from"))
+		if err != nil {
+			writeError(w, &queryParamError{fmt.Sprintf("invalid from query param %q: must be a YYYY-MM-DD date", q.Get("from"))})
+			return
+		}
+		to := from
+		if v := q.Get("to"); v != "" {
+			to, err = parseDay(v)
+			if err != nil || to.Before(from) {
+				writeError(w, &queryParamError{fmt.Sprintf("invalid to query param %q: must be a YYYY-MM-DD date, not before from", v)})
+				return
+			}
+		}
+		if to.Sub(from) >= MaxAvailabilityDays*24*time.Hour {
+			writeError(w, &queryParamError{fmt.Sprintf("invalid to query param: the availability is computed for at most %d days", MaxAvailabilityDays)})
+			return
+		}
+		duration, err := parseDuration(q.Get("duration"))
+		if err != nil {
+			writeError(w, &queryParamError{fmt.Sprintf("invalid duration query param %q: must be a number of minutes, or a duration like 1h30m", q.Get("duration"))})
+			return
+		}
+		var exclude uuid.UUID
+		if v := q.Get("booking"); v != "" {
+			exclude, err = uuid.Parse(v)
+			if err != nil {
+				writeError(w, &queryParamError{fmt.Sprintf("invalid booking query param %q: must be a UUID", v)})
+				return
+			}
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /availability - Resource: %s - From: %s - To: %s - Duration: %v - Took: %v\n", resourceID, from.Format("2006-01-02"), to.Format("2006-01-02"), duration, time.Since(begin))
+		}(time.Now())
+		data, err := s.GetAvailability(r.Context(), resourceID, from, to, duration, exclude)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+// parseDuration parses a slot duration, given in minutes or as a Go duration, between 5 minutes and a day.
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		minutes, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, err
+		}
+		d = time.Duration(minutes) * time.Minute
+	}
+	if d < 5*time.Minute || d > 24*time.Hour {
+		return 0, errors.New("duration out of range")
+	}
+	return d, nil
+}
+
+// bookingID parses the `id` path variable, an invalid ID being reported as not found.
+func bookingID(r *http.Request) (uuid.UUID, error) {
+	v, ok := mux.Vars(r)["id"]
+	if !ok {
+		panic(ErrBadRouting)
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return uuid.Nil, ErrNotFound
+	}
+	return id, nil
+}
+
+// transitionBooking reads and validates the optional body of a transition.
+func transitionBooking(r *http.Request, action string) (TransitionBooking, error) {
+	var dto TransitionBooking
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil && err != io.EOF {
+		return dto, &queryParamError{"invalid request body"}
+	}
+	dto.Action = action
+	dto.UpdatedAt = time.Now().UTC()
+	return dto, validation.Struct(dto)
+}
+
+// paginationQueryParams reads the pagination params from the request's query string,
+// falling back to the defaults for missing or invalid values.
+func paginationQueryParams(r *http.Request) params.PaginationQueryParams {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	paginate, err := strconv.ParseBool(r.URL.Query().Get("paginate"))
+	if err != nil {
+		paginate = true
+	}
+	return params.PaginationQueryParams{
+		Page:     page,
+		Limit:    limit,
+		Paginate: paginate,
+	}
+}
+
+// bookingQueryParams reads and validates the booking filters and orders from the request's query string.
+// The dates are YYYY-MM-DD days or RFC 3339 timestamps.
+func bookingQueryParams(r *http.Request) (params.BookingQueryParams, error) {
+	var bqp params.BookingQueryParams
+	q := r.URL.Query()
+	if v := q.Get("bookingState"); v != "" {
+		bqp.BookingState = &v
+	}
+	for _, p := range []struct {
+		key   string
+		value **time.Time
+	}{
+		{"fromDate", &bqp.FromDate},
+		{"toDate", &bqp.ToDate},
+	} {
+		v := q.Get(p.key)
+		if v == "" {
+			continue
+		}
+		t, err := parseDay(v)
+		if err != nil {
+			t, err = time.Parse(time.RFC3339, v)
+		}
+		if err != nil {
+			return bqp, &queryParamError{fmt.Sprintf("invalid %s query param %q: must be a YYYY-MM-DD date", p.key, v)}
+		}
+		*p.value = &t
+	}
+	for _, p := range []struct {
+		key   string
+		value **bool
+	}{
+		{"approved", &bqp.Approved},
+		{"paid", &bqp.Paid},
+	} {
+		v := q.Get(p.key)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return bqp, &queryParamError{fmt.Sprintf("invalid %s query param %q: must be a boolean", p.key, v)}
+		}
+		*p.value = &b
+	}
+	for _, p := range []struct {
+		key   string
+		value **int
+	}{
+		{"bookedForOrder", &bqp.BookedForOrder},
+		{"bookedForTimeOrder", &bqp.BookedForTimeOrder},
+		{"createdAtOrder", &bqp.CreatedAtOrder},
+	} {
+		v := q.Get(p.key)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return bqp, &queryParamError{fmt.Sprintf("invalid %s query param %q: must be 1 or -1", p.key, v)}
+		}
+		*p.value = &i
+	}
+	if bqp.FromDate != nil && bqp.ToDate != nil && bqp.ToDate.Before(*bqp.FromDate) {
+		return bqp, &queryParamError{"invalid toDate query param: must not be before fromDate"}
+	}
+	return bqp, validation.Struct(bqp)
+}
+
+// queryParamError is returned for a query param, or a body, which can't be parsed.
+type queryParamError struct {
+	message string
+}
+
+func (e *queryParamError) Error() string {
+	return e.message
+}
+
+// writeError maps the error to its HTTP status code and writes it as the response.
+func writeError(w http.ResponseWriter, err error) {
+	var qpErr *queryParamError
+	var status int
+	message := err.Error()
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrSlotTaken):
+		status = http.StatusConflict
+	case errors.Is(err, ErrUnknownCustomer), errors.Is(err, ErrUnknownBreed), errors.Is(err, ErrUnknownResource), errors.Is(err, ErrOutsideWorkingHours):
+		status = http.StatusUnprocessableEntity
+	case validation.IsValidationError(err), errors.As(err, &qpErr):
+		status = http.StatusUnprocessableEntity
+	default:
+		log.Printf("Unable to handle booking request: %+v\n", err)
+		status = http.StatusInternalServerError
+		message = "internal error"
+	}
+	writeResponse(w, Response{Status: status, Message: message})
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	// set the content type to application/json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
+
+	// encode the response struct as JSON and write it to the response writer
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		// handle the error
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,253 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/resource"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/sort"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+// ErrNotFound is returned when no booking has the given ID.
+var ErrNotFound = errors.New("booking not found")
+
+// Repository is responsible for managing the persistence layer of the bookings. (e.g. database operations)
+type Repository interface {
+	GetAllBookings(ctx context.Context, qp params.PaginationQueryParams, bqp params.BookingQueryParams) ([]Booking, *pagination.PaginationData, error)
+	GetSingleBooking(ctx context.Context, id uuid.UUID) (Booking, error)
+	GetResourceBookings(ctx context.Context, resourceID uuid.UUID, from, to time.Time) ([]Booking, error)
+	CreateSingleBooking(ctx context.Context, b Booking) (Booking, error)
+	TransitionSingleBooking(ctx context.Context, id uuid.UUID, action string, dto TransitionBooking) (Booking, error)
+}
+
+type bookingRepository struct {
+	db         *tigris.Database
+	collection *tigris.Collection[Booking]
+	resources  *tigris.Collection[resource.Resource]
+}
+
+// NewBookingRepository returns a concrete implementation of the Repository interface.
+func NewBookingRepository(db *tigris.Database) Repository {
+	return &bookingRepository{
+		db:         db,
+		collection: tigris.GetCollection[Booking](db),
+		resources:  tigris.GetCollection[resource.Resource](db),
+	}
+}
+
+func (r bookingRepository) GetAllBookings(ctx context.Context, qp params.PaginationQueryParams, bqp params.BookingQueryParams) ([]Booking, *pagination.PaginationData, error) {
+	var bookings []Booking = []Booking{}
+	f := bookingFilter(bqp)
+
+	// Add initial pagination data
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	c, err := r.collection.Count(ctx, f)
+	if err != nil {
+		return bookings, &m, err
+	}
+	if !qp.Paginate {
+		m.Page = 1
+		m.PerPage = c
+	}
+	options := tigris.ReadOptions{
+		Skip:  (m.Page - 1) * m.PerPage,
+		Limit: m.PerPage,
+		Sort:  bookingSort(bqp),
+	}
+	it, err := r.collection.ReadWithOptions(ctx, f, fields.All, &options)
+	if err != nil {
+		return bookings, &m, err
+	}
+	defer it.Close()
+
+	var booking Booking
+	for it.Next(&booking) {
+		bookings = append(bookings, booking)
+	}
+
+	m = pagination.New(m.Page, m.PerPage, c)
+
+	return bookings, &m, it.Err()
+}
+
+func (r bookingRepository) GetSingleBooking(ctx context.Context, id uuid.UUID) (Booking, error) {
+	booking, err := r.collection.ReadOne(ctx, filter.Eq("id", id))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return Booking{}, ErrNotFound
+	}
+	if err != nil {
+		return Booking{}, err
+	}
+	return *booking, nil
+}
+
+// GetResourceBookings returns the bookings holding a time slot of the resource between the from and to days,
+// and on the days around them, which may overlap them.
+func (r bookingRepository) GetResourceBookings(ctx context.Context, resourceID uuid.UUID, from, to time.Time) ([]Booking, error) {
+	var bookings []Booking = []Booking{}
+	var states []filter.Expr
+	for _, state := range occupying {
+		states = append(states, filter.Eq("state", state))
+	}
+	f := filter.And(
+		filter.Eq("resourceId", resourceID),
+		filter.Gte("bookedFor", day(from).AddDate(0, 0, -1)),
+		filter.Lte("bookedFor", day(to).AddDate(0, 0, 1)),
+		filter.Or(states...),
+	)
+	it, err := r.collection.Read(ctx, f)
+	if err != nil {
+		return bookings, err
+	}
+	defer it.Close()
+
+	var booking Booking
+	for it.Next(&booking) {
+		bookings = append(bookings, booking)
+	}
+	return bookings, it.Err()
+}
+
+// CreateSingleBooking inserts the booking once its resource is checked to be open and free for it,
+// in the same transaction so concurrent bookings can't both take the last free slot.
+func (r bookingRepository) CreateSingleBooking(ctx context.Context, b Booking) (Booking, error) {
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		if err := r.checkSlot(ctx, b); err != nil {
+			return err
+		}
+		_, err := r.collection.Insert(ctx, &b)
+		return err
+	}, tigris.TxOptions{AutoRetry: true})
+	if err != nil {
+		return Booking{}, err
+	}
+	return b, nil
+}
+
+// TransitionSingleBooking applies the action to the booking, reading and writing it in the same transaction
+// so concurrent transitions can't both be applied from the same state. A rescheduled booking is checked
+// to fit its new time slot like a new one.
+func (r bookingRepository) TransitionSingleBooking(ctx context.Context, id uuid.UUID, action string, dto TransitionBooking) (Booking, error) {
+	var updated Booking
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		b, err := r.GetSingleBooking(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := b.Apply(action, dto); err != nil {
+			return err
+		}
+		if action == ActionReschedule {
+			if err := r.checkSlot(ctx, b); err != nil {
+				return err
+			}
+		}
+		if _, err := r.collection.InsertOrReplace(ctx, &b); err != nil {
+			return err
+		}
+		updated = b
+		return nil
+	}, tigris.TxOptions{AutoRetry: true})
+	if err != nil {
+		return Booking{}, err
+	}
+	return updated, nil
+}
+
+// checkSlot reads the resource of the booking and its bookings around the booking's day,
+// and checks the booking fits, see checkSlot.
+func (r bookingRepository) checkSlot(ctx context.Context, b Booking) error {
+	res, err := r.resources.ReadOne(ctx, filter.Eq("id", b.ResourceID))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return ErrUnknownResource
+	}
+	if err != nil {
+		return err
+	}
+	bookings, err := r.GetResourceBookings(ctx, b.ResourceID, b.BookedFor, b.BookedFor)
+	if err != nil {
+		return err
+	}
+	return checkSlot(*res, b, bookings)
+}
+
+// bookingSort converts the order query params, 1 for ascending and -1 for descending, into a Tigris sort order.
+// The orders apply in the bookedFor, bookedForTime, createdAt precedence, and bookings are sorted by their
+// day and time by default.
+func bookingSort(bqp params.BookingQueryParams) sort.Order {
+	var order sort.Order
+	for _, o := range []struct {
+		field string
+		value *int
+	}{
+		{"bookedFor", bqp.BookedForOrder},
+		{"bookedForTime", bqp.BookedForTimeOrder},
+		{"createdAt", bqp.CreatedAtOrder},
+	} {
+		switch {
+		case o.value == nil:
+		case *o.value < 0:
+			order = order.Descending(o.field)
+		default:
+			order = order.Ascending(o.field)
+		}
+	}
+	if len(order) == 0 {
+		return sort.Ascending("bookedFor").Ascending("bookedForTime")
+	}
+	return order
+}
+
+// bookingFilter converts the booking query params into a Tigris filter.
+//
+// The fromDate and toDate params bound the day the bookings are for, both inclusive.
+// Deleted bookings are only listed when filtering on the deleted state.
+func bookingFilter(bqp params.BookingQueryParams) filter.Filter {
+	var ops []filter.Expr
+	if bqp.BookingState != nil && *bqp.BookingState != "" {
+		ops = append(ops, filter.Eq("state", *bqp.BookingState))
+	} else {
+		var visible []filter.Expr
+		for _, state := range []string{StateCreated, StateApproved, StatePaid, StateStarted, StateCompleted, StateCancelled, StateRescheduled} {
+			visible = append(visible, filter.Eq("state", state))
+		}
+		ops = append(ops, filter.Or(visible...))
+	}
+	if bqp.FromDate != nil {
+		ops = append(ops, filter.Gte("bookedFor", day(*bqp.FromDate)))
+	}
+	if bqp.ToDate != nil {
+		ops = append(ops, filter.Lte("bookedFor", day(*bqp.ToDate)))
+	}
+	if bqp.Approved != nil {
+		ops = append(ops, filter.Eq("approved", *bqp.Approved))
+	}
+	if bqp.Paid != nil {
+		ops = append(ops, filter.Eq("paid", *bqp.Paid))
+	}
+
+	if len(ops) == 1 {
+		return ops[0]
+	}
+	return filter.And(ops...)
+}
+
+// day truncates the time to its UTC day, the BookedFor of the bookings.
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// parseDay parses a YYYY-MM-DD day.
+func parseDay(s string) (time.Time, error) {
+	return time.Parse("2006-01-02", s)
+}
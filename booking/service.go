@@ -0,0 +1,211 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/breed"
+	"github.com/simply-alliv/tigris-go-explore/customer"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/resource"
+)
+
+var (
+	// ErrUnknownCustomer is returned when a booking is made for a customer which doesn't exist.
+	ErrUnknownCustomer = errors.New("booking customer not found")
+	// ErrUnknownBreed is returned when a booking is made for a breed which doesn't exist.
+	ErrUnknownBreed = errors.New("booking breed not found")
+)
+
+// CustomerReader reads the customers the bookings are made by.
+type CustomerReader interface {
+	GetSingleCustomer(ctx context.Context, id uuid.UUID) (customer.Customer, error)
+}
+
+// BreedReader reads the breeds the bookings are made for.
+type BreedReader interface {
+	GetSingleBreed(ctx context.Context, id string) (breed.Breed, error)
+}
+
+// ResourceReader reads the resources the bookings are made on.
+type ResourceReader interface {
+	GetSingleResource(ctx context.Context, id uuid.UUID) (resource.Resource, error)
+}
+
+type Service struct {
+	r         Repository
+	customers CustomerReader
+	breeds    BreedReader
+	resources ResourceReader
+}
+
+// NewBookingService returns a service
+func NewBookingService(r Repository, customers CustomerReader, breeds BreedReader, resources ResourceReader) *Service {
+	return &Service{r: r, customers: customers, breeds: breeds, resources: resources}
+}
+
+// GetAllBookings godoc
+// @Summary Get all booking resources
+// @Description Get the booking resources, filtered and ordered by the booking query params. Deleted bookings are only listed when filtering on the deleted state
+// @Security Bearer
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Success 200 {object} JSONResultSuccess{data=[]Booking} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /bookings [get]
+func (s *Service) GetAllBookings(ctx context.Context, qp params.PaginationQueryParams, bqp params.BookingQueryParams) ([]Booking, *pagination.PaginationData, error) {
+	return s.r.GetAllBookings(ctx, qp, bqp)
+}
+
+// GetSingleBooking godoc
+// @Summary Get single booking resource
+// @Description Get a single booking resource, with the actions allowed from its state
+// @Security Bearer
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the booking resource"
+// @Success 200 {object} JSONResultSuccess{data=Booking} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /bookings/{id} [get]
+func (s *Service) GetSingleBooking(ctx context.Context, id uuid.UUID) (Booking, error) {
+	return s.r.GetSingleBooking(ctx, id)
+}
+
+// CreateSingleBooking godoc
+// @Summary Create single booking resource
+// @Description Create a single booking resource in the created state, for an existing customer and breed, on a resource open and free for its whole duration
+// @Security Bearer
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param body body CreateBooking true "JSON body to create a booking resource, the breedId may be an alias"
+// @Success 201 {object} JSONResultSuccess{data=Booking} "Created"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /bookings [post]
+func (s *Service) CreateSingleBooking(ctx context.Context, dto CreateBooking) (Booking, error) {
+	if _, err := s.customers.GetSingleCustomer(ctx, dto.CustomerID); err != nil {
+		if errors.Is(err, customer.ErrNotFound) {
+			return Booking{}, ErrUnknownCustomer
+		}
+		return Booking{}, err
+	}
+	b, err := s.breeds.GetSingleBreed(ctx, dto.BreedID)
+	if err != nil {
+		if errors.Is(err, breed.ErrNotFound) {
+			return Booking{}, ErrUnknownBreed
+		}
+		return Booking{}, err
+	}
+	res, err := s.resources.GetSingleResource(ctx, dto.ResourceID)
+	if err != nil {
+		if errors.Is(err, resource.ErrNotFound) {
+			return Booking{}, ErrUnknownResource
+		}
+		return Booking{}, err
+	}
+	bookedFor, err := parseDay(dto.BookedFor)
+	if err != nil {
+		return Booking{}, err
+	}
+
+	return s.r.CreateSingleBooking(ctx, Booking{
+		ID:            uuid.New(),
+		CustomerID:    dto.CustomerID,
+		BreedID:       b.UniqeName,
+		ResourceID:    dto.ResourceID,
+		BookedFor:     bookedFor,
+		BookedForTime: dto.BookedForTime,
+		Duration:      dto.Duration,
+		Amount:        res.Price(dto.Duration),
+		Currency:      res.Currency,
+		State:         StateCreated,
+		Notes:         dto.Notes,
+		History:       []Transition{},
+		CreatedAt:     dto.CreatedAt,
+		UpdatedAt:     dto.UpdatedAt,
+	})
+}
+
+// TransitionSingleBooking godoc
+// @Summary Change the state of a single booking
+// @Description Apply an action (approve, pay, start, complete, cancel or reschedule) to a single booking, rescheduling requires the new bookedFor and bookedForTime, which must be open and free on the booking's resource
+// @Security Bearer
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the booking"
+// @Param action path string true "Action to apply to the booking"
+// @Param body body TransitionBooking false "JSON body with the reason of the transition"
+// @Success 200 {object} JSONResultSuccess{data=Booking} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /bookings/{id}/{action} [post]
+func (s *Service) TransitionSingleBooking(ctx context.Context, id uuid.UUID, action string, dto TransitionBooking) (Booking, error) {
+	return s.r.TransitionSingleBooking(ctx, id, action, dto)
+}
+
+// DeleteSingleBooking godoc
+// @Summary Delete single booking
+// @Description Soft-delete a single created, cancelled or completed booking, moving it to the deleted state
+// @Security Bearer
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the booking"
+// @Success 200 {object} JSONResultSuccess{} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /bookings/{id} [delete]
+func (s *Service) DeleteSingleBooking(ctx context.Context, id uuid.UUID, dto TransitionBooking) error {
+	_, err := s.r.TransitionSingleBooking(ctx, id, ActionDelete, dto)
+	return err
+}
+
+// GetAvailability godoc
+// @Summary Get the free time slots of a resource
+// @Description Get the slots of the given duration a resource can still take a booking for, between two days included. The booking being rescheduled can be excluded, so its own slot is offered
+// @Security Bearer
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param resource query string true "ID of the resource"
+// @Param from query string true "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD, defaults to the first day"
+// @Param duration query string true "Duration of the slots, in minutes or as a Go duration (e.g. 1h30m)"
+// @Param booking query string false "ID of a booking to ignore, e.g. the one being rescheduled"
+// @Success 200 {object} JSONResultSuccess{data=[]Slot} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /availability [get]
+func (s *Service) GetAvailability(ctx context.Context, resourceID uuid.UUID, from, to time.Time, duration time.Duration, exclude uuid.UUID) ([]Slot, error) {
+	res, err := s.resources.GetSingleResource(ctx, resourceID)
+	if err != nil {
+		if errors.Is(err, resource.ErrNotFound) {
+			return nil, ErrUnknownResource
+		}
+		return nil, err
+	}
+	bookings, err := s.r.GetResourceBookings(ctx, resourceID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return FreeSlots(res, bookings, day(from), day(to), duration, time.Now(), exclude), nil
+}
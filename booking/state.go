@@ -0,0 +1,101 @@
+package booking
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The states of a booking.
+const (
+	StateCreated     = "created"
+	StateApproved    = "approved"
+	StatePaid        = "paid"
+	StateStarted     = "started"
+	StateCompleted   = "completed"
+	StateCancelled   = "cancelled"
+	StateDeleted     = "deleted"
+	StateRescheduled = "rescheduled"
+)
+
+// The actions changing the state of a booking.
+const (
+	ActionApprove    = "approve"
+	ActionPay        = "pay"
+	ActionStart      = "start"
+	ActionComplete   = "complete"
+	ActionCancel     = "cancel"
+	ActionReschedule = "reschedule"
+	ActionDelete     = "delete"
+)
+
+// ErrInvalidTransition is returned when an action isn't allowed from the state of a booking.
+var ErrInvalidTransition = errors.New("booking state transition is not allowed")
+
+// transitions is the state-transition graph of the bookings: the states each action is allowed from,
+// and the state it leads to.
+var transitions = map[string]struct {
+	from []string
+	to   string
+}{
+	ActionApprove:    {from: []string{StateCreated, StateRescheduled}, to: StateApproved},
+	ActionPay:        {from: []string{StateApproved}, to: StatePaid},
+	ActionStart:      {from: []string{StatePaid}, to: StateStarted},
+	ActionComplete:   {from: []string{StateStarted}, to: StateCompleted},
+	ActionCancel:     {from: []string{StateCreated, StateApproved, StatePaid, StateRescheduled}, to: StateCancelled},
+	ActionReschedule: {from: []string{StateCreated, StateApproved, StatePaid, StateRescheduled}, to: StateRescheduled},
+	ActionDelete:     {from: []string{StateCreated, StateCancelled, StateCompleted}, to: StateDeleted},
+}
+
+// Actions returns the actions allowed from the state.
+func Actions(state string) []string {
+	var actions []string
+	for _, action := range []string{ActionApprove, ActionPay, ActionStart, ActionComplete, ActionCancel, ActionReschedule, ActionDelete} {
+		for _, from := range transitions[action].from {
+			if from == state {
+				actions = append(actions, action)
+			}
+		}
+	}
+	return actions
+}
+
+// Apply applies the action to the booking, changing its state and flags and recording the transition.
+//
+// A rescheduled booking has to be approved again, and goes back to paid rather than approved
+// when it was already paid.
+func (b *Booking) Apply(action string, dto TransitionBooking) error {
+	t, ok := transitions[action]
+	if !ok {
+		return fmt.Errorf("unknown booking action %q", action)
+	}
+	allowed := false
+	for _, from := range t.from {
+		allowed = allowed || from == b.State
+	}
+	if !allowed {
+		return fmt.Errorf("%w: cannot %s a %s booking", ErrInvalidTransition, action, b.State)
+	}
+
+	to := t.to
+	switch action {
+	case ActionApprove:
+		b.Approved = true
+		if b.Paid {
+			to = StatePaid
+		}
+	case ActionPay:
+		b.Paid = true
+	case ActionReschedule:
+		bookedFor, err := parseDay(dto.BookedFor)
+		if err != nil {
+			return err
+		}
+		b.BookedFor = bookedFor
+		b.BookedForTime = dto.BookedForTime
+		b.Approved = false
+	}
+	b.History = append(b.History, Transition{Action: action, From: b.State, To: to, Reason: dto.Reason, At: dto.UpdatedAt})
+	b.State = to
+	b.UpdatedAt = dto.UpdatedAt
+	return nil
+}
@@ -0,0 +1,230 @@
+package breed
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/cache"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultCacheSize is the default number of breeds and breed lists cached.
+	DefaultCacheSize = 1000
+	// DefaultCacheTTL is the default time a breed or breed list is cached for.
+	DefaultCacheTTL = time.Minute
+	// sharedReadTimeout bounds the reads shared by concurrent misses, which no longer end with their request.
+	sharedReadTimeout = 30 * time.Second
+)
+
+// The key prefixes of the cached reads.
+const (
+	breedCacheKey        = "breed:"
+	listCacheKey         = "list:"
+	searchCacheKey       = "search:"
+	lastModifiedCacheKey = "lastModified"
+)
+
+// cached is the result of a cached read, either a single breed, a page of breeds or the last modification time.
+type cached struct {
+	breed        Breed
+	breeds       []Breed
+	meta         *pagination.PaginationData
+	lastModified time.Time
+}
+
+// CachingRepository is a read-through caching decorator of a Repository.
+//
+// Single breeds are cached by the ID they were read with, lists and searches by their normalized params,
+// all of them per organization since each sees its own breeds, and per sparse fieldset.
+// Writes going through the decorator invalidate the entries of the written breed and all the lists,
+// while concurrent misses of the same key share a single read of the underlying repository,
+// which isn't canceled when the request which started it is, each request only waiting for it until it's done.
+// Writes made by other processes are only seen once the entries expire.
+type CachingRepository struct {
+	r     Repository
+	lru   *cache.LRU[string, cached]
+	group singleflight.Group
+	// generation is incremented by every write, so reads started before it aren't cached after it.
+	generation uint64
+}
+
+// NewCachingRepository returns a caching decorator of the repository.
+func NewCachingRepository(r Repository, size int, ttl time.Duration) *CachingRepository {
+	return &CachingRepository{r: r, lru: cache.NewLRU[string, cached](size, ttl)}
+}
+
+// Stats returns the hit and miss statistics of the cache.
+func (c *CachingRepository) Stats() cache.Stats {
+	return c.lru.Stats()
+}
+
+// load returns the cached result for the key, reading it from the repository on a miss.
+// The read is given a context detached from the request's, keeping its organization and fields, which the key depends on.
+func (c *CachingRepository) load(ctx context.Context, key string, read func(ctx context.Context) (cached, error)) (cached, error) {
+	if v, ok := c.lru.Get(key); ok {
+		return v, nil
+	}
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(detachedContext{ctx}, sharedReadTimeout)
+		defer cancel()
+		generation := atomic.LoadUint64(&c.generation)
+		v, err := read(ctx)
+		if err != nil {
+			return cached{}, err
+		}
+		if atomic.LoadUint64(&c.generation) == generation {
+			c.lru.Add(key, v)
+		}
+		return v, nil
+	})
+	select {
+	case <-ctx.Done():
+		return cached{}, ctx.Err()
+	case res := <-ch:
+		return res.Val.(cached), res.Err
+	}
+}
+
+// detachedContext carries the values of its parent but neither its deadline nor its cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c *CachingRepository) List(ctx context.Context, qp params.PaginationQueryParams, q crud.Query) ([]Breed, *pagination.PaginationData, error) {
+	v, err := c.load(ctx, projected(ctx, listCacheKey)+listKey(qp, q), func(ctx context.Context) (cached, error) {
+		breeds, meta, err := c.r.List(ctx, qp, q)
+		return cached{breeds: breeds, meta: meta}, err
+	})
+	if err != nil {
+		return []Breed{}, nil, err
+	}
+	return copyBreeds(v.breeds), copyMeta(v.meta), nil
+}
+
+func (c *CachingRepository) Search(ctx context.Context, q string, qp params.PaginationQueryParams) ([]Breed, *pagination.PaginationData, error) {
+	key := projected(ctx, searchCacheKey) + strconv.Itoa(qp.Page) + ":" + strconv.Itoa(qp.Limit) + ":" + q
+	v, err := c.load(ctx, key, func(ctx context.Context) (cached, error) {
+		breeds, meta, err := c.r.Search(ctx, q, qp)
+		return cached{breeds: breeds, meta: meta}, err
+	})
+	if err != nil {
+		return []Breed{}, nil, err
+	}
+	return copyBreeds(v.breeds), copyMeta(v.meta), nil
+}
+
+func (c *CachingRepository) Get(ctx context.Context, id string) (Breed, error) {
+	v, err := c.load(ctx, projected(ctx, breedCacheKey)+id, func(ctx context.Context) (cached, error) {
+		b, err := c.r.Get(ctx, id)
+		return cached{breed: b}, err
+	})
+	return v.breed, err
+}
+
+func (c *CachingRepository) LastModified(ctx context.Context) (time.Time, error) {
+	v, err := c.load(ctx, scoped(ctx, lastModifiedCacheKey), func(ctx context.Context) (cached, error) {
+		t, err := c.r.LastModified(ctx)
+		return cached{lastModified: t}, err
+	})
+	return v.lastModified, err
+}
+
+// UniqueNameTaken isn't cached, as it guards the creation of new breeds.
+func (c *CachingRepository) UniqueNameTaken(ctx context.Context, uniqueName string) (bool, error) {
+	return c.r.UniqueNameTaken(ctx, uniqueName)
+}
+
+func (c *CachingRepository) Create(ctx context.Context, dto CreateBreed) (Breed, error) {
+	defer c.invalidate(dto.UniqeName)
+	return c.r.Create(ctx, dto)
+}
+
+func (c *CachingRepository) Update(ctx context.Context, id string, dto UpdateBreed) (Breed, error) {
+	b, err := c.r.Update(ctx, id, dto)
+	c.invalidate(id, b.UniqeName)
+	return b, err
+}
+
+func (c *CachingRepository) RenameSingleBreed(ctx context.Context, id string, dto RenameBreed) (Breed, error) {
+	b, err := c.r.RenameSingleBreed(ctx, id, dto)
+	c.invalidate(id, dto.UniqeName, b.UniqeName)
+	return b, err
+}
+
+func (c *CachingRepository) Delete(ctx context.Context, id string) error {
+	defer c.invalidate(id)
+	return c.r.Delete(ctx, id)
+}
+
+// invalidate drops every cached list and the last modification time, and the cached breeds read with or resolved to one of the uniqueNames.
+// It is also called after failed writes, as they may have been applied nonetheless.
+func (c *CachingRepository) invalidate(uniqueNames ...string) {
+	atomic.AddUint64(&c.generation, 1)
+	names := map[string]bool{}
+	for _, name := range uniqueNames {
+		if name != "" {
+			names[name] = true
+		}
+	}
+	c.lru.RemoveFunc(func(key string, v cached) bool {
+		if !strings.HasPrefix(key, breedCacheKey) {
+			return true
+		}
+		// Drop the organization and fields the breed was read for
+		id := key[strings.LastIndex(key, "/")+1:]
+		return names[id] || names[v.breed.UniqeName]
+	})
+}
+
+// scoped prefixes the cache key with the organization of the context, which can't contain a slash.
+func scoped(ctx context.Context, prefix string) string {
+	return prefix + org.FromContext(ctx) + "/"
+}
+
+// projected scopes the cache key like scoped, and suffixes it with the fields of the context's reads, if any,
+// so the partial breeds aren't read for the whole ones. Their uniqueName is always read, see crud.WithFields.
+func projected(ctx context.Context, prefix string) string {
+	key := scoped(ctx, prefix)
+	if fs := crud.FieldsFromContext(ctx); len(fs) > 0 {
+		key += strings.Join(fs, ",") + "/"
+	}
+	return key
+}
+
+// listKey normalizes the list params, ignoring the page and limit of unpaginated lists.
+// The queries are the breed query params, marshaled as they were before the crud toolkit so the ETags don't change.
+func listKey(qp params.PaginationQueryParams, q crud.Query) string {
+	if !qp.Paginate {
+		qp.Page, qp.Limit = 0, 0
+	}
+	key, _ := json.Marshal(struct {
+		Pagination params.PaginationQueryParams
+		Breed      crud.Query
+	}{qp, q})
+	return string(key)
+}
+
+func copyBreeds(breeds []Breed) []Breed {
+	return append([]Breed{}, breeds...)
+}
+
+func copyMeta(meta *pagination.PaginationData) *pagination.PaginationData {
+	if meta == nil {
+		return nil
+	}
+	m := *meta
+	return &m
+}
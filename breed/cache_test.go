@@ -0,0 +1,110 @@
+package breed
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+)
+
+// slowRepository is a Repository whose Get waits for its release, or the context, before reading the breed.
+type slowRepository struct {
+	Repository
+	reads   int32
+	release chan struct{}
+}
+
+func (r *slowRepository) Get(ctx context.Context, id string) (Breed, error) {
+	atomic.AddInt32(&r.reads, 1)
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return Breed{}, ctx.Err()
+		}
+	}
+	return Breed{UniqeName: id, Name: org.FromContext(ctx)}, nil
+}
+
+func (r *slowRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestCachingRepositoryCounts(t *testing.T) {
+	r := &slowRepository{}
+	c := NewCachingRepository(r, 10, time.Minute)
+	ctx := context.Background()
+
+	c.Get(ctx, "borzoi")
+	c.Get(ctx, "borzoi")
+	c.Get(org.NewContext(ctx, "acme"), "borzoi")
+	if r.reads != 2 {
+		t.Errorf("%d reads, want one per organization", r.reads)
+	}
+	if s := c.Stats(); s.Hits != 1 || s.Misses != 2 || s.Size != 2 {
+		t.Errorf("Stats() = %+v, want 1 hit and 2 misses", s)
+	}
+
+	// The writes drop the breed for all the organizations
+	c.Delete(ctx, "borzoi")
+	c.Get(ctx, "borzoi")
+	if r.reads != 3 {
+		t.Errorf("%d reads after the delete, want the breed read again", r.reads)
+	}
+}
+
+func TestCachingRepositoryExpires(t *testing.T) {
+	r := &slowRepository{}
+	c := NewCachingRepository(r, 10, 30*time.Millisecond)
+
+	c.Get(context.Background(), "borzoi")
+	time.Sleep(40 * time.Millisecond)
+	c.Get(context.Background(), "borzoi")
+	if r.reads != 2 {
+		t.Errorf("%d reads, want the expired breed read again", r.reads)
+	}
+}
+
+func TestCachingRepositorySharesReads(t *testing.T) {
+	r := &slowRepository{release: make(chan struct{})}
+	c := NewCachingRepository(r, 10, time.Minute)
+
+	// The first request is canceled while the others wait for its read
+	first, cancel := context.WithCancel(org.NewContext(context.Background(), "acme"))
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.Get(first, "borzoi")
+		errs <- err
+	}()
+	for atomic.LoadInt32(&r.reads) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	results := make(chan Breed, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			b, _ := c.Get(org.NewContext(context.Background(), "acme"), "borzoi")
+			results <- b
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() of the canceled request = %v, want context.Canceled", err)
+	}
+
+	close(r.release)
+	for i := 0; i < 2; i++ {
+		if b := <-results; b.UniqeName != "borzoi" || b.Name != "acme" {
+			t.Errorf("Get() = %+v, want the breed read for acme", b)
+		}
+	}
+	if r.reads != 1 {
+		t.Errorf("%d reads, want a single shared read", r.reads)
+	}
+	if _, ok := c.lru.Get(breedCacheKey + "acme/borzoi"); !ok {
+		t.Error("the shared read isn't cached")
+	}
+}
@@ -0,0 +1,116 @@
+package breed
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/similarity"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/slug"
+)
+
+// DefaultDuplicateThreshold is the name similarity ratio from which two breeds are near-duplicates.
+const DefaultDuplicateThreshold = 0.9
+
+// Duplicate is a pair of breeds which are likely the same breed.
+type Duplicate struct {
+	UniqueName      string  `json:"uniqueName" example:"chowchow"`
+	DuplicateOf     string  `json:"duplicateOf" example:"chow_chow"`
+	DuplicateOfName string  `json:"duplicateOfName" example:"Chow Chow"`
+	Reason          string  `json:"reason" enums:"url,name" example:"name"`
+	NameSimilarity  float64 `json:"nameSimilarity" example:"1"`
+	CanonicalURL    string  `json:"canonicalUrl,omitempty" example:"https://en.wikipedia.org/wiki/Chow_Chow"`
+}
+
+// DuplicateError is returned when creating a breed which has near-duplicates in strict mode.
+type DuplicateError struct {
+	Duplicates []Duplicate
+}
+
+func (e *DuplicateError) Error() string {
+	names := make([]string, 0, len(e.Duplicates))
+	for _, d := range e.Duplicates {
+		names = append(names, d.DuplicateOf)
+	}
+	return fmt.Sprintf("breed is a near-duplicate of: %s", strings.Join(names, ", "))
+}
+
+// Warnings returns a human readable warning per duplicate.
+func Warnings(duplicates []Duplicate) []string {
+	var warnings []string
+	for _, d := range duplicates {
+		if d.Reason == "url" {
+			warnings = append(warnings, fmt.Sprintf("breed has the same url as %q (%s)", d.DuplicateOfName, d.DuplicateOf))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("breed name is %.0f%% similar to %q (%s)", d.NameSimilarity*100, d.DuplicateOfName, d.DuplicateOf))
+		}
+	}
+	return warnings
+}
+
+// FindDuplicates returns every pair of near-duplicate breeds, in the order they appear.
+func FindDuplicates(breeds []Breed, threshold float64) []Duplicate {
+	keys := make([]duplicateKey, len(breeds))
+	for i, b := range breeds {
+		keys[i] = newDuplicateKey(b.Name, b.URL)
+	}
+
+	var duplicates []Duplicate
+	for i := range breeds {
+		for j := i + 1; j < len(breeds); j++ {
+			if d, ok := compare(breeds[j], keys[j], breeds[i], keys[i], threshold); ok {
+				duplicates = append(duplicates, d)
+			}
+		}
+	}
+	return duplicates
+}
+
+// FindSimilar returns the breeds which the candidate name and url are near-duplicates of.
+// Breeds with the same uniqueName as the candidate are skipped.
+func FindSimilar(candidate Breed, breeds []Breed, threshold float64) []Duplicate {
+	key := newDuplicateKey(candidate.Name, candidate.URL)
+
+	var duplicates []Duplicate
+	for _, b := range breeds {
+		if candidate.UniqeName != "" && b.UniqeName == candidate.UniqeName {
+			continue
+		}
+		if d, ok := compare(candidate, key, b, newDuplicateKey(b.Name, b.URL), threshold); ok {
+			duplicates = append(duplicates, d)
+		}
+	}
+	return duplicates
+}
+
+type duplicateKey struct {
+	name string
+	url  string
+}
+
+func newDuplicateKey(name, url string) duplicateKey {
+	// Compare names without separators so "Chow Chow" and "Chowchow" are equal.
+	key := duplicateKey{name: strings.ReplaceAll(slug.Make(name), "_", "")}
+	if url != "" {
+		key.url = similarity.CanonicalWikipediaURL(url)
+	}
+	return key
+}
+
+func compare(b Breed, bKey duplicateKey, of Breed, ofKey duplicateKey, threshold float64) (Duplicate, bool) {
+	d := Duplicate{
+		UniqueName:      b.UniqeName,
+		DuplicateOf:     of.UniqeName,
+		DuplicateOfName: of.Name,
+		NameSimilarity:  similarity.Ratio(bKey.name, ofKey.name),
+	}
+	if bKey.url != "" && bKey.url == ofKey.url {
+		d.Reason = "url"
+		d.CanonicalURL = bKey.url
+		return d, true
+	}
+	if d.NameSimilarity >= threshold {
+		d.Reason = "name"
+		return d, true
+	}
+	return d, false
+}
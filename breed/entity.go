@@ -2,31 +2,96 @@ package breed
 
 import (
 	"time"
+
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/locale"
 )
 
+// Range struct
+//
+// Weight is measured in kg, height in cm and lifespan in years.
+type Range struct {
+	Min float64 `json:"min" validate:"gte=0" example:"3"`
+	Max float64 `json:"max" validate:"gtefield=Min" example:"6"`
+}
+
 // Breed struct
+//
+// The breeds without an organization are shared, visible to every organization, the others are private to their
+// organization. The uniqueNames are unique across all the organizations.
 type Breed struct {
-	UniqeName    string    `json:"uniqueName" tigris:"primaryKey:1,searchIndex" example:"affenpinscher"`
-	Name         string    `json:"name" tigris:"index" example:"Affenpinscher"`
-	URL          string    `json:"url" example:"https://en.wikipedia.org/wiki/Affenpinscher"`
-	CreationType string    `json:"creationType" tigris:"searchIndex" example:"original"`
-	CreatedAt    time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
-	UpdatedAt    time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+	UniqeName      string            `json:"uniqueName" tigris:"primaryKey:1,searchIndex" example:"affenpinscher"`
+	OrganizationID string            `json:"organizationId" tigris:"index,searchIndex" example:"acme"`
+	Name           string            `json:"name" tigris:"index" example:"Affenpinscher"`
+	URL            string            `json:"url" example:"https://en.wikipedia.org/wiki/Affenpinscher"`
+	CreationType   string            `json:"creationType" tigris:"searchIndex" example:"original"`
+	Group          string            `json:"group" tigris:"index,searchIndex" example:"toy"`
+	Origin         string            `json:"origin" tigris:"index,searchIndex" example:"DE"`
+	Size           string            `json:"size" tigris:"index,searchIndex" example:"small"`
+	Weight         Range             `json:"weight"`
+	Height         Range             `json:"height"`
+	Lifespan       Range             `json:"lifespan"`
+	Temperament    []string          `json:"temperament" tigris:"searchIndex" example:"loyal,curious,playful"`
+	Aliases        []string          `json:"aliases" tigris:"searchIndex" example:"monkey_terrier"`
+	LocalizedNames map[string]string `json:"localizedNames" tigris:"searchIndex"`
+	CreatedAt      time.Time         `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt      time.Time         `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// Localize returns a copy of the breed named in the first of the preferred
+// languages it has a localized name for.
+func (b Breed) Localize(langs []string) Breed {
+	if name, ok := locale.Match(langs, b.LocalizedNames); ok {
+		b.Name = name
+	}
+	return b
 }
 
 // CreateBreed struct
 type CreateBreed struct {
-	Name         string    `json:"name" validate:"required" example:"Affenpinscher"`
-	UniqeName    string    `json:"uniqueName" validate:"required,alpha_underscore" example:"affenpinscher"`
-	URL          string    `json:"url" validate:"required,url" example:"https://en.wikipedia.org/wiki/Affenpinscher"`
-	CreationType string    `json:"creationType" validate:"required,oneof=original custom" example:"original"`
-	CreatedAt    time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
-	UpdatedAt    time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+	Name           string            `json:"name" validate:"required" example:"Affenpinscher"`
+	UniqeName      string            `json:"uniqueName" validate:"omitempty,alpha_underscore" example:"affenpinscher"`
+	URL            string            `json:"url" validate:"required,url" example:"https://en.wikipedia.org/wiki/Affenpinscher"`
+	CreationType   string            `json:"creationType" validate:"required,oneof=original custom" example:"original"`
+	Group          string            `json:"group" validate:"omitempty,oneof=herding hound non_sporting sporting terrier toy working miscellaneous" example:"toy"`
+	Origin         string            `json:"origin" validate:"omitempty,iso3166_1_alpha2" example:"DE"`
+	Size           string            `json:"size" validate:"omitempty,oneof=toy small medium large giant" example:"small"`
+	Weight         Range             `json:"weight"`
+	Height         Range             `json:"height"`
+	Lifespan       Range             `json:"lifespan"`
+	Temperament    []string          `json:"temperament" validate:"omitempty,max=10,dive,required,max=32" example:"loyal,curious,playful"`
+	Aliases        []string          `json:"aliases" validate:"omitempty,max=20,dive,alpha_underscore" example:"monkey_terrier"`
+	LocalizedNames map[string]string `json:"localizedNames" validate:"omitempty,dive,keys,bcp47_language_tag,endkeys,required"`
+	CreatedAt      time.Time         `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt      time.Time         `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
 }
 
 // UpdateBreed struct
 type UpdateBreed struct {
-	Name      string    `json:"name" validate:"omitempty" example:"Affenpinscher"`
-	URL       string    `json:"url" validate:"omitempty,url" example:"https://en.wikipedia.org/wiki/Affenpinscher"`
+	Name           string            `json:"name" validate:"omitempty" example:"Affenpinscher"`
+	URL            string            `json:"url" validate:"omitempty,url" example:"https://en.wikipedia.org/wiki/Affenpinscher"`
+	Group          string            `json:"group" validate:"omitempty,oneof=herding hound non_sporting sporting terrier toy working miscellaneous" example:"toy"`
+	Origin         string            `json:"origin" validate:"omitempty,iso3166_1_alpha2" example:"DE"`
+	Size           string            `json:"size" validate:"omitempty,oneof=toy small medium large giant" example:"small"`
+	Weight         *Range            `json:"weight" validate:"omitempty"`
+	Height         *Range            `json:"height" validate:"omitempty"`
+	Lifespan       *Range            `json:"lifespan" validate:"omitempty"`
+	Temperament    []string          `json:"temperament" validate:"omitempty,max=10,dive,required,max=32" example:"loyal,curious,playful"`
+	Aliases        []string          `json:"aliases" validate:"omitempty,max=20,dive,alpha_underscore" example:"monkey_terrier"`
+	LocalizedNames map[string]string `json:"localizedNames" validate:"omitempty,dive,keys,bcp47_language_tag,endkeys,required"`
+	UpdatedAt      time.Time         `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// RenameBreed struct
+type RenameBreed struct {
+	UniqeName string    `json:"uniqueName" validate:"required,alpha_underscore" example:"affenpinscher"`
 	UpdatedAt time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
 }
+
+// BreedDeletion records when a breed of an organization, or a shared one, was last deleted, as the deleted
+// breeds no longer tell when the breeds were last modified.
+// The type name makes Tigris name the collection `breed_deletions`.
+type BreedDeletion struct {
+	ID             string    `json:"id" tigris:"primaryKey:1"`
+	OrganizationID string    `json:"organizationId" tigris:"index"`
+	DeletedAt      time.Time `json:"deletedAt"`
+}
@@ -0,0 +1,298 @@
+package breed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/simply-alliv/tigris-go-explore/outbox"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/events"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+// The types of the events written to the outbox by the repository, their data is the Breed.
+const (
+	EventCreated = "breed.created"
+	EventUpdated = "breed.updated"
+	EventRenamed = "breed.renamed"
+	EventDeleted = "breed.deleted"
+)
+
+// NewBusPublisher returns the outbox publisher of the breed events to the change feed bus.
+// The breed payload of the outbox records is decoded, so the feed can be filtered on the breed attributes.
+func NewBusPublisher(bus *events.Bus) outbox.Publisher {
+	return outbox.PublisherFunc(func(ctx context.Context, e events.Event) error {
+		if raw, ok := e.Data.(json.RawMessage); ok {
+			var b Breed
+			if err := json.Unmarshal(raw, &b); err != nil {
+				return err
+			}
+			e.Data = b
+		}
+		bus.Publish(e)
+		return nil
+	})
+}
+
+// heartbeatInterval is how often an idle change feed connection is kept alive.
+const heartbeatInterval = 15 * time.Second
+
+// EventFilter selects the breed events a change feed subscriber receives.
+// Empty fields match every event.
+type EventFilter struct {
+	Types        []string `json:"type" validate:"omitempty,dive,oneof=breed.created breed.updated breed.renamed breed.deleted"`
+	CreationType string   `json:"creationType" validate:"omitempty,oneof=original custom"`
+	Group        string   `json:"group" validate:"omitempty,oneof=herding hound non_sporting sporting terrier toy working miscellaneous"`
+	Origin       string   `json:"origin" validate:"omitempty,iso3166_1_alpha2"`
+	Size         string   `json:"size" validate:"omitempty,oneof=toy small medium large giant"`
+	// OrganizationID is the organization of the subscriber, which only receives the events of the breeds it sees.
+	OrganizationID string `json:"-"`
+}
+
+// Match reports whether the event passes the filter.
+func (f EventFilter) Match(e events.Event) bool {
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			if t == e.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	b, ok := e.Data.(Breed)
+	if !ok {
+		return false
+	}
+	return (b.OrganizationID == "" || b.OrganizationID == f.OrganizationID) &&
+		(f.CreationType == "" || f.CreationType == b.CreationType) &&
+		(f.Group == "" || f.Group == b.Group) &&
+		(f.Origin == "" || f.Origin == b.Origin) &&
+		(f.Size == "" || f.Size == b.Size)
+}
+
+// eventFilter reads the filter from the query string, where `type` may be repeated or comma-separated,
+// scoped to the organization of the request.
+func eventFilter(r *http.Request) (EventFilter, error) {
+	q := r.URL.Query()
+	f := EventFilter{
+		CreationType:   q.Get("creationType"),
+		Group:          q.Get("group"),
+		Origin:         q.Get("origin"),
+		Size:           q.Get("size"),
+		OrganizationID: org.FromContext(r.Context()),
+	}
+	for _, v := range q["type"] {
+		for _, t := range strings.Split(v, ",") {
+			if t != "" {
+				f.Types = append(f.Types, t)
+			}
+		}
+	}
+	return f, validation.Struct(f)
+}
+
+// lastEventID reads the ID of the last event the client has received, from the
+// `Last-Event-ID` header sent by reconnecting EventSources or the `lastEventId` query param.
+func lastEventID(r *http.Request) (uint64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("lastEventId")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid last event ID %q", v)
+	}
+	return id, nil
+}
+
+// GetBreedEvents streams the breed change feed as Server-Sent Events.
+//
+// A client reconnecting with a Last-Event-ID is replayed the events it missed.
+// When they are no longer buffered it is sent a `reset` event first, telling it to reload the breeds.
+func GetBreedEvents(bus *events.Bus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+			return
+		}
+		f, err := eventFilter(r)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		lastID, err := lastEventID(r)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		sub, replay, complete := bus.Subscribe(lastID)
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		if !complete {
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		}
+		for _, e := range replay {
+			if f.Match(e) {
+				writeSSE(w, e)
+			}
+		}
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+				flusher.Flush()
+			case e, ok := <-sub.Events():
+				if !ok {
+					// The client fell behind, it reconnects with its Last-Event-ID
+					return
+				}
+				if f.Match(e) {
+					writeSSE(w, e)
+					flusher.Flush()
+				}
+			}
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, e events.Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("Unable to encode breed event %d: %+v\n", e.ID, err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// wsMessage is a message sent by a WebSocket client to replace its filter.
+type wsMessage struct {
+	Filter EventFilter `json:"filter"`
+}
+
+// GetBreedEventsWS streams the breed change feed over a WebSocket, one JSON event per message.
+//
+// The filter is read from the query string, and can be replaced by sending a `{"filter": {...}}` message.
+// Events missed since the `lastEventId` query param are replayed, preceded by a `reset` event
+// when they are no longer buffered.
+func GetBreedEventsWS(bus *events.Bus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, err := eventFilter(r)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		lastID, err := lastEventID(r)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already replied with an error
+			return
+		}
+		defer conn.Close()
+
+		sub, replay, complete := bus.Subscribe(lastID)
+		defer sub.Close()
+
+		// Read the filter updates until the client goes away
+		orgID := f.OrganizationID
+		filters := make(chan EventFilter)
+		done := make(chan struct{})
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			defer close(done)
+			for {
+				var msg wsMessage
+				if err := conn.ReadJSON(&msg); err != nil {
+					return
+				}
+				if err := validation.Struct(msg.Filter); err != nil {
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseUnsupportedData, err.Error()), time.Now().Add(time.Second))
+					return
+				}
+				// The organization can't be changed by the client
+				msg.Filter.OrganizationID = orgID
+				select {
+				case filters <- msg.Filter:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		if !complete {
+			if err := conn.WriteJSON(events.Event{Type: "reset"}); err != nil {
+				return
+			}
+		}
+		for _, e := range replay {
+			if f.Match(e) {
+				if err := conn.WriteJSON(e); err != nil {
+					return
+				}
+			}
+		}
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case f = <-filters:
+			case <-heartbeat.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+					return
+				}
+			case e, ok := <-sub.Events():
+				if !ok {
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscription closed, reconnect with the lastEventId"), time.Now().Add(time.Second))
+					return
+				}
+				if f.Match(e) {
+					if err := conn.WriteJSON(e); err != nil {
+						return
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package breed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+// breedPage is the result of the breeds and searchBreeds queries.
+type breedPage struct {
+	Items      []Breed                    `json:"items"`
+	Pagination *pagination.PaginationData `json:"pagination"`
+}
+
+// localizedName is a single entry of the localizedNames of a breed.
+type localizedName struct {
+	Locale string `json:"locale"`
+	Name   string `json:"name"`
+}
+
+var rangeType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Range",
+	Description: "Weight is measured in kg, height in cm and lifespan in years.",
+	Fields: graphql.Fields{
+		"min": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"max": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
+
+var rangeInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "RangeInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"min": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"max": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
+
+var localizedNameType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "LocalizedName",
+	Fields: graphql.Fields{
+		"locale": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"name":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var localizedNameInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "LocalizedNameInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"locale": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"name":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var breedType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Breed",
+	Fields: graphql.Fields{
+		"uniqueName": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the breed, localized when a locale is given and the breed has a name for it.",
+			Args: graphql.FieldConfigArgument{
+				"locale": &graphql.ArgumentConfig{Type: graphql.String},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				b := p.Source.(Breed)
+				if l, ok := p.Args["locale"].(string); ok && l != "" {
+					return b.Localize([]string{l}).Name, nil
+				}
+				return b.Name, nil
+			},
+		},
+		"url":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"creationType": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"group":        &graphql.Field{Type: graphql.String},
+		"origin":       &graphql.Field{Type: graphql.String},
+		"size":         &graphql.Field{Type: graphql.String},
+		"weight":       &graphql.Field{Type: rangeType},
+		"height":       &graphql.Field{Type: rangeType},
+		"lifespan":     &graphql.Field{Type: rangeType},
+		"temperament":  &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"aliases":      &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"localizedNames": &graphql.Field{
+			Type: graphql.NewList(graphql.NewNonNull(localizedNameType)),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return localizedNamesToList(p.Source.(Breed).LocalizedNames), nil
+			},
+		},
+		"createdAt": &graphql.Field{Type: graphql.DateTime},
+		"updatedAt": &graphql.Field{Type: graphql.DateTime},
+	},
+})
+
+var paginationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Pagination",
+	Fields: graphql.Fields{
+		"total":     &graphql.Field{Type: graphql.Int},
+		"page":      &graphql.Field{Type: graphql.Int},
+		"perPage":   &graphql.Field{Type: graphql.Int},
+		"prev":      &graphql.Field{Type: graphql.Int},
+		"next":      &graphql.Field{Type: graphql.Int},
+		"totalPage": &graphql.Field{Type: graphql.Int},
+	},
+})
+
+var breedPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "BreedPage",
+	Fields: graphql.Fields{
+		"items":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(breedType)))},
+		"pagination": &graphql.Field{Type: paginationType},
+	},
+})
+
+var pageInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "PageInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"page":     &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 1},
+		"limit":    &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 20},
+		"paginate": &graphql.InputObjectFieldConfig{Type: graphql.Boolean, DefaultValue: true},
+	},
+})
+
+var breedFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "BreedFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"creationType": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"group":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"origin":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"size":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"temperament":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"minWeight":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"maxWeight":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"minHeight":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"maxHeight":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"minLifespan":  &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"maxLifespan":  &graphql.InputObjectFieldConfig{Type: graphql.Float},
+	},
+})
+
+var breedSortType = graphql.NewEnum(graphql.EnumConfig{
+	Name: "BreedSort",
+	Values: graphql.EnumValueConfigMap{
+		"NAME_ASC":         &graphql.EnumValueConfig{Value: "name"},
+		"NAME_DESC":        &graphql.EnumValueConfig{Value: "-name"},
+		"UNIQUE_NAME_ASC":  &graphql.EnumValueConfig{Value: "uniqueName"},
+		"UNIQUE_NAME_DESC": &graphql.EnumValueConfig{Value: "-uniqueName"},
+		"CREATED_AT_ASC":   &graphql.EnumValueConfig{Value: "createdAt"},
+		"CREATED_AT_DESC":  &graphql.EnumValueConfig{Value: "-createdAt"},
+		"UPDATED_AT_ASC":   &graphql.EnumValueConfig{Value: "updatedAt"},
+		"UPDATED_AT_DESC":  &graphql.EnumValueConfig{Value: "-updatedAt"},
+	},
+})
+
+var createBreedInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateBreedInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":           &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"uniqueName":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"url":            &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"creationType":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"group":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"origin":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"size":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"weight":         &graphql.InputObjectFieldConfig{Type: rangeInputType},
+		"height":         &graphql.InputObjectFieldConfig{Type: rangeInputType},
+		"lifespan":       &graphql.InputObjectFieldConfig{Type: rangeInputType},
+		"temperament":    &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"aliases":        &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"localizedNames": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(localizedNameInputType))},
+	},
+})
+
+var updateBreedInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UpdateBreedInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"url":            &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"group":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"origin":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"size":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"weight":         &graphql.InputObjectFieldConfig{Type: rangeInputType},
+		"height":         &graphql.InputObjectFieldConfig{Type: rangeInputType},
+		"lifespan":       &graphql.InputObjectFieldConfig{Type: rangeInputType},
+		"temperament":    &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"aliases":        &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"localizedNames": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(localizedNameInputType))},
+	},
+})
+
+// NewGraphQLSchema returns the GraphQL schema of the breeds, backed by the same Service as the HTTP handlers.
+func NewGraphQLSchema(s *Service) (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"breed": &graphql.Field{
+				Type:        breedType,
+				Description: "Get a single breed by its uniqueName or one of its aliases.",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					data, err := s.GetSingleBreed(p.Context, p.Args["id"].(string))
+					if errors.Is(err, ErrNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, graphqlError(err)
+					}
+					return data, nil
+				},
+			},
+			"breeds": &graphql.Field{
+				Type:        graphql.NewNonNull(breedPageType),
+				Description: "Get all breeds matching the filter.",
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: breedFilterType},
+					"sort":   &graphql.ArgumentConfig{Type: breedSortType, Description: "Defaults to NAME_ASC."},
+					"page":   &graphql.ArgumentConfig{Type: pageInputType},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var bqp params.BreedQueryParams
+					if err := decodeArg(p.Args["filter"], &bqp); err != nil {
+						return nil, err
+					}
+					if order, ok := p.Args["sort"].(string); ok {
+						bqp.Sort = &order
+					}
+					if err := validation.Struct(bqp); err != nil {
+						return nil, graphqlError(err)
+					}
+					data, m, err := s.GetAllBreeds(p.Context, pageArg(p.Args["page"]), bqp)
+					if err != nil {
+						return nil, graphqlError(err)
+					}
+					return breedPage{Items: data, Pagination: m}, nil
+				},
+			},
+			"searchBreeds": &graphql.Field{
+				Type:        graphql.NewNonNull(breedPageType),
+				Description: "Full-text search over the breeds.",
+				Args: graphql.FieldConfigArgument{
+					"q":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"page": &graphql.ArgumentConfig{Type: pageInputType},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					data, m, err := s.SearchBreeds(p.Context, p.Args["q"].(string), pageArg(p.Args["page"]))
+					if err != nil {
+						return nil, graphqlError(err)
+					}
+					return breedPage{Items: data, Pagination: m}, nil
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBreed": &graphql.Field{
+				Type: graphql.NewNonNull(breedType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createBreedInputType)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var dto CreateBreed
+					if err := decodeArg(p.Args["input"], &dto); err != nil {
+						return nil, err
+					}
+					now := time.Now().UTC()
+					dto.CreatedAt = now
+					dto.UpdatedAt = now
+					if err := validation.Struct(dto); err != nil {
+						return nil, graphqlError(err)
+					}
+
+					warnings, err := s.CheckDuplicates(p.Context, dto)
+					if err != nil {
+						return nil, graphqlError(err)
+					}
+					for _, warning := range warnings {
+						log.Printf("GraphQL createBreed: %s\n", warning)
+					}
+					data, err := s.CreateSingleBreed(p.Context, dto)
+					if err != nil {
+						return nil, graphqlError(err)
+					}
+					return data, nil
+				},
+			},
+			"updateBreed": &graphql.Field{
+				Type: graphql.NewNonNull(breedType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateBreedInputType)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var dto UpdateBreed
+					if err := decodeArg(p.Args["input"], &dto); err != nil {
+						return nil, err
+					}
+					dto.UpdatedAt = time.Now().UTC()
+					if err := validation.Struct(dto); err != nil {
+						return nil, graphqlError(err)
+					}
+
+					data, err := s.UpdateSingleBreed(p.Context, p.Args["id"].(string), dto)
+					if err != nil {
+						return nil, graphqlError(err)
+					}
+					return data, nil
+				},
+			},
+			"deleteBreed": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := s.DeleteSingleBreed(p.Context, p.Args["id"].(string)); err != nil {
+						return nil, graphqlError(err)
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// graphqlError hides the internal errors from the clients, the domain errors are returned as is.
+func graphqlError(err error) error {
+	var dupErr *DuplicateError
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrConflict), errors.Is(err, ErrForbidden), errors.Is(err, ErrInvalidName),
+		errors.As(err, &dupErr),
+		validation.IsValidationError(err), errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
+	default:
+		log.Printf("Unable to handle GraphQL breed request: %+v\n", err)
+		return errors.New("internal error")
+	}
+}
+
+// decodeArg decodes an input object argument into the DTO sharing its JSON field names.
+// The localizedNames list is turned back into the map used by the DTOs.
+func decodeArg(arg interface{}, dto interface{}) error {
+	obj, ok := arg.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if list, ok := obj["localizedNames"].([]interface{}); ok {
+		names := make(map[string]interface{}, len(list))
+		for _, item := range list {
+			if ln, ok := item.(map[string]interface{}); ok {
+				names[ln["locale"].(string)] = ln["name"]
+			}
+		}
+		obj["localizedNames"] = names
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dto)
+}
+
+func pageArg(arg interface{}) params.PaginationQueryParams {
+	qp := params.PaginationQueryParams{Page: 1, Limit: 20, Paginate: true}
+	obj, ok := arg.(map[string]interface{})
+	if !ok {
+		return qp
+	}
+	if page, ok := obj["page"].(int); ok && page > 0 {
+		qp.Page = page
+	}
+	if limit, ok := obj["limit"].(int); ok && limit > 0 {
+		qp.Limit = limit
+	}
+	if paginate, ok := obj["paginate"].(bool); ok {
+		qp.Paginate = paginate
+	}
+	return qp
+}
+
+func localizedNamesToList(names map[string]string) []localizedName {
+	list := make([]localizedName, 0, len(names))
+	for l, name := range names {
+		list = append(list, localizedName{Locale: l, Name: name})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Locale < list[j].Locale
+	})
+	return list
+}
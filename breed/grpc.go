@@ -0,0 +1,244 @@
+package breed
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	breedv1 "github.com/simply-alliv/tigris-go-explore/pkg/pb/breed/v1"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// streamPageSize is the number of breeds read per page while streaming.
+const streamPageSize = 100
+
+type grpcServer struct {
+	breedv1.UnimplementedBreedServiceServer
+	s *Service
+}
+
+// NewGRPCServer returns the gRPC BreedService, backed by the same Service as the HTTP handlers.
+func NewGRPCServer(s *Service) breedv1.BreedServiceServer {
+	return &grpcServer{s: s}
+}
+
+func (g *grpcServer) ListBreeds(ctx context.Context, req *breedv1.ListBreedsRequest) (*breedv1.ListBreedsResponse, error) {
+	qp := params.PaginationQueryParams{
+		Page:     int(req.GetPage()),
+		Limit:    int(req.GetLimit()),
+		Paginate: req.Paginate == nil || *req.Paginate,
+	}
+	if qp.Page < 1 {
+		qp.Page = 1
+	}
+	if qp.Limit < 1 {
+		qp.Limit = 20
+	}
+	bqp, err := breedQueryParamsFromProto(req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	data, m, err := g.s.GetAllBreeds(ctx, qp, bqp)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &breedv1.ListBreedsResponse{Breeds: make([]*breedv1.Breed, 0, len(data))}
+	for _, b := range data {
+		resp.Breeds = append(resp.Breeds, breedToProto(b))
+	}
+	if m != nil {
+		resp.Pagination = &breedv1.Pagination{
+			Total:     m.Total,
+			Page:      m.Page,
+			PerPage:   m.PerPage,
+			Prev:      m.Prev,
+			Next:      m.Next,
+			TotalPage: m.TotalPage,
+		}
+	}
+	return resp, nil
+}
+
+func (g *grpcServer) StreamBreeds(req *breedv1.ListBreedsRequest, stream breedv1.BreedService_StreamBreedsServer) error {
+	bqp, err := breedQueryParamsFromProto(req)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	qp := params.PaginationQueryParams{Page: 1, Limit: streamPageSize, Paginate: true}
+	for {
+		data, m, err := g.s.GetAllBreeds(stream.Context(), qp, bqp)
+		if err != nil {
+			return grpcError(err)
+		}
+		for _, b := range data {
+			if err := stream.Send(breedToProto(b)); err != nil {
+				return err
+			}
+		}
+		if len(data) < qp.Limit || m == nil || int64(qp.Page) >= m.TotalPage {
+			return nil
+		}
+		qp.Page++
+	}
+}
+
+func (g *grpcServer) GetBreed(ctx context.Context, req *breedv1.GetBreedRequest) (*breedv1.Breed, error) {
+	data, err := g.s.GetSingleBreed(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return breedToProto(data), nil
+}
+
+func (g *grpcServer) CreateBreed(ctx context.Context, req *breedv1.CreateBreedRequest) (*breedv1.Breed, error) {
+	now := time.Now().UTC()
+	dto := CreateBreed{
+		Name:           req.GetName(),
+		UniqeName:      req.GetUniqueName(),
+		URL:            req.GetUrl(),
+		CreationType:   req.GetCreationType(),
+		Group:          req.GetGroup(),
+		Origin:         req.GetOrigin(),
+		Size:           req.GetSize(),
+		Weight:         rangeFromProto(req.GetWeight()),
+		Height:         rangeFromProto(req.GetHeight()),
+		Lifespan:       rangeFromProto(req.GetLifespan()),
+		Temperament:    req.GetTemperament(),
+		Aliases:        req.GetAliases(),
+		LocalizedNames: req.GetLocalizedNames(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := validation.Struct(dto); err != nil {
+		return nil, grpcError(err)
+	}
+
+	warnings, err := g.s.CheckDuplicates(ctx, dto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	data, err := g.s.CreateSingleBreed(ctx, dto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if len(warnings) > 0 {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(append([]string{"warning"}, warnings...)...))
+	}
+	return breedToProto(data), nil
+}
+
+func (g *grpcServer) UpdateBreed(ctx context.Context, req *breedv1.UpdateBreedRequest) (*breedv1.Breed, error) {
+	dto := UpdateBreed{
+		Name:           req.GetName(),
+		URL:            req.GetUrl(),
+		Group:          req.GetGroup(),
+		Origin:         req.GetOrigin(),
+		Size:           req.GetSize(),
+		Temperament:    req.GetTemperament(),
+		Aliases:        req.GetAliases(),
+		LocalizedNames: req.GetLocalizedNames(),
+		UpdatedAt:      time.Now().UTC(),
+	}
+	if req.Weight != nil {
+		r := rangeFromProto(req.Weight)
+		dto.Weight = &r
+	}
+	if req.Height != nil {
+		r := rangeFromProto(req.Height)
+		dto.Height = &r
+	}
+	if req.Lifespan != nil {
+		r := rangeFromProto(req.Lifespan)
+		dto.Lifespan = &r
+	}
+	if err := validation.Struct(dto); err != nil {
+		return nil, grpcError(err)
+	}
+
+	data, err := g.s.UpdateSingleBreed(ctx, req.GetId(), dto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return breedToProto(data), nil
+}
+
+func (g *grpcServer) DeleteBreed(ctx context.Context, req *breedv1.DeleteBreedRequest) (*breedv1.DeleteBreedResponse, error) {
+	if err := g.s.DeleteSingleBreed(ctx, req.GetId()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &breedv1.DeleteBreedResponse{}, nil
+}
+
+// grpcError maps the domain errors to their gRPC status codes.
+func grpcError(err error) error {
+	var dupErr *DuplicateError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrConflict), errors.As(err, &dupErr):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, ErrInvalidName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case validation.IsValidationError(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		log.Printf("Unable to handle gRPC breed request: %+v\n", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func breedQueryParamsFromProto(req *breedv1.ListBreedsRequest) (params.BreedQueryParams, error) {
+	bqp := params.BreedQueryParams{
+		CreationType: req.CreationType,
+		Group:        req.Group,
+		Origin:       req.Origin,
+		Size:         req.Size,
+		Temperament:  req.Temperament,
+		MinWeight:    req.MinWeight,
+		MaxWeight:    req.MaxWeight,
+		MinHeight:    req.MinHeight,
+		MaxHeight:    req.MaxHeight,
+		MinLifespan:  req.MinLifespan,
+		MaxLifespan:  req.MaxLifespan,
+	}
+	return bqp, validation.Struct(bqp)
+}
+
+func breedToProto(b Breed) *breedv1.Breed {
+	return &breedv1.Breed{
+		UniqueName:     b.UniqeName,
+		Name:           b.Name,
+		Url:            b.URL,
+		CreationType:   b.CreationType,
+		Group:          b.Group,
+		Origin:         b.Origin,
+		Size:           b.Size,
+		Weight:         &breedv1.Range{Min: b.Weight.Min, Max: b.Weight.Max},
+		Height:         &breedv1.Range{Min: b.Height.Min, Max: b.Height.Max},
+		Lifespan:       &breedv1.Range{Min: b.Lifespan.Min, Max: b.Lifespan.Max},
+		Temperament:    b.Temperament,
+		Aliases:        b.Aliases,
+		LocalizedNames: b.LocalizedNames,
+		CreatedAt:      timestamppb.New(b.CreatedAt),
+		UpdatedAt:      timestamppb.New(b.UpdatedAt),
+	}
+}
+
+func rangeFromProto(r *breedv1.Range) Range {
+	return Range{Min: r.GetMin(), Max: r.GetMax()}
+}
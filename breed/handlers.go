@@ -1,16 +1,23 @@
 package breed
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/httpcache"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/locale"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
 	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
 )
 
 var (
@@ -19,51 +26,86 @@ var (
 	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
 )
 
+// Routes returns the handlers of the CRUD routes of the breeds, see crud.Register.
+func Routes(s *Service) crud.Routes {
+	return crud.Routes{
+		List:   GetAllBreeds(s),
+		Search: SearchBreeds(s),
+		Get:    GetSingleBreed(s),
+		Create: CreateSingleBreed(s),
+		Update: UpdateSingleBreed(s),
+		Delete: DeleteeSingleBreed(s),
+	}
+}
+
 func GetAllBreeds(s *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		page, err := strconv.Atoi(r.URL.Query().Get("page"))
-		if err != nil || page < 1 {
-			page = 1
-		}
-		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
-		if err != nil || limit < 1 {
-			limit = 20
-		}
-		paginate, err := strconv.ParseBool(r.URL.Query().Get("paginate"))
+		qp := crud.PaginationQueryParams(r)
+		bqp, err := breedQueryParams(r)
 		if err != nil {
-			paginate = true
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
 		}
-		creationType := r.URL.Query().Get("creationType")
-		qp := params.PaginationQueryParams{
-			Page:     page,
-			Limit:    limit,
-			Paginate: paginate,
-		}
-		var bqp params.BreedQueryParams
-		if creationType != "" {
-			bqp = params.BreedQueryParams{CreationType: &creationType}
-		} else {
-			bqp = params.BreedQueryParams{CreationType: nil}
+		ctx, fs, err := fieldsContext(r)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
 		}
 		defer func(begin time.Time) {
 			fmt.Printf("GET /breeds - PaginationQueryParams: %+v - BreedQueryParams: %+v - Took: %v\n", qp, bqp, time.Since(begin))
 		}(time.Now())
-		data, metadata, err := s.GetAllBreeds(r.Context(), qp, bqp)
+		lastModified, ok := checkLastModified(w, r, s)
+		if !ok {
+			return
+		}
+		data, metadata, err := s.GetAllBreeds(ctx, qp, bqp)
 		if err != nil {
 			log.Fatalf("Unable to get all breeds: %+v\n", err)
 		}
+		data = localize(w, r, data)
+		etag := httpcache.WeakETag(listKey(qp, breedQuery{bqp}), lastModified.UnixNano(), metadata.Total, r.Header.Get("Accept-Language"), strings.Join(fs, ","))
+		if httpcache.CheckNotModified(w, r, etag, lastModified) {
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		projected, err := crud.Project(data, fs)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
 
 		// create a new Response struct
 		response := Response{
 			Status:   http.StatusOK,
 			Message:  "success",
-			Data:     data,
+			Data:     projected,
 			Metadata: metadata,
 		}
 		writeResponse(w, response)
 	}
 }
 
+func GetDuplicateBreeds(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func(begin time.Time) {
+			fmt.Printf("GET /breeds/duplicates - Took: %v\n", time.Since(begin))
+		}(time.Now())
+		data, err := s.GetDuplicateBreeds(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// create a new Response struct
+		response := Response{
+			Status:  http.StatusOK,
+			Message: "success",
+			Data:    data,
+		}
+		writeResponse(w, response)
+	}
+}
+
 func GetSingleBreed(s *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -71,21 +113,91 @@ func GetSingleBreed(s *Service) http.HandlerFunc {
 		if !ok {
 			panic(ErrBadRouting)
 		}
+		ctx, fs, err := fieldsContext(r)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
 
-		data, err := s.GetSingleBreed(r.Context(), id)
+		data, err := s.GetSingleBreed(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
 		if err != nil {
-			log.Fatalf("Unable to get single breed by id (%s): %+v\n", id, err)
+			writeError(w, http.StatusInternalServerError, err)
+			return
 		}
 
 		defer func(begin time.Time) {
 			fmt.Printf("GET /breed/%s - Took: %v\n", id, time.Since(begin))
 		}(time.Now())
 		fmt.Printf("GET /breed/%s\n", id)
+		projected, err := crud.Project(localize(w, r, []Breed{data})[0], fs)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
 		// create a new Response struct
 		response := Response{
 			Status:  http.StatusOK,
 			Message: "success",
-			Data:    data,
+			Data:    projected,
+		}
+		// The id is an alias, point the client at the canonical breed without redirecting it,
+		// since the alias may be taken by another breed once renamed.
+		if data.UniqeName != id {
+			w.Header().Set("Content-Location", "/breeds/"+data.UniqeName)
+			response.CanonicalID = data.UniqeName
+		}
+		writeResponse(w, response)
+	}
+}
+
+func SearchBreeds(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			writeError(w, http.StatusUnprocessableEntity, errors.New("missing q query param"))
+			return
+		}
+		qp := crud.PaginationQueryParams(r)
+		ctx, fs, err := fieldsContext(r)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /breeds/search - Query: %q - PaginationQueryParams: %+v - Took: %v\n", q, qp, time.Since(begin))
+		}(time.Now())
+		lastModified, ok := checkLastModified(w, r, s)
+		if !ok {
+			return
+		}
+		data, metadata, err := s.SearchBreeds(ctx, q, qp)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		data = localize(w, r, data)
+		etag := httpcache.WeakETag(q, qp.Page, qp.Limit, lastModified.UnixNano(), metadata.Total, r.Header.Get("Accept-Language"), strings.Join(fs, ","))
+		if httpcache.CheckNotModified(w, r, etag, lastModified) {
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		projected, err := crud.Project(data, fs)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// create a new Response struct
+		response := Response{
+			Status:   http.StatusOK,
+			Message:  "success",
+			Data:     projected,
+			Metadata: metadata,
 		}
 		writeResponse(w, response)
 	}
@@ -97,6 +209,10 @@ func CreateSingleBreed(s *Service) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
 			panic(err)
 		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
 		// Set default timestamps
 		dto.CreatedAt = time.Now().UTC()
 		dto.UpdatedAt = time.Now().UTC()
@@ -104,16 +220,44 @@ func CreateSingleBreed(s *Service) http.HandlerFunc {
 		defer func(begin time.Time) {
 			fmt.Printf("POST /breeds - CreateBreedDTO: %+v - Took: %v\n", dto, time.Since(begin))
 		}(time.Now())
+		warnings, err := s.CheckDuplicates(r.Context(), dto)
+		var dupErr *DuplicateError
+		if errors.As(err, &dupErr) {
+			writeResponse(w, Response{
+				Status:  http.StatusConflict,
+				Message: dupErr.Error(),
+				Data:    dupErr.Duplicates,
+			})
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
 		data, err := s.CreateSingleBreed(r.Context(), dto)
+		if errors.Is(err, ErrInvalidName) {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if errors.Is(err, ErrConflict) {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		if errors.Is(err, ErrForbidden) {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
 		if err != nil {
-			log.Fatalf("Unable to create single breed %+v\n", err)
+			writeError(w, http.StatusInternalServerError, err)
+			return
 		}
 
 		// create a new Response struct
 		response := Response{
-			Status:  http.StatusCreated,
-			Message: "success",
-			Data:    data,
+			Status:   http.StatusCreated,
+			Message:  "success",
+			Data:     data,
+			Warnings: warnings,
 		}
 		writeResponse(w, response)
 	}
@@ -131,6 +275,10 @@ func UpdateSingleBreed(s *Service) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
 			panic(err)
 		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
 		// Set default timestamps
 		dto.UpdatedAt = time.Now().UTC()
 
@@ -138,6 +286,14 @@ func UpdateSingleBreed(s *Service) http.HandlerFunc {
 			fmt.Printf("PATCH /breeds - UpdateBreedDTO: %+v - Took: %v\n", dto, time.Since(begin))
 		}(time.Now())
 		data, err := s.UpdateSingleBreed(r.Context(), id, dto)
+		if errors.Is(err, ErrNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, ErrForbidden) {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
 		if err != nil {
 			log.Fatalf("Unable to create single breed by id (%s): %+v\n", id, err)
 		}
@@ -152,6 +308,56 @@ func UpdateSingleBreed(s *Service) http.HandlerFunc {
 	}
 }
 
+func RenameSingleBreed(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, ok := vars["id"]
+		if !ok {
+			panic(ErrBadRouting)
+		}
+
+		var dto RenameBreed
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			panic(err)
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		// Set default timestamps
+		dto.UpdatedAt = time.Now().UTC()
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /breeds/%s/rename - RenameBreedDTO: %+v - Took: %v\n", id, dto, time.Since(begin))
+		}(time.Now())
+		data, err := s.RenameSingleBreed(r.Context(), id, dto)
+		if errors.Is(err, ErrNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, ErrConflict) {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		if errors.Is(err, ErrForbidden) {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// create a new Response struct
+		response := Response{
+			Status:  http.StatusOK,
+			Message: "success",
+			Data:    data,
+		}
+		writeResponse(w, response)
+	}
+}
+
 func DeleteeSingleBreed(s *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -164,6 +370,10 @@ func DeleteeSingleBreed(s *Service) http.HandlerFunc {
 			fmt.Printf("DELETE /breeds/%s - Took: %v\n", id, time.Since(begin))
 		}(time.Now())
 		err := s.DeleteSingleBreed(r.Context(), id)
+		if errors.Is(err, ErrForbidden) {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
 		if err != nil {
 			log.Fatalf("Unable to delete single breed by id (%s): %+v\n", id, err)
 		}
@@ -178,9 +388,134 @@ func DeleteeSingleBreed(s *Service) http.HandlerFunc {
 	}
 }
 
+func GetCacheStats(c *CachingRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats := c.Stats()
+
+		// create a new Response struct
+		response := Response{
+			Status:   http.StatusOK,
+			Message:  "success",
+			Data:     stats,
+			Metadata: map[string]float64{"hitRatio": stats.HitRatio()},
+		}
+		writeResponse(w, response)
+	}
+}
+
+// checkLastModified reads the collection-level Last-Modified of the breeds, answering with a 304
+// when the request's If-Modified-Since is enough to tell it hasn't changed. It returns false when
+// the response has been written.
+func checkLastModified(w http.ResponseWriter, r *http.Request, s *Service) (time.Time, bool) {
+	lastModified, err := s.LastModified(r.Context())
+	if err != nil {
+		log.Printf("Unable to get the breeds last modification time: %+v\n", err)
+		writeError(w, http.StatusInternalServerError, errors.New("internal error"))
+		return time.Time{}, false
+	}
+	if httpcache.NotModifiedSince(r, lastModified) {
+		w.Header().Add("Vary", "Accept-Language")
+		httpcache.CheckNotModified(w, r, "", lastModified)
+		return lastModified, false
+	}
+	return lastModified, true
+}
+
+// localize names the breeds in the language preferred by the request's Accept-Language header.
+func localize(w http.ResponseWriter, r *http.Request, breeds []Breed) []Breed {
+	w.Header().Add("Vary", "Accept-Language")
+	langs := locale.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if len(langs) == 0 {
+		return breeds
+	}
+	localized := make([]Breed, len(breeds))
+	for i, b := range breeds {
+		localized[i] = b.Localize(langs)
+	}
+	return localized
+}
+
+// fieldsContext reads the sparse fieldset of the request, see crud.ParseFields, and returns the context of its reads.
+// They also read the localized names of the breeds when their name is requested, to localize it.
+func fieldsContext(r *http.Request) (context.Context, []string, error) {
+	fs, err := crud.ParseFields[Breed](r)
+	if err != nil || len(fs) == 0 {
+		return r.Context(), fs, err
+	}
+	read := fs
+	for _, f := range fs {
+		if f == "name" {
+			read = append([]string{"localizedNames"}, fs...)
+			break
+		}
+	}
+	return crud.WithFields(r.Context(), read), fs, nil
+}
+
+// breedQueryParams reads and validates the breed filters from the request's query string.
+func breedQueryParams(r *http.Request) (params.BreedQueryParams, error) {
+	var bqp params.BreedQueryParams
+	q := r.URL.Query()
+	for _, p := range []struct {
+		key   string
+		value **string
+	}{
+		{"creationType", &bqp.CreationType},
+		{"group", &bqp.Group},
+		{"origin", &bqp.Origin},
+		{"size", &bqp.Size},
+		{"temperament", &bqp.Temperament},
+		{"sort", &bqp.Sort},
+		{"filter", &bqp.Filter},
+	} {
+		if v := q.Get(p.key); v != "" {
+			*p.value = &v
+		}
+	}
+	for _, p := range []struct {
+		key   string
+		value **float64
+	}{
+		{"minWeight", &bqp.MinWeight},
+		{"maxWeight", &bqp.MaxWeight},
+		{"minHeight", &bqp.MinHeight},
+		{"maxHeight", &bqp.MaxHeight},
+		{"minLifespan", &bqp.MinLifespan},
+		{"maxLifespan", &bqp.MaxLifespan},
+	} {
+		v := q.Get(p.key)
+		if v == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return bqp, fmt.Errorf("invalid %s query param %q: must be a number", p.key, v)
+		}
+		*p.value = &f
+	}
+	if err := validation.Struct(bqp); err != nil {
+		return bqp, err
+	}
+	if bqp.Filter != nil {
+		if _, err := compileBreedFilter(*bqp.Filter); err != nil {
+			return bqp, err
+		}
+	}
+	return bqp, nil
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	response := Response{
+		Status:  status,
+		Message: err.Error(),
+	}
+	writeResponse(w, response)
+}
+
 func writeResponse(w http.ResponseWriter, response Response) {
 	// set the content type to application/json
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
 
 	// encode the response struct as JSON and write it to the response writer
 	err := json.NewEncoder(w).Encode(response)
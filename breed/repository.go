@@ -2,11 +2,14 @@ package breed
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
+	"time"
 
-	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/outbox"
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/filterql"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
 	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
 	"github.com/tigrisdata/tigris-client-go/fields"
 	"github.com/tigrisdata/tigris-client-go/filter"
@@ -14,135 +17,382 @@ import (
 	"github.com/tigrisdata/tigris-client-go/tigris"
 )
 
+var (
+	// ErrNotFound is returned when no breed matches the given uniqueName or alias.
+	ErrNotFound = fmt.Errorf("breed %w", crud.ErrNotFound)
+	// ErrConflict is returned when a uniqueName is already used by a breed, or one of its aliases.
+	ErrConflict = errors.New("breed uniqueName is already taken")
+	// ErrInvalidName is returned when no uniqueName can be derived from the name of a breed, e.g. one without
+	// any latin letter or digit, so the client has to pick it.
+	ErrInvalidName = errors.New("unable to derive a uniqueName from the breed name, pick one")
+	// ErrForbidden is returned when an organization writes a shared breed, or creates an original one.
+	ErrForbidden = errors.New("shared breeds can't be changed by an organization")
+)
+
 // Repository is an implementation of the Service CRUD interface for organization's breeds.
 //
 // Repository is responsible for managing the persistence layer. (e.g. database operations)
+// The reads and writes are scoped to the organization carried by their context, see the org package.
 type Repository interface {
-	IService
+	crud.Repository[Breed, CreateBreed, UpdateBreed, string]
+	RenameSingleBreed(ctx context.Context, id string, dto RenameBreed) (Breed, error)
+	UniqueNameTaken(ctx context.Context, uniqueName string) (bool, error)
+	LastModified(ctx context.Context) (time.Time, error)
 }
 
 type breedRepository struct {
+	*crud.TigrisRepository[Breed, CreateBreed, UpdateBreed, string]
+	db         *tigris.Database
 	collection *tigris.Collection[Breed]
 }
 
+// breedEvents are the types of the events of the breed writes, but the renames.
+var breedEvents = map[crud.Op]string{
+	crud.OpCreate: EventCreated,
+	crud.OpUpdate: EventUpdated,
+	crud.OpDelete: EventDeleted,
+}
+
 // NewBreedRepository returns a concrete implementation of the Repository interface.
-func NewBreedRepository(collection *tigris.Collection[Breed]) Repository {
-	return &breedRepository{collection: collection}
+//
+// The breeds are read by their uniqueName, or by one of their aliases, and their writes
+// are stored together with their events, see the outbox package.
+func NewBreedRepository(db *tigris.Database) Repository {
+	res := crud.Resource[Breed, CreateBreed, UpdateBreed, string]{
+		KeyField:  "uniqueName",
+		Key:       func(b Breed) string { return b.UniqeName },
+		Lookups:   []string{"uniqueName", "aliases"},
+		New:       newBreed,
+		Changes:   breedChanges,
+		Scope:     visible,
+		Authorize: authorize,
+		OnWrite: func(ctx context.Context, op crud.Op, b Breed) error {
+			if op == crud.OpDelete {
+				if err := recordDeletion(ctx, db, b); err != nil {
+					return err
+				}
+			}
+			return outbox.Append(ctx, db, breedEvents[op], b.UniqeName, b)
+		},
+		ErrNotFound: ErrNotFound,
+	}
+	return &breedRepository{
+		TigrisRepository: crud.NewTigrisRepository(db, res),
+		db:               db,
+		collection:       tigris.GetCollection[Breed](db),
+	}
 }
 
-func (r breedRepository) GetAllBreeds(ctx context.Context, qp params.PaginationQueryParams, bqp params.BreedQueryParams) ([]Breed, *pagination.PaginationData, error) {
-	var breeds []Breed = []Breed{}
-	f := filter.All
-	creationType := bqp.CreationType
-	if creationType != nil && *creationType != "" {
-		f = filter.Eq("creationType", *creationType)
+// newBreed builds a shared breed, or a custom breed private to the organization of the context.
+func newBreed(ctx context.Context, dto CreateBreed) (Breed, error) {
+	b := Breed{
+		Name:           dto.Name,
+		UniqeName:      dto.UniqeName,
+		OrganizationID: org.FromContext(ctx),
+		CreationType:   dto.CreationType,
+		URL:            dto.URL,
+		Group:          dto.Group,
+		Origin:         dto.Origin,
+		Size:           dto.Size,
+		Weight:         dto.Weight,
+		Height:         dto.Height,
+		Lifespan:       dto.Lifespan,
+		Temperament:    dto.Temperament,
+		Aliases:        dto.Aliases,
+		LocalizedNames: dto.LocalizedNames,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+	}
+	if b.Temperament == nil {
+		b.Temperament = []string{}
+	}
+	if b.Aliases == nil {
+		b.Aliases = []string{}
 	}
+	if b.LocalizedNames == nil {
+		b.LocalizedNames = map[string]string{}
+	}
+	return b, nil
+}
 
-	// Add initial pagination data
-	m := pagination.PaginationData{
-		Page:    int64(qp.Page),
-		PerPage: int64(qp.Limit),
+// breedChanges returns the fields set by the update, the omitted ones being left unchanged.
+func breedChanges(dto UpdateBreed) map[string]interface{} {
+	set := map[string]interface{}{}
+	set["updatedAt"] = dto.UpdatedAt
+	if dto.Name != "" {
+		set["name"] = dto.Name
 	}
-	c, err := r.collection.Count(ctx, f)
-	if err != nil {
-		return breeds, &m, err
+	if dto.URL != "" {
+		set["url"] = dto.URL
+	}
+	if dto.Group != "" {
+		set["group"] = dto.Group
+	}
+	if dto.Origin != "" {
+		set["origin"] = dto.Origin
+	}
+	if dto.Size != "" {
+		set["size"] = dto.Size
+	}
+	if dto.Weight != nil {
+		set["weight"] = dto.Weight
 	}
-	if !qp.Paginate {
-		m.Page = 1
-		m.PerPage = c
+	if dto.Height != nil {
+		set["height"] = dto.Height
 	}
+	if dto.Lifespan != nil {
+		set["lifespan"] = dto.Lifespan
+	}
+	if dto.Temperament != nil {
+		set["temperament"] = dto.Temperament
+	}
+	if dto.Aliases != nil {
+		set["aliases"] = dto.Aliases
+	}
+	if dto.LocalizedNames != nil {
+		set["localizedNames"] = dto.LocalizedNames
+	}
+	return set
+}
+
+// RenameSingleBreed moves a breed to a new uniqueName, keeping the old one as an alias.
+//
+// The uniqueName is the primary key, so the breed is re-inserted under the new key
+// and the old document deleted in the same transaction. It is read whole, whatever the fields of the context.
+func (r breedRepository) RenameSingleBreed(ctx context.Context, id string, dto RenameBreed) (Breed, error) {
+	var renamed Breed
+	ctx = crud.WithFields(ctx, nil)
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		b, err := r.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := authorize(ctx, crud.OpUpdate, b); err != nil {
+			return err
+		}
+		if b.UniqeName == dto.UniqeName {
+			renamed = b
+			return nil
+		}
+		// The uniqueNames are unique across the organizations, so the breeds of the others count too.
+		existing, err := r.collection.ReadOne(ctx, filter.Or(filter.Eq("uniqueName", dto.UniqeName), filter.Eq("aliases", dto.UniqeName)))
+		if err == nil && existing.UniqeName != b.UniqeName {
+			return ErrConflict
+		}
+		if err != nil && !errors.Is(err, tigris.ErrNotFound) {
+			return err
+		}
+
+		aliases := []string{b.UniqeName}
+		for _, a := range b.Aliases {
+			if a != dto.UniqeName && a != b.UniqeName {
+				aliases = append(aliases, a)
+			}
+		}
+		old := b.UniqeName
+		b.UniqeName = dto.UniqeName
+		b.Aliases = aliases
+		b.UpdatedAt = dto.UpdatedAt
+		if _, err := r.collection.Insert(ctx, &b); err != nil {
+			return err
+		}
+		if _, err := r.collection.DeleteOne(ctx, filter.Eq("uniqueName", old)); err != nil {
+			return err
+		}
+		renamed = b
+		return outbox.Append(ctx, r.db, EventRenamed, b.UniqeName, b)
+	})
+	return renamed, err
+}
+
+// LastModified returns the latest updatedAt of the breeds, or the time the last of them was deleted if later,
+// or the zero time when there are none.
+func (r breedRepository) LastModified(ctx context.Context) (time.Time, error) {
 	options := tigris.ReadOptions{
-		Skip:  (m.Page - 1) * m.PerPage,
-		Limit: m.PerPage,
-		Sort:  sort.Ascending("name"),
+		Limit: 1,
+		Sort:  sort.Descending("updatedAt"),
 	}
-	it, err := r.collection.ReadWithOptions(ctx, f, fields.All, &options)
+	it, err := r.collection.ReadWithOptions(ctx, visible(ctx), fields.Include("updatedAt"), &options)
 	if err != nil {
-		return breeds, &m, err
+		return time.Time{}, err
 	}
 	defer it.Close()
 
 	var breed Breed
-	for it.Next(&breed) {
-		breeds = append(breeds, breed)
+	it.Next(&breed)
+	if err := it.Err(); err != nil {
+		return time.Time{}, err
 	}
 
-	// Add missing pagination data
-	m.Total = c
-	if !qp.Paginate {
-		m.TotalPage = 1
-	} else {
-		m.TotalPage = int64(math.Ceil(float64(c) / float64(m.PerPage)))
+	// The deletions of the breeds visible to the organization, its own and the shared ones.
+	deletions, err := tigris.GetCollection[BreedDeletion](r.db).Read(ctx, visible(ctx))
+	if err != nil {
+		return time.Time{}, err
 	}
-	if m.Page <= 1 {
-		m.Next = m.Page + 1
-		m.Next = 0
-	} else if m.Page < m.TotalPage {
-		m.Prev = m.Page - 1
-		m.Next = m.Page + 1
-	} else if m.Page >= m.TotalPage {
-		m.Prev = m.Page - 1
-		m.Next = 0
+	defer deletions.Close()
+
+	lastModified := breed.UpdatedAt
+	var d BreedDeletion
+	for deletions.Next(&d) {
+		if d.DeletedAt.After(lastModified) {
+			lastModified = d.DeletedAt
+		}
 	}
+	return lastModified, deletions.Err()
+}
 
-	return breeds, &m, err
+// recordDeletion records the deletion of the breed, in the transaction of the context.
+func recordDeletion(ctx context.Context, db *tigris.Database, b Breed) error {
+	d := BreedDeletion{
+		ID:             "org:" + b.OrganizationID,
+		OrganizationID: b.OrganizationID,
+		DeletedAt:      time.Now().UTC(),
+	}
+	_, err := tigris.GetCollection[BreedDeletion](db).InsertOrReplace(ctx, &d)
+	return err
 }
 
-func (r breedRepository) GetSingleBreed(ctx context.Context, id string) (Breed, error) {
-	breed, err := r.collection.ReadOne(ctx, filter.Eq("uniqueName", id))
-	if err != nil {
-		return *breed, err
+// UniqueNameTaken reports whether the uniqueName is used by a breed, or one of its aliases, of any organization.
+func (r breedRepository) UniqueNameTaken(ctx context.Context, uniqueName string) (bool, error) {
+	_, err := r.collection.ReadOne(ctx, filter.Or(filter.Eq("uniqueName", uniqueName), filter.Eq("aliases", uniqueName)))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return false, nil
 	}
-	return *breed, err
+	return err == nil, err
 }
 
-func (r breedRepository) CreateSingleBreed(ctx context.Context, dto CreateBreed) (Breed, error) {
-	var breed Breed
-	docs := &Breed{
-		Name:         dto.Name,
-		UniqeName:    dto.UniqeName,
-		CreationType: dto.CreationType,
-		URL:          dto.URL,
-		CreatedAt:    dto.CreatedAt,
-		UpdatedAt:    dto.UpdatedAt,
-	}
-	resp, err := r.collection.Insert(ctx, docs)
-	if err != nil {
-		return breed, err
+// visible returns the filter of the breeds visible to the organization of the context:
+// the shared breeds, and its own ones.
+func visible(ctx context.Context) filter.Expr {
+	shared := filter.Eq("organizationId", "")
+	orgID := org.FromContext(ctx)
+	if orgID == "" {
+		return shared
 	}
-	if len(resp.Keys) == 1 {
-		k := resp.Keys[0]
-		data := map[string]interface{}{}
-		err := json.Unmarshal(k, &data)
-		fmt.Println(data)
-		if err != nil {
-			return breed, err
-		}
-	} else {
-		return breed, fmt.Errorf("error counting length of response keys, only 1 is expected, %v received", len(resp.Keys))
+	return filter.Or(shared, filter.Eq("organizationId", orgID))
+}
+
+// authorize returns ErrForbidden when the breed isn't the organization of the context's own,
+// the shared breeds only being written without an organization, or when an organization creates an original breed.
+func authorize(ctx context.Context, op crud.Op, b Breed) error {
+	if b.OrganizationID != org.FromContext(ctx) {
+		return ErrForbidden
 	}
-	return breed, nil
+	if op == crud.OpCreate && b.OrganizationID != "" && b.CreationType != "custom" {
+		return ErrForbidden
+	}
+	return nil
 }
 
-func (r breedRepository) UpdateSingleBreed(ctx context.Context, id string, dto UpdateBreed) (Breed, error) {
-	update := fields.Update{}
-	set := map[string]interface{}{}
-	set["updatedAt"] = dto.UpdatedAt
-	if dto.Name != "" {
-		set["name"] = dto.Name
+// AuthorizeWrite returns ErrForbidden unless the organization of the context can change the breed, e.g. its images:
+// its own breeds, or the shared ones for the requests made without an organization.
+func AuthorizeWrite(ctx context.Context, b Breed) error {
+	return authorize(ctx, crud.OpUpdate, b)
+}
+
+// breedQuery is the crud.Query of the breed query params.
+type breedQuery struct {
+	params.BreedQueryParams
+}
+
+func (q breedQuery) Filter() filter.Filter {
+	return breedFilter(q.BreedQueryParams)
+}
+
+// Sort sorts the breeds by name by default.
+func (q breedQuery) Sort() sort.Order {
+	return crud.SortOrder(q.BreedQueryParams.Sort, "name")
+}
+
+// breedFilter converts the breed query params into a Tigris filter.
+//
+// The min/max range params match breeds whose whole range lies within the bounds,
+// e.g. minWeight=10 only matches breeds with a minimum weight of at least 10kg.
+func breedFilter(bqp params.BreedQueryParams) filter.Filter {
+	var ops []filter.Expr
+	for _, eq := range []struct {
+		field string
+		value *string
+	}{
+		{"creationType", bqp.CreationType},
+		{"group", bqp.Group},
+		{"origin", bqp.Origin},
+		{"size", bqp.Size},
+		{"temperament", bqp.Temperament},
+	} {
+		if eq.value != nil && *eq.value != "" {
+			ops = append(ops, filter.Eq(eq.field, *eq.value))
+		}
 	}
-	if dto.URL != "" {
-		set["url"] = dto.URL
+	for _, gte := range []struct {
+		field string
+		value *float64
+	}{
+		{"weight.min", bqp.MinWeight},
+		{"height.min", bqp.MinHeight},
+		{"lifespan.min", bqp.MinLifespan},
+	} {
+		if gte.value != nil {
+			ops = append(ops, filter.Gte(gte.field, *gte.value))
+		}
 	}
-	update.SetF = set
-	_, err := r.collection.UpdateOne(ctx, filter.Eq("uniqueName", id), &update)
-	if err != nil {
-		return Breed{}, err
+	for _, lte := range []struct {
+		field string
+		value *float64
+	}{
+		{"weight.max", bqp.MaxWeight},
+		{"height.max", bqp.MaxHeight},
+		{"lifespan.max", bqp.MaxLifespan},
+	} {
+		if lte.value != nil {
+			ops = append(ops, filter.Lte(lte.field, *lte.value))
+		}
+	}
+
+	if bqp.Filter != nil && *bqp.Filter != "" {
+		// the expression is checked along the other params by the handlers, see breedQueryParams.
+		if f, err := compileBreedFilter(*bqp.Filter); err == nil {
+			ops = append(ops, f)
+		}
+	}
+
+	switch len(ops) {
+	case 0:
+		return filter.All
+	case 1:
+		return ops[0]
+	default:
+		return filter.And(ops...)
 	}
-	return r.GetSingleBreed(ctx, id)
 }
 
-func (r breedRepository) DeleteSingleBreed(ctx context.Context, id string) error {
-	_, err := r.collection.DeleteOne(ctx, filter.Eq("uniqueName", id))
-	return err
+// breedFields are the fields of the breeds the filter expressions can filter on.
+var breedFields = filterql.Fields{
+	"uniqueName":   {Type: filterql.String, Ops: filterql.TextOps},
+	"name":         {Type: filterql.String, Ops: filterql.TextOps},
+	"creationType": {Type: filterql.String, Ops: filterql.EqualityOps},
+	"group":        {Type: filterql.String, Ops: filterql.EqualityOps},
+	"origin":       {Type: filterql.String, Ops: filterql.EqualityOps},
+	"size":         {Type: filterql.String, Ops: filterql.EqualityOps},
+	"temperament":  {Type: filterql.String, Ops: filterql.EqualityOps},
+	"weight.min":   {Type: filterql.Number, Ops: filterql.ComparisonOps},
+	"weight.max":   {Type: filterql.Number, Ops: filterql.ComparisonOps},
+	"height.min":   {Type: filterql.Number, Ops: filterql.ComparisonOps},
+	"height.max":   {Type: filterql.Number, Ops: filterql.ComparisonOps},
+	"lifespan.min": {Type: filterql.Number, Ops: filterql.ComparisonOps},
+	"lifespan.max": {Type: filterql.Number, Ops: filterql.ComparisonOps},
+	"createdAt":    {Type: filterql.Time, Ops: filterql.ComparisonOps},
+	"updatedAt":    {Type: filterql.Time, Ops: filterql.ComparisonOps},
+}
+
+// compileBreedFilter parses the filter expression, checks it against the breedFields and compiles it to a Tigris filter.
+func compileBreedFilter(expr string) (filter.Filter, error) {
+	n, err := filterql.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	if n, err = breedFields.Check(n); err != nil {
+		return nil, err
+	}
+	return filterql.Compile(n)
 }
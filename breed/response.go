@@ -1,8 +1,10 @@
 package breed
 
 type Response struct {
-	Status   int         `json:"status"`
-	Message  string      `json:"message"`
-	Data     interface{} `json:"data,omitempty"`
-	Metadata interface{} `json:"metadata,omitempty"`
+	Status      int         `json:"status"`
+	Message     string      `json:"message"`
+	Data        interface{} `json:"data,omitempty"`
+	Metadata    interface{} `json:"metadata,omitempty"`
+	Warnings    []string    `json:"warnings,omitempty"`
+	CanonicalID string      `json:"canonicalId,omitempty"`
 }
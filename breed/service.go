@@ -2,27 +2,61 @@ package breed
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
 	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
 	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/slug"
 )
 
 // IService is a simple CRUD interface for organization's breeds.
 type IService interface {
 	GetAllBreeds(ctx context.Context, qp params.PaginationQueryParams, bqp params.BreedQueryParams) ([]Breed, *pagination.PaginationData, error)
 	CreateSingleBreed(ctx context.Context, dto CreateBreed) (Breed, error)
+	SearchBreeds(ctx context.Context, q string, qp params.PaginationQueryParams) ([]Breed, *pagination.PaginationData, error)
 	GetSingleBreed(ctx context.Context, id string) (Breed, error)
 	UpdateSingleBreed(ctx context.Context, id string, dto UpdateBreed) (Breed, error)
+	RenameSingleBreed(ctx context.Context, id string, dto RenameBreed) (Breed, error)
 	DeleteSingleBreed(ctx context.Context, id string) error
 }
 
 type Service struct {
-	r Repository
+	r                  Repository
+	crud               *crud.Service[Breed, CreateBreed, UpdateBreed, string]
+	duplicateThreshold float64
+	strictDuplicates   bool
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithDuplicateThreshold sets the name similarity ratio from which breeds are near-duplicates.
+func WithDuplicateThreshold(threshold float64) Option {
+	return func(s *Service) {
+		s.duplicateThreshold = threshold
+	}
+}
+
+// WithStrictDuplicates rejects the creation of near-duplicate breeds, instead of only warning about them.
+func WithStrictDuplicates(strict bool) Option {
+	return func(s *Service) {
+		s.strictDuplicates = strict
+	}
 }
 
 // NewBreedService returns a service
-func NewBreedService(r Repository) *Service {
-	return &Service{r: r}
+func NewBreedService(r Repository, opts ...Option) *Service {
+	s := &Service{
+		r:                  r,
+		crud:               crud.NewService[Breed, CreateBreed, UpdateBreed, string](r),
+		duplicateThreshold: DefaultDuplicateThreshold,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GetAllBreeds godoc
@@ -32,13 +66,15 @@ func NewBreedService(r Repository) *Service {
 // @Tags Breed
 // @Accept json
 // @Produce json
+// @Param fields query string false "Comma-separated fields of the breeds to return, e.g. name,uniqueName"
+// @Param filter query string false "Filter expression, e.g. name~\"^Bor\" and createdAt>2023-01-01 and creationType in (custom)"
 // @Success 200 {object} JSONResultSuccess{data=[]Breed} "OK"
 // @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
 // @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
 // @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
 // @Router /breeds [get]
 func (s *Service) GetAllBreeds(ctx context.Context, qp params.PaginationQueryParams, bqp params.BreedQueryParams) ([]Breed, *pagination.PaginationData, error) {
-	data, metadata, err := s.r.GetAllBreeds(ctx, qp, bqp)
+	data, metadata, err := s.crud.List(ctx, qp, breedQuery{bqp})
 	if err != nil {
 		// TODO: Handle error
 		return data, metadata, err
@@ -47,6 +83,79 @@ func (s *Service) GetAllBreeds(ctx context.Context, qp params.PaginationQueryPar
 	}
 }
 
+// SearchBreeds godoc
+// @Summary Search breed resources
+// @Description Full-text search over the breed names, aliases, localized names and other indexed fields
+// @Security Bearer
+// @Tags Breed
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param fields query string false "Comma-separated fields of the breeds to return, e.g. name,uniqueName"
+// @Success 200 {object} JSONResultSuccess{data=[]Breed} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /breeds/search [get]
+func (s *Service) SearchBreeds(ctx context.Context, q string, qp params.PaginationQueryParams) ([]Breed, *pagination.PaginationData, error) {
+	data, metadata, err := s.crud.Search(ctx, q, qp)
+	if err != nil {
+		// TODO: Handle error
+		return data, metadata, err
+	} else {
+		return data, metadata, nil
+	}
+}
+
+// GetDuplicateBreeds godoc
+// @Summary Get near-duplicate breed resources
+// @Description Get the pairs of breed resources with the same Wikipedia url or very similar names
+// @Security Bearer
+// @Tags Breed
+// @Accept json
+// @Produce json
+// @Success 200 {object} JSONResultSuccess{data=[]Duplicate} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /breeds/duplicates [get]
+func (s *Service) GetDuplicateBreeds(ctx context.Context) ([]Duplicate, error) {
+	breeds, err := s.allBreeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	duplicates := FindDuplicates(breeds, s.duplicateThreshold)
+	if duplicates == nil {
+		duplicates = []Duplicate{}
+	}
+	return duplicates, nil
+}
+
+// CheckDuplicates returns warnings for the existing breeds the new breed is a near-duplicate of.
+// In strict mode a *DuplicateError is returned instead.
+func (s *Service) CheckDuplicates(ctx context.Context, dto CreateBreed) ([]string, error) {
+	breeds, err := s.allBreeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidate := Breed{UniqeName: dto.UniqeName, Name: dto.Name, URL: dto.URL}
+	duplicates := FindSimilar(candidate, breeds, s.duplicateThreshold)
+	if len(duplicates) > 0 && s.strictDuplicates {
+		return nil, &DuplicateError{Duplicates: duplicates}
+	}
+	return Warnings(duplicates), nil
+}
+
+// LastModified returns the latest modification time of the breeds, deletions included.
+func (s *Service) LastModified(ctx context.Context) (time.Time, error) {
+	return s.r.LastModified(ctx)
+}
+
+func (s *Service) allBreeds(ctx context.Context) ([]Breed, error) {
+	qp := params.PaginationQueryParams{Page: 1, Limit: 1, Paginate: false}
+	breeds, _, err := s.crud.List(ctx, qp, breedQuery{})
+	return breeds, err
+}
+
 // CreateSingleBreed godoc
 // @Summary Create single breed resource
 // @Description Create a single breed resource
@@ -54,16 +163,35 @@ func (s *Service) GetAllBreeds(ctx context.Context, qp params.PaginationQueryPar
 // @Tags Breed
 // @Accept json
 // @Produce json
-// @Param body body CreateBreed true "JSON body to create a breed resource"
+// @Param body body CreateBreed true "JSON body to create a breed resource, the uniqueName is derived from the name when omitted"
 // @Success 201 {object} JSONResultSuccess{data=string} "Created"
 // @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
-// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 403 {object} JSONResultFailure "Error: Forbidden"
+// @Failure 409 {object} JSONResultFailure{data=[]Duplicate} "Error: Conflict"
 // @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
 // @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
 // @Router /breeds [post]
 func (s *Service) CreateSingleBreed(ctx context.Context, dto CreateBreed) (Breed, error) {
+	// Derive the uniqueName from the name when the client didn't pick one
+	if dto.UniqeName == "" {
+		uniqueName, err := s.generateUniqueName(ctx, dto.Name)
+		if err != nil {
+			return Breed{}, err
+		}
+		dto.UniqeName = uniqueName
+	} else {
+		// The uniqueName the client picked mustn't be used by a breed, or one of its aliases
+		taken, err := s.r.UniqueNameTaken(ctx, dto.UniqeName)
+		if err != nil {
+			return Breed{}, err
+		}
+		if taken {
+			return Breed{}, ErrConflict
+		}
+	}
+
 	// Create the breed record with all the defaults set
-	data, err := s.r.CreateSingleBreed(ctx, dto)
+	data, err := s.crud.Create(ctx, dto)
 	if err != nil {
 		// TODO: Handle error
 		return Breed{}, err
@@ -79,15 +207,16 @@ func (s *Service) CreateSingleBreed(ctx context.Context, dto CreateBreed) (Breed
 // @Tags Breed
 // @Accept json
 // @Produce json
-// @Param id path string true "ID of the breed resource"
-// @Success 200 {object} JSONResultSuccess{data=Breed} "OK"
+// @Param id path string true "ID or alias of the breed resource"
+// @Param fields query string false "Comma-separated fields of the breed to return, e.g. name,uniqueName"
+// @Success 200 {object} JSONResultSuccess{data=Breed} "OK, with the canonicalId of the breed when the ID is an alias"
 // @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
 // @Failure 404 {object} JSONResultFailure "Error: Not Found"
 // @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
 // @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
 // @Router /breeds/{id} [get]
 func (s *Service) GetSingleBreed(ctx context.Context, id string) (Breed, error) {
-	data, err := s.r.GetSingleBreed(ctx, id)
+	data, err := s.crud.Get(ctx, id)
 	if err != nil {
 		// TODO: Handle error
 		return data, err
@@ -107,12 +236,13 @@ func (s *Service) GetSingleBreed(ctx context.Context, id string) (Breed, error)
 // @Param body body UpdateBreed true "JSON body to update a breed"
 // @Success 200 {object} JSONResultSuccess{} "OK"
 // @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 403 {object} JSONResultFailure "Error: Forbidden"
 // @Failure 404 {object} JSONResultFailure "Error: Not Found"
 // @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
 // @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
 // @Router /breeds/{id} [patch]
 func (s *Service) UpdateSingleBreed(ctx context.Context, id string, dto UpdateBreed) (Breed, error) {
-	c, err := s.r.UpdateSingleBreed(ctx, id, dto)
+	c, err := s.crud.Update(ctx, id, dto)
 	if err != nil {
 		// TODO: Handle error
 		return c, err
@@ -121,6 +251,33 @@ func (s *Service) UpdateSingleBreed(ctx context.Context, id string, dto UpdateBr
 	}
 }
 
+// RenameSingleBreed godoc
+// @Summary Rename single breed
+// @Description Change the uniqueName of a single breed, keeping the old uniqueName as a redirect alias
+// @Security Bearer
+// @Tags Breed
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the breed"
+// @Param body body RenameBreed true "JSON body with the new uniqueName of the breed"
+// @Success 200 {object} JSONResultSuccess{data=Breed} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 403 {object} JSONResultFailure "Error: Forbidden"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /breeds/{id}/rename [post]
+func (s *Service) RenameSingleBreed(ctx context.Context, id string, dto RenameBreed) (Breed, error) {
+	data, err := s.r.RenameSingleBreed(ctx, id, dto)
+	if err != nil {
+		// TODO: Handle error
+		return data, err
+	} else {
+		return data, nil
+	}
+}
+
 // DeleteSingleBreed godoc
 // @Summary Delete single breed
 // @Description Delete a single breed
@@ -131,12 +288,13 @@ func (s *Service) UpdateSingleBreed(ctx context.Context, id string, dto UpdateBr
 // @Param id path string true "ID of the breed"
 // @Success 200 {object} JSONResultSuccess{} "OK"
 // @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 403 {object} JSONResultFailure "Error: Forbidden"
 // @Failure 404 {object} JSONResultFailure "Error: Not Found"
 // @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
 // @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
 // @Router /breeds/{id} [delete]
 func (s *Service) DeleteSingleBreed(ctx context.Context, id string) error {
-	err := s.r.DeleteSingleBreed(ctx, id)
+	err := s.crud.Delete(ctx, id)
 	if err != nil {
 		// TODO: Handle error
 		return err
@@ -144,3 +302,29 @@ func (s *Service) DeleteSingleBreed(ctx context.Context, id string) error {
 		return nil
 	}
 }
+
+// maxUniqueNameSuffix bounds the collision suffixes tried for a generated uniqueName.
+const maxUniqueNameSuffix = 100
+
+// generateUniqueName slugifies the name, appending a numeric suffix
+// (e.g. chow_chow_2) when the slug is already taken.
+func (s *Service) generateUniqueName(ctx context.Context, name string) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	for i := 1; i <= maxUniqueNameSuffix; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s_%d", base, i)
+		}
+		taken, err := s.r.UniqueNameTaken(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", ErrConflict
+}
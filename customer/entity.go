@@ -0,0 +1,36 @@
+package customer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customer struct
+//
+// The email is stored lowercased and is unique across the customers.
+type Customer struct {
+	ID        uuid.UUID `json:"id" tigris:"primaryKey:1" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	Name      string    `json:"name" tigris:"index,searchIndex" example:"Jane Doe"`
+	Email     string    `json:"email" tigris:"index,searchIndex" example:"jane.doe@example.com"`
+	Phone     string    `json:"phone" example:"+4915123456789"`
+	CreatedAt time.Time `json:"createdAt" tigris:"index" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// CreateCustomer struct
+type CreateCustomer struct {
+	Name      string    `json:"name" validate:"required,max=100" example:"Jane Doe"`
+	Email     string    `json:"email" validate:"required,email" example:"jane.doe@example.com"`
+	Phone     string    `json:"phone" validate:"omitempty,e164" example:"+4915123456789"`
+	CreatedAt time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// UpdateCustomer struct
+type UpdateCustomer struct {
+	Name      string    `json:"name" validate:"omitempty,max=100" example:"Jane Doe"`
+	Email     string    `json:"email" validate:"omitempty,email" example:"jane.doe@example.com"`
+	Phone     *string   `json:"phone" validate:"omitempty,e164" example:"+4915123456789"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
@@ -0,0 +1,269 @@
+package customer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+func GetAllCustomers(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qp := paginationQueryParams(r)
+		cqp, err := customerQueryParams(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /customers - PaginationQueryParams: %+v - Took: %v\n", qp, time.Since(begin))
+		}(time.Now())
+		data, metadata, err := s.GetAllCustomers(r.Context(), qp, cqp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data, Metadata: metadata})
+	}
+}
+
+func SearchCustomers(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "missing q query param"})
+			return
+		}
+		qp := paginationQueryParams(r)
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /customers/search - PaginationQueryParams: %+v - Took: %v\n", qp, time.Since(begin))
+		}(time.Now())
+		data, metadata, err := s.SearchCustomers(r.Context(), q, qp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data, Metadata: metadata})
+	}
+}
+
+func GetSingleCustomer(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := customerID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /customers/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.GetSingleCustomer(r.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func CreateSingleCustomer(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var dto CreateCustomer
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+		// Set default timestamps
+		now := time.Now().UTC()
+		dto.CreatedAt = now
+		dto.UpdatedAt = now
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /customers - Took: %v\n", time.Since(begin))
+		}(time.Now())
+		data, err := s.CreateSingleCustomer(r.Context(), dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusCreated, Message: "success", Data: data})
+	}
+}
+
+func UpdateSingleCustomer(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := customerID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		var dto UpdateCustomer
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+		// Set default timestamps
+		dto.UpdatedAt = time.Now().UTC()
+
+		defer func(begin time.Time) {
+			fmt.Printf("PATCH /customers/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.UpdateSingleCustomer(r.Context(), id, dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func DeleteSingleCustomer(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := customerID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("DELETE /customers/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		if err := s.DeleteSingleCustomer(r.Context(), id); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success"})
+	}
+}
+
+// customerID parses the `id` path variable, an invalid ID being reported as not found.
+func customerID(r *http.Request) (uuid.UUID, error) {
+	v, ok := mux.Vars(r)["id"]
+	if !ok {
+		panic(ErrBadRouting)
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return uuid.Nil, ErrNotFound
+	}
+	return id, nil
+}
+
+// paginationQueryParams reads the pagination params from the request's query string,
+// falling back to the defaults for missing or invalid values.
+func paginationQueryParams(r *http.Request) params.PaginationQueryParams {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	paginate, err := strconv.ParseBool(r.URL.Query().Get("paginate"))
+	if err != nil {
+		paginate = true
+	}
+	return params.PaginationQueryParams{
+		Page:     page,
+		Limit:    limit,
+		Paginate: paginate,
+	}
+}
+
+// customerQueryParams reads and validates the customer filters from the request's query string.
+func customerQueryParams(r *http.Request) (params.CustomerQueryParams, error) {
+	var cqp params.CustomerQueryParams
+	q := r.URL.Query()
+	if v := q.Get("customerId"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return cqp, &queryParamError{fmt.Sprintf("invalid customerId query param %q: must be a UUID", v)}
+		}
+		cqp.CustomerID = &id
+	}
+	for _, p := range []struct {
+		key   string
+		value **string
+	}{
+		{"name", &cqp.Name},
+		{"email", &cqp.Email},
+		{"sort", &cqp.Sort},
+	} {
+		if v := q.Get(p.key); v != "" {
+			*p.value = &v
+		}
+	}
+	return cqp, validation.Struct(cqp)
+}
+
+// queryParamError is returned for a query param which can't be parsed.
+type queryParamError struct {
+	message string
+}
+
+func (e *queryParamError) Error() string {
+	return e.message
+}
+
+// writeError maps the error to its HTTP status code and writes it as the response.
+func writeError(w http.ResponseWriter, err error) {
+	var qpErr *queryParamError
+	var status int
+	message := err.Error()
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		status = http.StatusConflict
+	case validation.IsValidationError(err), errors.As(err, &qpErr):
+		status = http.StatusUnprocessableEntity
+	default:
+		log.Printf("Unable to handle customer request: %+v\n", err)
+		status = http.StatusInternalServerError
+		message = "internal error"
+	}
+	writeResponse(w, Response{Status: status, Message: message})
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	// set the content type to application/json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
+
+	// encode the response struct as JSON and write it to the response writer
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		// handle the error
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+		return
+	}
+}
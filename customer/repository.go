@@ -0,0 +1,236 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/search"
+	"github.com/tigrisdata/tigris-client-go/sort"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+var (
+	// ErrNotFound is returned when no customer has the given ID.
+	ErrNotFound = errors.New("customer not found")
+	// ErrConflict is returned when an email is already used by another customer.
+	ErrConflict = errors.New("customer email is already taken")
+)
+
+// Repository is an implementation of the Service CRUD interface for organization's customers.
+//
+// Repository is responsible for managing the persistence layer. (e.g. database operations)
+type Repository interface {
+	IService
+}
+
+type customerRepository struct {
+	db         *tigris.Database
+	collection *tigris.Collection[Customer]
+}
+
+// NewCustomerRepository returns a concrete implementation of the Repository interface.
+func NewCustomerRepository(db *tigris.Database) Repository {
+	return &customerRepository{db: db, collection: tigris.GetCollection[Customer](db)}
+}
+
+func (r customerRepository) GetAllCustomers(ctx context.Context, qp params.PaginationQueryParams, cqp params.CustomerQueryParams) ([]Customer, *pagination.PaginationData, error) {
+	var customers []Customer = []Customer{}
+	f := customerFilter(cqp)
+
+	// Add initial pagination data
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	c, err := r.collection.Count(ctx, f)
+	if err != nil {
+		return customers, &m, err
+	}
+	if !qp.Paginate {
+		m.Page = 1
+		m.PerPage = c
+	}
+	options := tigris.ReadOptions{
+		Skip:  (m.Page - 1) * m.PerPage,
+		Limit: m.PerPage,
+		Sort:  customerSort(cqp),
+	}
+	it, err := r.collection.ReadWithOptions(ctx, f, fields.All, &options)
+	if err != nil {
+		return customers, &m, err
+	}
+	defer it.Close()
+
+	var customer Customer
+	for it.Next(&customer) {
+		customers = append(customers, customer)
+	}
+
+	m = pagination.New(m.Page, m.PerPage, c)
+
+	return customers, &m, it.Err()
+}
+
+// SearchCustomers runs a full-text search over the names and emails of the customers.
+func (r customerRepository) SearchCustomers(ctx context.Context, q string, qp params.PaginationQueryParams) ([]Customer, *pagination.PaginationData, error) {
+	var customers []Customer = []Customer{}
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	req := search.NewRequestBuilder().
+		WithQuery(q).
+		WithSearchFields("name", "email").
+		WithOptions(&search.Options{Page: int32(qp.Page), PageSize: int32(qp.Limit)}).
+		Build()
+	it, err := r.collection.Search(ctx, req)
+	if err != nil {
+		return customers, &m, err
+	}
+	defer it.Close()
+
+	var res search.Result[Customer]
+	for it.Next(&res) {
+		for _, hit := range res.Hits {
+			customers = append(customers, *hit.Document)
+		}
+		m.Total = res.Meta.Found
+	}
+	if err := it.Err(); err != nil {
+		return customers, &m, err
+	}
+	m = pagination.New(m.Page, m.PerPage, m.Total)
+
+	return customers, &m, nil
+}
+
+func (r customerRepository) GetSingleCustomer(ctx context.Context, id uuid.UUID) (Customer, error) {
+	customer, err := r.collection.ReadOne(ctx, filter.Eq("id", id))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return Customer{}, ErrNotFound
+	}
+	if err != nil {
+		return Customer{}, err
+	}
+	return *customer, nil
+}
+
+// CreateSingleCustomer inserts the customer, checking its email isn't taken in the same transaction.
+func (r customerRepository) CreateSingleCustomer(ctx context.Context, dto CreateCustomer) (Customer, error) {
+	customer := Customer{
+		ID:        uuid.New(),
+		Name:      dto.Name,
+		Email:     dto.Email,
+		Phone:     dto.Phone,
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		if err := r.checkEmail(ctx, customer.Email, uuid.Nil); err != nil {
+			return err
+		}
+		_, err := r.collection.Insert(ctx, &customer)
+		return err
+	})
+	if err != nil {
+		return Customer{}, err
+	}
+	return customer, nil
+}
+
+// UpdateSingleCustomer updates the customer, checking its new email isn't taken in the same transaction.
+func (r customerRepository) UpdateSingleCustomer(ctx context.Context, id uuid.UUID, dto UpdateCustomer) (Customer, error) {
+	var updated Customer
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		customer, err := r.GetSingleCustomer(ctx, id)
+		if err != nil {
+			return err
+		}
+		if dto.Name != "" {
+			customer.Name = dto.Name
+		}
+		if dto.Email != "" && dto.Email != customer.Email {
+			if err := r.checkEmail(ctx, dto.Email, id); err != nil {
+				return err
+			}
+			customer.Email = dto.Email
+		}
+		if dto.Phone != nil {
+			customer.Phone = *dto.Phone
+		}
+		customer.UpdatedAt = dto.UpdatedAt
+		if _, err := r.collection.InsertOrReplace(ctx, &customer); err != nil {
+			return err
+		}
+		updated = customer
+		return nil
+	})
+	if err != nil {
+		return Customer{}, err
+	}
+	return updated, nil
+}
+
+func (r customerRepository) DeleteSingleCustomer(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.GetSingleCustomer(ctx, id); err != nil {
+		return err
+	}
+	_, err := r.collection.DeleteOne(ctx, filter.Eq("id", id))
+	return err
+}
+
+// checkEmail returns ErrConflict when the email is used by a customer other than the given one.
+func (r customerRepository) checkEmail(ctx context.Context, email string, id uuid.UUID) error {
+	existing, err := r.collection.ReadOne(ctx, filter.Eq("email", email))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != id {
+		return ErrConflict
+	}
+	return nil
+}
+
+// customerSort converts the sort query param, a field name optionally prefixed with "-"
+// for a descending order, into a Tigris sort order. Customers are sorted by name by default.
+func customerSort(cqp params.CustomerQueryParams) sort.Order {
+	if cqp.Sort == nil || *cqp.Sort == "" {
+		return sort.Ascending("name")
+	}
+	if strings.HasPrefix(*cqp.Sort, "-") {
+		return sort.Descending(strings.TrimPrefix(*cqp.Sort, "-"))
+	}
+	return sort.Ascending(*cqp.Sort)
+}
+
+// customerFilter converts the customer query params into a Tigris filter.
+func customerFilter(cqp params.CustomerQueryParams) filter.Filter {
+	var ops []filter.Expr
+	if cqp.CustomerID != nil {
+		ops = append(ops, filter.Eq("id", *cqp.CustomerID))
+	}
+	if cqp.Name != nil && *cqp.Name != "" {
+		ops = append(ops, filter.Eq("name", *cqp.Name))
+	}
+	if cqp.Email != nil && *cqp.Email != "" {
+		ops = append(ops, filter.Eq("email", strings.ToLower(*cqp.Email)))
+	}
+
+	switch len(ops) {
+	case 0:
+		return filter.All
+	case 1:
+		return ops[0]
+	default:
+		return filter.And(ops...)
+	}
+}
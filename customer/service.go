@@ -0,0 +1,141 @@
+package customer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+// IService is a simple CRUD interface for organization's customers.
+type IService interface {
+	GetAllCustomers(ctx context.Context, qp params.PaginationQueryParams, cqp params.CustomerQueryParams) ([]Customer, *pagination.PaginationData, error)
+	SearchCustomers(ctx context.Context, q string, qp params.PaginationQueryParams) ([]Customer, *pagination.PaginationData, error)
+	GetSingleCustomer(ctx context.Context, id uuid.UUID) (Customer, error)
+	CreateSingleCustomer(ctx context.Context, dto CreateCustomer) (Customer, error)
+	UpdateSingleCustomer(ctx context.Context, id uuid.UUID, dto UpdateCustomer) (Customer, error)
+	DeleteSingleCustomer(ctx context.Context, id uuid.UUID) error
+}
+
+type Service struct {
+	r Repository
+}
+
+// NewCustomerService returns a service
+func NewCustomerService(r Repository) *Service {
+	return &Service{r: r}
+}
+
+// GetAllCustomers godoc
+// @Summary Get all customer resources
+// @Description Get all the customer resources, optionally filtered by ID, name or email
+// @Security Bearer
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Success 200 {object} JSONResultSuccess{data=[]Customer} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /customers [get]
+func (s *Service) GetAllCustomers(ctx context.Context, qp params.PaginationQueryParams, cqp params.CustomerQueryParams) ([]Customer, *pagination.PaginationData, error) {
+	return s.r.GetAllCustomers(ctx, qp, cqp)
+}
+
+// SearchCustomers godoc
+// @Summary Search customer resources
+// @Description Full-text search over the customer names and emails
+// @Security Bearer
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Success 200 {object} JSONResultSuccess{data=[]Customer} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /customers/search [get]
+func (s *Service) SearchCustomers(ctx context.Context, q string, qp params.PaginationQueryParams) ([]Customer, *pagination.PaginationData, error) {
+	return s.r.SearchCustomers(ctx, q, qp)
+}
+
+// GetSingleCustomer godoc
+// @Summary Get single customer resource
+// @Description Get a single customer resource
+// @Security Bearer
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the customer resource"
+// @Success 200 {object} JSONResultSuccess{data=Customer} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /customers/{id} [get]
+func (s *Service) GetSingleCustomer(ctx context.Context, id uuid.UUID) (Customer, error) {
+	return s.r.GetSingleCustomer(ctx, id)
+}
+
+// CreateSingleCustomer godoc
+// @Summary Create single customer resource
+// @Description Create a single customer resource, the email must not be used by another customer
+// @Security Bearer
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Param body body CreateCustomer true "JSON body to create a customer resource"
+// @Success 201 {object} JSONResultSuccess{data=Customer} "Created"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /customers [post]
+func (s *Service) CreateSingleCustomer(ctx context.Context, dto CreateCustomer) (Customer, error) {
+	dto.Email = normalizeEmail(dto.Email)
+	return s.r.CreateSingleCustomer(ctx, dto)
+}
+
+// UpdateSingleCustomer godoc
+// @Summary Update single customer
+// @Description Update a single customer, the new email must not be used by another customer
+// @Security Bearer
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the customer"
+// @Param body body UpdateCustomer true "JSON body to update a customer"
+// @Success 200 {object} JSONResultSuccess{data=Customer} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /customers/{id} [patch]
+func (s *Service) UpdateSingleCustomer(ctx context.Context, id uuid.UUID, dto UpdateCustomer) (Customer, error) {
+	dto.Email = normalizeEmail(dto.Email)
+	return s.r.UpdateSingleCustomer(ctx, id, dto)
+}
+
+// DeleteSingleCustomer godoc
+// @Summary Delete single customer
+// @Description Delete a single customer
+// @Security Bearer
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the customer"
+// @Success 200 {object} JSONResultSuccess{} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /customers/{id} [delete]
+func (s *Service) DeleteSingleCustomer(ctx context.Context, id uuid.UUID) error {
+	return s.r.DeleteSingleCustomer(ctx, id)
+}
+
+// normalizeEmail lowercases the email, so its uniqueness doesn't depend on the case it was written in.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,267 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// KeyHeader is the request header carrying the idempotency key.
+	KeyHeader = "Idempotency-Key"
+	// ReplayedHeader is set on the replayed responses.
+	ReplayedHeader = "Idempotent-Replayed"
+	// MaxKeyLength is the maximum length of an idempotency key.
+	MaxKeyLength = 255
+	// DefaultTTL is how long the responses are stored for by default.
+	DefaultTTL = 24 * time.Hour
+	// DefaultLease is how long a key is reserved for by default, without its request extending it.
+	DefaultLease = time.Minute
+	// DefaultMaxBodySize is the maximum size of the request bodies which are fingerprinted by default.
+	DefaultMaxBodySize = 16 << 20
+)
+
+// ClientFunc identifies the client a request comes from, the idempotency keys being scoped to it.
+type ClientFunc func(r *http.Request) string
+
+// Option configures the Guard.
+type Option func(*Guard)
+
+// WithTTL sets how long the responses are stored for.
+func WithTTL(ttl time.Duration) Option {
+	return func(g *Guard) {
+		g.ttl = ttl
+	}
+}
+
+// WithLease sets how long a key is reserved for, the lease being extended every half lease while its request
+// is being handled.
+func WithLease(lease time.Duration) Option {
+	return func(g *Guard) {
+		g.lease = lease
+	}
+}
+
+// WithClientFunc sets how the clients are identified.
+func WithClientFunc(fn ClientFunc) Option {
+	return func(g *Guard) {
+		g.client = fn
+	}
+}
+
+// WithMaxBodySize sets the maximum size of the request bodies sent with an idempotency key.
+func WithMaxBodySize(size int64) Option {
+	return func(g *Guard) {
+		g.maxBodySize = size
+	}
+}
+
+// Guard makes the POST and PATCH requests sent with an Idempotency-Key header safe to retry.
+//
+// The first response to a key is stored, with a fingerprint of the request's method, path, query params
+// and body, and replayed to the retries of the same request, whatever the order of their query params.
+// Reusing the key for a different request is rejected with a 422, and retrying while the first request
+// is still being handled with a 409.
+// Server errors aren't stored, so the request can be retried with the same key, as can the requests
+// whose server crashed once their lease expires.
+type Guard struct {
+	store       Store
+	ttl         time.Duration
+	lease       time.Duration
+	client      ClientFunc
+	maxBodySize int64
+}
+
+// NewGuard returns a Guard storing the responses in the given store.
+func NewGuard(store Store, opts ...Option) *Guard {
+	g := &Guard{
+		store:       store,
+		ttl:         DefaultTTL,
+		lease:       DefaultLease,
+		client:      DefaultClient,
+		maxBodySize: DefaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// DefaultClient identifies the clients by their Authorization header, or by their IP address when they don't send one.
+func DefaultClient(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return "auth:" + auth
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// Middleware applies the Guard to the POST and PATCH requests having an Idempotency-Key header.
+func (g *Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(KeyHeader)
+		if idempotencyKey == "" || (r.Method != http.MethodPost && r.Method != http.MethodPatch) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(idempotencyKey) > MaxKeyLength {
+			writeResponse(w, response{Status: http.StatusBadRequest, Message: "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, g.maxBodySize+1))
+		if err != nil {
+			writeResponse(w, response{Status: http.StatusBadRequest, Message: "unable to read the request body"})
+			return
+		}
+		if int64(len(body)) > g.maxBodySize {
+			writeResponse(w, response{Status: http.StatusRequestEntityTooLarge, Message: "request body is too large"})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now().UTC()
+		rec := IdempotencyRecord{
+			Key:         hash(g.client(r), idempotencyKey),
+			Fingerprint: hash(r.Method, r.URL.Path, r.URL.Query().Encode(), string(body)),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(g.lease),
+		}
+		existing, reserved, err := g.store.Reserve(r.Context(), rec)
+		if err != nil {
+			log.Printf("Unable to reserve the idempotency key: %+v\n", err)
+			writeResponse(w, response{Status: http.StatusInternalServerError, Message: "internal error"})
+			return
+		}
+		if !reserved {
+			replay(w, existing, rec.Fingerprint)
+			return
+		}
+
+		rw := &recordingWriter{ResponseWriter: w}
+		completed := false
+		stopExtending := g.extend(rec.Key)
+		defer func() {
+			stopExtending()
+			if !completed {
+				// Let the request be retried with the same key when it failed, or panicked.
+				if err := g.store.Release(context.Background(), rec.Key); err != nil {
+					log.Printf("Unable to release the idempotency key: %+v\n", err)
+				}
+			}
+		}()
+		next.ServeHTTP(rw, r)
+
+		if rw.status == 0 || rw.status >= http.StatusInternalServerError {
+			return
+		}
+		rec.Completed = true
+		rec.ExpiresAt = time.Now().UTC().Add(g.ttl)
+		rec.Status = rw.status
+		rec.Body = rw.body.Bytes()
+		for name, values := range rw.header {
+			rec.Header = append(rec.Header, Header{Name: name, Values: values})
+		}
+		if err := g.store.Complete(context.Background(), rec); err != nil {
+			log.Printf("Unable to store the idempotent response: %+v\n", err)
+			return
+		}
+		completed = true
+	})
+}
+
+// extend extends the lease of the key every half lease, until the returned func is called.
+func (g *Guard) extend(key string) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(g.lease / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if err := g.store.Extend(context.Background(), key, now.UTC().Add(g.lease)); err != nil {
+					log.Printf("Unable to extend the idempotency key lease: %+v\n", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// replay writes the stored response of a key, unless it was used for a different request
+// or its first request is still being handled.
+func replay(w http.ResponseWriter, rec IdempotencyRecord, fingerprint string) {
+	switch {
+	case rec.Fingerprint != fingerprint:
+		writeResponse(w, response{Status: http.StatusUnprocessableEntity, Message: "Idempotency-Key was already used for a different request"})
+	case !rec.Completed:
+		w.Header().Set("Retry-After", "1")
+		writeResponse(w, response{Status: http.StatusConflict, Message: "a request with this Idempotency-Key is still being handled"})
+	default:
+		for _, h := range rec.Header {
+			w.Header()[h.Name] = h.Values
+		}
+		w.Header().Set(ReplayedHeader, "true")
+		w.WriteHeader(rec.Status)
+		_, _ = w.Write(rec.Body)
+	}
+}
+
+// hash returns the hex-encoded SHA-256 of the parts, so neither the credentials nor the bodies are stored.
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response written by the handler.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		w.header = w.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+type response struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func writeResponse(w http.ResponseWriter, resp response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.Status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,213 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counter is a handler answering 201 with the request's body and the number of requests it handled.
+type counter struct {
+	calls  int32
+	status int
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := atomic.AddInt32(&c.calls, 1)
+	body, _ := io.ReadAll(r.Body)
+	status := c.status
+	if status == 0 {
+		status = http.StatusCreated
+	}
+	w.Header().Set("Location", "/bookings/1")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "%d:%s", n, body)
+}
+
+func send(h http.Handler, method, target, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(KeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddlewareReplays(t *testing.T) {
+	next := &counter{}
+	h := NewGuard(NewMemoryStore()).Middleware(next)
+
+	first := send(h, "POST", "/bookings?notify=true&lang=en", "k1", "{}")
+	if first.Code != http.StatusCreated || first.Body.String() != "1:{}" {
+		t.Fatalf("first response = %d %q, want 201 %q", first.Code, first.Body, "1:{}")
+	}
+	if got := first.Header().Get(ReplayedHeader); got != "" {
+		t.Errorf("%s of the first response = %q, want none", ReplayedHeader, got)
+	}
+
+	// The order of the query params doesn't matter
+	retry := send(h, "POST", "/bookings?lang=en&notify=true", "k1", "{}")
+	if retry.Code != http.StatusCreated || retry.Body.String() != "1:{}" {
+		t.Errorf("replayed response = %d %q, want 201 %q", retry.Code, retry.Body, "1:{}")
+	}
+	if got := retry.Header().Get(ReplayedHeader); got != "true" {
+		t.Errorf("%s = %q, want true", ReplayedHeader, got)
+	}
+	if got := retry.Header().Get("Location"); got != "/bookings/1" {
+		t.Errorf("Location = %q, want the stored header", got)
+	}
+	if next.calls != 1 {
+		t.Errorf("handler called %d times, want once", next.calls)
+	}
+}
+
+func TestMiddlewareRejectsReuse(t *testing.T) {
+	tests := []struct {
+		name           string
+		method, target string
+		body           string
+	}{
+		{name: "method", method: "PATCH", target: "/bookings?notify=true", body: "{}"},
+		{name: "path", method: "POST", target: "/payments?notify=true", body: "{}"},
+		{name: "query", method: "POST", target: "/bookings?notify=false", body: "{}"},
+		{name: "no query", method: "POST", target: "/bookings", body: "{}"},
+		{name: "body", method: "POST", target: "/bookings?notify=true", body: `{"id":2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &counter{}
+			h := NewGuard(NewMemoryStore()).Middleware(next)
+			send(h, "POST", "/bookings?notify=true", "k1", "{}")
+
+			if w := send(h, tt.method, tt.target, "k1", tt.body); w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status = %d, want 422", w.Code)
+			}
+			if next.calls != 1 {
+				t.Errorf("handler called %d times, want once", next.calls)
+			}
+		})
+	}
+}
+
+func TestMiddlewareInFlight(t *testing.T) {
+	entered, unblock := make(chan struct{}), make(chan struct{})
+	next := &counter{}
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-unblock
+		next.ServeHTTP(w, r)
+	})
+	// The lease is extended while the first request is being handled
+	h := NewGuard(NewMemoryStore(), WithLease(20*time.Millisecond)).Middleware(blocking)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send(h, "POST", "/bookings", "k1", "{}") }()
+	<-entered
+	time.Sleep(50 * time.Millisecond)
+
+	w := send(h, "POST", "/bookings", "k1", "{}")
+	if w.Code != http.StatusConflict {
+		t.Errorf("status while the first request is handled = %d, want 409", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+
+	close(unblock)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("first response = %d, want 201", first.Code)
+	}
+	if w := send(h, "POST", "/bookings", "k1", "{}"); w.Code != http.StatusCreated || w.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("response once handled = %d, want the replayed 201", w.Code)
+	}
+}
+
+func TestMiddlewareExpiredLease(t *testing.T) {
+	store := NewMemoryStore()
+	next := &counter{}
+	h := NewGuard(store).Middleware(next)
+
+	// The server handling the first request crashed, leaving its reservation behind
+	r := httptest.NewRequest("POST", "/bookings", nil)
+	now := time.Now().UTC()
+	_, _, err := store.Reserve(context.Background(), IdempotencyRecord{
+		Key:         hash(DefaultClient(r), "k1"),
+		Fingerprint: hash("POST", "/bookings", "", "{}"),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(20 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("Reserve() = %v", err)
+	}
+	if w := send(h, "POST", "/bookings", "k1", "{}"); w.Code != http.StatusConflict {
+		t.Errorf("status during the lease = %d, want 409", w.Code)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if w := send(h, "POST", "/bookings", "k1", "{}"); w.Code != http.StatusCreated || next.calls != 1 {
+		t.Errorf("status once the lease expired = %d, want the request handled", w.Code)
+	}
+}
+
+func TestMiddlewareTTL(t *testing.T) {
+	next := &counter{}
+	h := NewGuard(NewMemoryStore(), WithTTL(30*time.Millisecond)).Middleware(next)
+
+	send(h, "POST", "/bookings", "k1", "{}")
+	if w := send(h, "POST", "/bookings", "k1", "{}"); w.Body.String() != "1:{}" {
+		t.Errorf("response within the TTL = %q, want the replayed %q", w.Body, "1:{}")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	w := send(h, "POST", "/bookings", "k1", `{"id":2}`)
+	if w.Body.String() != `2:{"id":2}` || w.Header().Get(ReplayedHeader) != "" {
+		t.Errorf("response once expired = %q, want the key reused", w.Body)
+	}
+}
+
+func TestMiddlewareServerErrors(t *testing.T) {
+	next := &counter{status: http.StatusInternalServerError}
+	h := NewGuard(NewMemoryStore()).Middleware(next)
+
+	send(h, "POST", "/bookings", "k1", "{}")
+	next.status = http.StatusCreated
+	if w := send(h, "POST", "/bookings", "k1", "{}"); w.Code != http.StatusCreated || next.calls != 2 {
+		t.Errorf("retry of a server error = %d, want the request handled again", w.Code)
+	}
+}
+
+func TestMiddlewareScope(t *testing.T) {
+	next := &counter{}
+	h := NewGuard(NewMemoryStore(), WithMaxBodySize(8)).Middleware(next)
+
+	// The keys are scoped to the clients
+	send(h, "POST", "/bookings", "k1", "{}")
+	r := httptest.NewRequest("POST", "/bookings", strings.NewReader("{}"))
+	r.Header.Set(KeyHeader, "k1")
+	r.Header.Set("Authorization", "Bearer other")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if next.calls != 2 {
+		t.Errorf("handler called %d times, want once per client", next.calls)
+	}
+
+	// Only the POST and PATCH requests with a key are guarded
+	send(h, "POST", "/bookings", "", "{}")
+	send(h, "PUT", "/bookings", "k1", "{}")
+	if next.calls != 4 {
+		t.Errorf("handler called %d times, want the unguarded requests handled", next.calls)
+	}
+
+	if w := send(h, "POST", "/bookings", strings.Repeat("k", MaxKeyLength+1), "{}"); w.Code != http.StatusBadRequest {
+		t.Errorf("status with a too long key = %d, want 400", w.Code)
+	}
+	if w := send(h, "POST", "/bookings", "k2", `{"id":"123456"}`); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status with a too large body = %d, want 413", w.Code)
+	}
+}
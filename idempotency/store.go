@@ -0,0 +1,180 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+// IdempotencyRecord is the response stored for an idempotency key.
+// The type name makes Tigris name the collection `idempotency_records`.
+//
+// A record is reserved, without a response, while the first request is being handled,
+// and completed with the response once it has been written. A reservation expires after a short lease,
+// extended while its request is being handled, so the key of a request whose server crashed can be retried.
+type IdempotencyRecord struct {
+	Key         string    `json:"key" tigris:"primaryKey:1"`
+	Fingerprint string    `json:"fingerprint"`
+	Completed   bool      `json:"completed"`
+	Status      int       `json:"status,omitempty"`
+	Header      []Header  `json:"header,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	ExpiresAt   time.Time `json:"expiresAt" tigris:"index"`
+}
+
+// Header is a response header replayed with the stored response.
+type Header struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// Store keeps the idempotency records until they expire.
+type Store interface {
+	// Reserve stores the record unless an unexpired one has the same key, in which case
+	// the existing record is returned instead and the bool is false.
+	Reserve(ctx context.Context, rec IdempotencyRecord) (IdempotencyRecord, bool, error)
+	// Extend extends the lease of a reserved record until the given time, unless it's completed.
+	Extend(ctx context.Context, key string, expiresAt time.Time) error
+	// Complete replaces the reserved record with the completed one.
+	Complete(ctx context.Context, rec IdempotencyRecord) error
+	// Release deletes a reserved record, letting the request be retried.
+	Release(ctx context.Context, key string) error
+	// DeleteExpired deletes the records which expired before now.
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// Sweep deletes the expired records of the store every interval, until the context is done.
+func Sweep(ctx context.Context, s Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.DeleteExpired(ctx, now.UTC()); err != nil && ctx.Err() == nil {
+				log.Printf("Unable to delete the expired idempotency records: %+v\n", err)
+			}
+		}
+	}
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+// NewMemoryStore returns a Store keeping the records in memory,
+// which are therefore neither shared between instances nor kept across restarts.
+func NewMemoryStore() Store {
+	return &memoryStore{records: map[string]IdempotencyRecord{}}
+}
+
+func (s *memoryStore) Reserve(ctx context.Context, rec IdempotencyRecord) (IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[rec.Key]; ok && existing.ExpiresAt.After(time.Now()) {
+		return existing, false, nil
+	}
+	s.records[rec.Key] = rec
+	return rec, true, nil
+}
+
+func (s *memoryStore) Extend(ctx context.Context, key string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec, ok := s.records[key]; ok && !rec.Completed {
+		rec.ExpiresAt = expiresAt
+		s.records[key] = rec
+	}
+	return nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, rec IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[rec.Key] = rec
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func (s *memoryStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, rec := range s.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(s.records, key)
+		}
+	}
+	return nil
+}
+
+type tigrisStore struct {
+	db         *tigris.Database
+	collection *tigris.Collection[IdempotencyRecord]
+}
+
+// NewTigrisStore returns a Store keeping the records in the `idempotency_records` collection,
+// shared between all the instances of the server.
+func NewTigrisStore(db *tigris.Database) Store {
+	return &tigrisStore{db: db, collection: tigris.GetCollection[IdempotencyRecord](db)}
+}
+
+// Reserve reads and writes the record in a transaction, retried on conflicts,
+// so only one of the concurrent requests with the same key reserves it.
+func (s tigrisStore) Reserve(ctx context.Context, rec IdempotencyRecord) (IdempotencyRecord, bool, error) {
+	var existing IdempotencyRecord
+	reserved := false
+	err := s.db.Tx(ctx, func(ctx context.Context) error {
+		r, err := s.collection.ReadOne(ctx, filter.Eq("key", rec.Key))
+		if err == nil && r.ExpiresAt.After(time.Now()) {
+			existing, reserved = *r, false
+			return nil
+		}
+		if err != nil && !errors.Is(err, tigris.ErrNotFound) {
+			return err
+		}
+		if _, err := s.collection.InsertOrReplace(ctx, &rec); err != nil {
+			return err
+		}
+		existing, reserved = rec, true
+		return nil
+	}, tigris.TxOptions{AutoRetry: true})
+	return existing, reserved, err
+}
+
+func (s tigrisStore) Extend(ctx context.Context, key string, expiresAt time.Time) error {
+	_, err := s.collection.UpdateOne(ctx,
+		filter.And(filter.Eq("key", key), filter.Eq("completed", false)),
+		fields.UpdateBuilder().Set("expiresAt", expiresAt),
+	)
+	return err
+}
+
+func (s tigrisStore) Complete(ctx context.Context, rec IdempotencyRecord) error {
+	_, err := s.collection.InsertOrReplace(ctx, &rec)
+	return err
+}
+
+func (s tigrisStore) Release(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, filter.Eq("key", key))
+	return err
+}
+
+func (s tigrisStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := s.collection.Delete(ctx, filter.Lte("expiresAt", now))
+	return err
+}
@@ -4,19 +4,48 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
+	"github.com/simply-alliv/tigris-go-explore/accounting"
+	"github.com/simply-alliv/tigris-go-explore/booking"
 	"github.com/simply-alliv/tigris-go-explore/breed"
+	"github.com/simply-alliv/tigris-go-explore/customer"
+	"github.com/simply-alliv/tigris-go-explore/idempotency"
+	"github.com/simply-alliv/tigris-go-explore/media"
+	"github.com/simply-alliv/tigris-go-explore/migrate"
+	"github.com/simply-alliv/tigris-go-explore/outbox"
+	"github.com/simply-alliv/tigris-go-explore/payment"
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	breedv1 "github.com/simply-alliv/tigris-go-explore/pkg/pb/breed/v1"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/events"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/gql"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/httpcache"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+	"github.com/simply-alliv/tigris-go-explore/ratelimit"
+	"github.com/simply-alliv/tigris-go-explore/resource"
 	"github.com/simply-alliv/tigris-go-explore/seed"
+	"github.com/simply-alliv/tigris-go-explore/webhook"
 	"github.com/tigrisdata/tigris-client-go/tigris"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
+// defaultCachePolicies are the Cache-Control policies used when HTTP_CACHE_POLICIES isn't set.
+const defaultCachePolicies = "/breeds=public, max-age=60;/breeds/search=public, max-age=60;/breeds/{id}=public, max-age=300"
+
+// defaultRateLimitCosts are the route costs used when RATE_LIMIT_COSTS isn't set.
+const defaultRateLimitCosts = "GET /breeds?paginate=false=20;GET /breeds/search?paginate=false=20"
+
 func init() {
 	err := godotenv.Load(".env")
 	if err != nil {
@@ -28,6 +57,32 @@ func main() {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancelFunc()
 
+	duplicateThreshold := breed.DefaultDuplicateThreshold
+	if v := os.Getenv("DUPLICATE_THRESHOLD"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			log.Fatal("Unable to parse DUPLICATE_THRESHOLD string to float: ", err)
+		}
+		duplicateThreshold = t
+	}
+
+	// Lint the seed data if requested, it doesn't need a database connection.
+	// e.g. `go run main.go seed lint [breeds file]`
+	if len(os.Args) > 2 && os.Args[1] == "seed" && os.Args[2] == "lint" {
+		breedsFile := os.Getenv("SEED_DATA_BREEDS_FILE")
+		if len(os.Args) > 3 {
+			breedsFile = os.Args[3]
+		}
+		duplicates, err := seed.Lint(breedsFile, duplicateThreshold)
+		if err != nil {
+			log.Fatal("Unable to lint seed data: ", err)
+		}
+		if len(duplicates) > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Initialise and configure the Tigris SDK client.
 	cfg := &tigris.Config{
 		URL:          os.Getenv("TIGRIS_URL"),
@@ -40,11 +95,31 @@ func main() {
 		panic(err)
 	}
 
-	// Create or update the collections and their schemas
-	db, err := client.OpenDatabase(ctx, &breed.Breed{})
+	// Collections and their schemas are managed by the migrations,
+	// so the database is opened without evolving any schema.
+	db := client.GetDatabase()
+	migrator, err := migrate.NewMigrator(ctx, db)
 	if err != nil {
 		panic(err)
 	}
+
+	// Run the migrate command if requested, instead of starting the server.
+	// e.g. `go run main.go migrate up|down [n]|status`
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.Run(context.Background(), migrator, os.Args[2:]); err != nil {
+			log.Fatal("Unable to run migrate command: ", err)
+		}
+		os.Exit(0)
+	}
+
+	// Refuse to seed or serve against an out of date database.
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		log.Fatal("Unable to check for pending migrations: ", err)
+	}
+	if len(pending) > 0 {
+		log.Fatalf("There are %d pending migrations, run `go run main.go migrate up` first\n", len(pending))
+	}
 	c := tigris.GetCollection[breed.Breed](db)
 
 	// Seed data if required flags are defined and valid,
@@ -61,17 +136,407 @@ func main() {
 		os.Exit(0)
 	} else {
 		// Initialise the servive
-		r := breed.NewBreedRepository(c)
-		s := breed.NewBreedService(r)
+		var r breed.Repository = breed.NewBreedRepository(db)
+		cacheSize := breed.DefaultCacheSize
+		if v := os.Getenv("BREED_CACHE_SIZE"); v != "" {
+			cacheSize, err = strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse BREED_CACHE_SIZE string to int: ", err)
+			}
+		}
+		cacheTTL := breed.DefaultCacheTTL
+		if v := os.Getenv("BREED_CACHE_TTL"); v != "" {
+			cacheTTL, err = time.ParseDuration(v)
+			if err != nil {
+				log.Fatal("Unable to parse BREED_CACHE_TTL string to duration: ", err)
+			}
+		}
+		var breedCache *breed.CachingRepository
+		if cacheSize > 0 {
+			breedCache = breed.NewCachingRepository(r, cacheSize, cacheTTL)
+			r = breedCache
+		}
+		strictDuplicates, _ := strconv.ParseBool(os.Getenv("DUPLICATE_STRICT"))
+		eventBufferSize := events.DefaultBufferSize
+		if v := os.Getenv("EVENTS_BUFFER_SIZE"); v != "" {
+			eventBufferSize, err = strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse EVENTS_BUFFER_SIZE string to int: ", err)
+			}
+		}
+		bus := events.NewBus(eventBufferSize)
+		s := breed.NewBreedService(r,
+			breed.WithDuplicateThreshold(duplicateThreshold),
+			breed.WithStrictDuplicates(strictDuplicates),
+		)
+
+		// Initialise the image gallery service
+		mediaDir := os.Getenv("MEDIA_DIR")
+		if mediaDir == "" {
+			mediaDir = "data/media"
+		}
+		blobs, err := media.NewFileBlobStore(mediaDir)
+		if err != nil {
+			log.Fatal("Unable to create the media blob store: ", err)
+		}
+		var mediaOpts []media.Option
+		if v := os.Getenv("MEDIA_MAX_UPLOAD_SIZE"); v != "" {
+			size, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				log.Fatal("Unable to parse MEDIA_MAX_UPLOAD_SIZE string to int: ", err)
+			}
+			mediaOpts = append(mediaOpts, media.WithMaxUploadSize(size))
+		}
+		if v := os.Getenv("MEDIA_MAX_PIXELS"); v != "" {
+			pixels, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				log.Fatal("Unable to parse MEDIA_MAX_PIXELS string to int: ", err)
+			}
+			mediaOpts = append(mediaOpts, media.WithMaxPixels(pixels))
+		}
+		ms := media.NewImageService(media.NewImageRepository(db), blobs, s, mediaOpts...)
+
+		// Initialise the customer service
+		cs := customer.NewCustomerService(customer.NewCustomerRepository(db))
+
+		// Initialise the booking service, the bookings being made by the customers for the breeds on the resources
+		rs := resource.NewResourceService(resource.NewResourceRepository(db))
+		bs := booking.NewBookingService(booking.NewBookingRepository(db), cs, s, rs)
+
+		// Initialise the payment service, the payments being made with Paystack, or with a local fake of it in development
+		var liveProvider, testProvider payment.Provider
+		switch v := os.Getenv("PAYMENT_PROVIDER"); v {
+		case "fake":
+			log.Println("PAYMENT_PROVIDER is fake: the payments are simulated and no money is taken")
+			fakeLive, closeLive, err := payment.ServeFakePaystack()
+			if err != nil {
+				log.Fatal("Unable to serve the fake payment provider: ", err)
+			}
+			defer closeLive()
+			fakeTest, closeTest, err := payment.ServeFakePaystack()
+			if err != nil {
+				log.Fatal("Unable to serve the fake payment provider: ", err)
+			}
+			defer closeTest()
+			liveProvider, testProvider = fakeLive, fakeTest
+		case "", "paystack":
+			secretKey := os.Getenv("PAYSTACK_SECRET_KEY")
+			if secretKey == "" {
+				log.Fatal("PAYSTACK_SECRET_KEY is required, unless PAYMENT_PROVIDER is fake")
+			}
+			var paystackOpts []payment.PaystackOption
+			if v := os.Getenv("PAYSTACK_BASE_URL"); v != "" {
+				paystackOpts = append(paystackOpts, payment.WithBaseURL(v))
+			}
+			liveProvider = payment.NewPaystack(secretKey, paystackOpts...)
+			if testKey := os.Getenv("PAYSTACK_TEST_SECRET_KEY"); testKey != "" {
+				testProvider = payment.NewPaystack(testKey, paystackOpts...)
+			}
+		default:
+			log.Fatalf("Unknown PAYMENT_PROVIDER %q, expected fake or paystack\n", v)
+		}
+		var paymentOpts []payment.Option
+		if testProvider != nil {
+			paymentOpts = append(paymentOpts, payment.WithTestProvider(testProvider))
+		}
+		ps := payment.NewPaymentService(payment.NewPaymentRepository(db), liveProvider, bs, cs, paymentOpts...)
+
+		// Initialise the accounting service, exporting the invoices of the paid bookings to files or to Xero
+		var exporter accounting.Exporter
+		switch v := os.Getenv("ACCOUNTING_EXPORTER"); v {
+		case "", "file":
+			exportDir := os.Getenv("ACCOUNTING_EXPORT_DIR")
+			if exportDir == "" {
+				exportDir = "data/accounting"
+			}
+			exportFormat := os.Getenv("ACCOUNTING_EXPORT_FORMAT")
+			if exportFormat == "" {
+				exportFormat = accounting.FormatCSV
+			}
+			exporter, err = accounting.NewFileExporter(exportDir, exportFormat)
+			if err != nil {
+				log.Fatal("Unable to create the accounting file exporter: ", err)
+			}
+		case "xero":
+			accessToken := os.Getenv("XERO_ACCESS_TOKEN")
+			if accessToken == "" {
+				log.Fatal("XERO_ACCESS_TOKEN is required when ACCOUNTING_EXPORTER is xero")
+			}
+			var xeroOpts []accounting.XeroOption
+			if v := os.Getenv("XERO_BASE_URL"); v != "" {
+				xeroOpts = append(xeroOpts, accounting.WithXeroBaseURL(v))
+			}
+			if v := os.Getenv("XERO_ACCOUNT_CODE"); v != "" {
+				xeroOpts = append(xeroOpts, accounting.WithXeroAccountCode(v))
+			}
+			exporter = accounting.NewXero(accessToken, xeroOpts...)
+		default:
+			log.Fatalf("Unknown ACCOUNTING_EXPORTER %q, expected file or xero\n", v)
+		}
+		var accountingOpts []accounting.Option
+		if v := os.Getenv("ACCOUNTING_MAX_ATTEMPTS"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse ACCOUNTING_MAX_ATTEMPTS string to int: ", err)
+			}
+			accountingOpts = append(accountingOpts, accounting.WithMaxAttempts(n))
+		}
+		as := accounting.NewAccountingService(accounting.NewSyncRepository(db), exporter, bs, ps, cs, accountingOpts...)
+
+		// Initialise the webhooks, delivered in the background from the breed events
+		var dispatcherOpts []webhook.DispatcherOption
+		if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse WEBHOOK_MAX_ATTEMPTS string to int: ", err)
+			}
+			dispatcherOpts = append(dispatcherOpts, webhook.WithMaxAttempts(n))
+		}
+		if v := os.Getenv("WEBHOOK_WORKERS"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse WEBHOOK_WORKERS string to int: ", err)
+			}
+			dispatcherOpts = append(dispatcherOpts, webhook.WithWorkers(n))
+		}
+		if v := os.Getenv("WEBHOOK_ALLOW_PRIVATE_URLS"); v != "" {
+			allow, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Fatal("Unable to parse WEBHOOK_ALLOW_PRIVATE_URLS string to boolean: ", err)
+			}
+			if allow {
+				dispatcherOpts = append(dispatcherOpts, webhook.WithPrivateNetworks())
+			}
+		}
+		wr := webhook.NewWebhookRepository(db)
+		dispatcher := webhook.NewDispatcher(wr, dispatcherOpts...)
+		ws := webhook.NewWebhookService(wr, dispatcher)
+
+		// Relay the breed events from the outbox to the change feed, the webhooks and the optional file sink
+		publishers := []outbox.Publisher{breed.NewBusPublisher(bus), dispatcher}
+		if path := os.Getenv("OUTBOX_FILE_SINK"); path != "" {
+			sink, err := outbox.NewFileSink(path)
+			if err != nil {
+				log.Fatal("Unable to open the outbox file sink: ", err)
+			}
+			defer sink.Close()
+			publishers = append(publishers, sink)
+		}
+		relay := outbox.NewRelay(outbox.NewOutboxRepository(db), publishers)
+		// The workers outlive the startup context, they are stopped once the server has shut down
+		workersCtx, stopWorkers := context.WithCancel(context.Background())
+		go dispatcher.Run(workersCtx)
+		go relay.Run(workersCtx)
+		if v := os.Getenv("ACCOUNTING_SYNC_TENANTS"); v != "" {
+			syncInterval := 15 * time.Minute
+			if v := os.Getenv("ACCOUNTING_SYNC_INTERVAL"); v != "" {
+				syncInterval, err = time.ParseDuration(v)
+				if err != nil {
+					log.Fatal("Unable to parse ACCOUNTING_SYNC_INTERVAL string to duration: ", err)
+				}
+			}
+			go as.Run(workersCtx, strings.Split(v, ","), syncInterval)
+		}
+
+		// Initialise the GraphQL schema
+		schema, err := breed.NewGraphQLSchema(s)
+		if err != nil {
+			log.Fatal("Unable to build the GraphQL schema: ", err)
+		}
+		graphqlLimits := gql.DefaultLimits()
+		if v := os.Getenv("GRAPHQL_MAX_DEPTH"); v != "" {
+			graphqlLimits.MaxDepth, err = strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse GRAPHQL_MAX_DEPTH string to int: ", err)
+			}
+		}
+		if v := os.Getenv("GRAPHQL_MAX_COMPLEXITY"); v != "" {
+			graphqlLimits.MaxComplexity, err = strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse GRAPHQL_MAX_COMPLEXITY string to int: ", err)
+			}
+		}
+		graphiql, _ := strconv.ParseBool(os.Getenv("GRAPHIQL"))
+
+		// Store the responses of the requests sent with an Idempotency-Key, in Tigris unless IDEMPOTENCY_STORE is memory
+		idempotencyTTL := idempotency.DefaultTTL
+		if v := os.Getenv("IDEMPOTENCY_TTL"); v != "" {
+			idempotencyTTL, err = time.ParseDuration(v)
+			if err != nil {
+				log.Fatal("Unable to parse IDEMPOTENCY_TTL string to duration: ", err)
+			}
+		}
+		idempotencyLease := idempotency.DefaultLease
+		if v := os.Getenv("IDEMPOTENCY_LEASE"); v != "" {
+			idempotencyLease, err = time.ParseDuration(v)
+			if err != nil {
+				log.Fatal("Unable to parse IDEMPOTENCY_LEASE string to duration: ", err)
+			}
+		}
+		var idempotencyStore idempotency.Store
+		switch v := os.Getenv("IDEMPOTENCY_STORE"); v {
+		case "", "tigris":
+			idempotencyStore = idempotency.NewTigrisStore(db)
+		case "memory":
+			idempotencyStore = idempotency.NewMemoryStore()
+		default:
+			log.Fatalf("Unknown IDEMPOTENCY_STORE %q, expected tigris or memory\n", v)
+		}
+		// The idempotency keys of the organizations sharing a client are kept apart
+		idempotencyGuard := idempotency.NewGuard(idempotencyStore,
+			idempotency.WithTTL(idempotencyTTL),
+			idempotency.WithLease(idempotencyLease),
+			idempotency.WithClientFunc(func(r *http.Request) string {
+				return org.FromContext(r.Context()) + "|" + idempotency.DefaultClient(r)
+			}))
+		go idempotency.Sweep(workersCtx, idempotencyStore, time.Minute)
+
+		// Set the Cache-Control policies of the routes
+		cachePolicies := os.Getenv("HTTP_CACHE_POLICIES")
+		if cachePolicies == "" {
+			cachePolicies = defaultCachePolicies
+		}
+		policies, err := httpcache.ParsePolicies(cachePolicies)
+		if err != nil {
+			log.Fatal("Unable to parse HTTP_CACHE_POLICIES: ", err)
+		}
+
+		// Resolve the organization of the requests from the client tokens, or else the X-Org-ID header when it's trusted
+		orgTokens, err := org.ParseTokens(os.Getenv("ORG_TOKENS"))
+		if err != nil {
+			log.Fatal("Unable to parse ORG_TOKENS: ", err)
+		}
+		var orgTrustHeader bool
+		if v := os.Getenv("ORG_TRUST_HEADER"); v != "" {
+			orgTrustHeader, err = strconv.ParseBool(v)
+			if err != nil {
+				log.Fatal("Unable to parse ORG_TRUST_HEADER string to boolean: ", err)
+			}
+		}
+		orgResolver := org.NewResolver(orgTokens, org.WithTrustedHeader(orgTrustHeader))
+
+		// Limit the rate of the requests of each client, and optionally their daily quota
+		rateLimit := ratelimit.DefaultLimit
+		if v := os.Getenv("RATE_LIMIT"); v != "" {
+			rateLimit, err = strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse RATE_LIMIT string to int: ", err)
+			}
+		}
+		rateLimitWindow := ratelimit.DefaultWindow
+		if v := os.Getenv("RATE_LIMIT_WINDOW"); v != "" {
+			rateLimitWindow, err = time.ParseDuration(v)
+			if err != nil {
+				log.Fatal("Unable to parse RATE_LIMIT_WINDOW string to duration: ", err)
+			}
+		}
+		rateLimitCosts := os.Getenv("RATE_LIMIT_COSTS")
+		if rateLimitCosts == "" {
+			rateLimitCosts = defaultRateLimitCosts
+		}
+		costs, err := ratelimit.ParseCosts(rateLimitCosts)
+		if err != nil {
+			log.Fatal("Unable to parse RATE_LIMIT_COSTS: ", err)
+		}
+		// The clients are identified by their organization token, the other keys being unverified, or else by their IP
+		rateLimitOpts := []ratelimit.Option{
+			ratelimit.WithCosts(costs),
+			ratelimit.WithClientFunc(ratelimit.KeyClient(func(key string) bool {
+				_, ok := orgTokens[key]
+				return ok
+			})),
+		}
+		if v := os.Getenv("RATE_LIMIT_DAILY_QUOTA"); v != "" {
+			quota, err := strconv.Atoi(v)
+			if err != nil {
+				log.Fatal("Unable to parse RATE_LIMIT_DAILY_QUOTA string to int: ", err)
+			}
+			var quotaStore ratelimit.QuotaStore
+			switch v := os.Getenv("RATE_LIMIT_QUOTA_STORE"); v {
+			case "", "memory":
+				quotaStore = ratelimit.NewMemoryQuotaStore()
+			case "tigris":
+				quotaStore = ratelimit.NewTigrisQuotaStore(db)
+			default:
+				log.Fatalf("Unknown RATE_LIMIT_QUOTA_STORE %q, expected memory or tigris\n", v)
+			}
+			if quota > 0 {
+				rateLimitOpts = append(rateLimitOpts, ratelimit.WithDailyQuota(quotaStore, quota))
+			}
+		}
 
 		// Create the routes
 		router := mux.NewRouter()
+		if rateLimit > 0 {
+			limiter, err := ratelimit.NewLimiter(rateLimit, rateLimitWindow)
+			if err != nil {
+				log.Fatal("Unable to create the rate limiter: ", err)
+			}
+			rateLimiter := ratelimit.NewRateLimiter(limiter, rateLimitOpts...)
+			go rateLimiter.Run(workersCtx, time.Minute)
+			router.Use(rateLimiter.Middleware)
+		}
+		router.Use(orgResolver.Middleware)
+		router.Use(policies.Middleware)
+		router.Use(idempotencyGuard.Middleware)
 
-		router.HandleFunc("/breeds", breed.GetAllBreeds(s)).Methods("GET")
-		router.HandleFunc("/breeds/{id}", breed.GetSingleBreed(s)).Methods("GET")
-		router.HandleFunc("/breeds", breed.CreateSingleBreed(s)).Methods("POST")
-		router.HandleFunc("/breeds/{id}", breed.UpdateSingleBreed(s)).Methods("PATCH")
-		router.HandleFunc("/breeds/{id}", breed.DeleteeSingleBreed(s)).Methods("DELETE")
+		// The other routes under /breeds go first, so they aren't taken for a breed ID
+		router.HandleFunc("/breeds/duplicates", breed.GetDuplicateBreeds(s)).Methods("GET")
+		if breedCache != nil {
+			router.HandleFunc("/breeds/cache/stats", breed.GetCacheStats(breedCache)).Methods("GET")
+		}
+		router.HandleFunc("/breeds/events", breed.GetBreedEvents(bus)).Methods("GET")
+		router.HandleFunc("/breeds/events/ws", breed.GetBreedEventsWS(bus)).Methods("GET")
+		crud.Register(router, "/breeds", breed.Routes(s))
+		router.HandleFunc("/breeds/{id}/rename", breed.RenameSingleBreed(s)).Methods("POST")
+		router.HandleFunc("/breeds/{id}/images", media.GetBreedImages(ms)).Methods("GET")
+		router.HandleFunc("/breeds/{id}/images", media.UploadBreedImage(ms)).Methods("POST")
+		router.HandleFunc("/breeds/{id}/images/{imageId}", media.DeleteBreedImage(ms)).Methods("DELETE")
+		router.HandleFunc("/breeds/{id}/images/{imageId}/{variant}", media.GetImageFile(ms)).Methods("GET")
+		router.HandleFunc("/customers", customer.GetAllCustomers(cs)).Methods("GET")
+		router.HandleFunc("/customers/search", customer.SearchCustomers(cs)).Methods("GET")
+		router.HandleFunc("/customers/{id}", customer.GetSingleCustomer(cs)).Methods("GET")
+		router.HandleFunc("/customers", customer.CreateSingleCustomer(cs)).Methods("POST")
+		router.HandleFunc("/customers/{id}", customer.UpdateSingleCustomer(cs)).Methods("PATCH")
+		router.HandleFunc("/customers/{id}", customer.DeleteSingleCustomer(cs)).Methods("DELETE")
+		router.HandleFunc("/resources", resource.GetAllResources(rs)).Methods("GET")
+		router.HandleFunc("/resources/{id}", resource.GetSingleResource(rs)).Methods("GET")
+		router.HandleFunc("/resources", resource.CreateSingleResource(rs)).Methods("POST")
+		router.HandleFunc("/resources/{id}", resource.UpdateSingleResource(rs)).Methods("PATCH")
+		router.HandleFunc("/resources/{id}", resource.DeleteSingleResource(rs)).Methods("DELETE")
+		router.HandleFunc("/availability", booking.GetAvailability(bs)).Methods("GET")
+		router.HandleFunc("/bookings", booking.GetAllBookings(bs)).Methods("GET")
+		router.HandleFunc("/bookings/{id}", booking.GetSingleBooking(bs)).Methods("GET")
+		router.HandleFunc("/bookings", booking.CreateSingleBooking(bs)).Methods("POST")
+		router.HandleFunc("/bookings/{id}/approve", booking.TransitionSingleBooking(bs, booking.ActionApprove)).Methods("POST")
+		router.HandleFunc("/bookings/{id}/pay", booking.TransitionSingleBooking(bs, booking.ActionPay)).Methods("POST")
+		router.HandleFunc("/bookings/{id}/start", booking.TransitionSingleBooking(bs, booking.ActionStart)).Methods("POST")
+		router.HandleFunc("/bookings/{id}/complete", booking.TransitionSingleBooking(bs, booking.ActionComplete)).Methods("POST")
+		router.HandleFunc("/bookings/{id}/cancel", booking.TransitionSingleBooking(bs, booking.ActionCancel)).Methods("POST")
+		router.HandleFunc("/bookings/{id}/reschedule", booking.TransitionSingleBooking(bs, booking.ActionReschedule)).Methods("POST")
+		router.HandleFunc("/bookings/{id}", booking.DeleteSingleBooking(bs)).Methods("DELETE")
+		router.HandleFunc("/bookings/{id}/payments", payment.GetBookingPayments(ps)).Methods("GET")
+		router.HandleFunc("/payments", payment.CreateSinglePayment(ps)).Methods("POST")
+		router.HandleFunc("/payments/webhook", payment.HandleWebhook(ps)).Methods("POST")
+		router.HandleFunc("/payments/{reference}", payment.GetSinglePayment(ps)).Methods("GET")
+		router.HandleFunc("/payments/{reference}/verify", payment.VerifySinglePayment(ps)).Methods("POST")
+		router.HandleFunc("/payments/{reference}/refund", payment.RefundSinglePayment(ps)).Methods("POST")
+		router.HandleFunc("/accounting/invoices/{id}", accounting.GetInvoice(as)).Methods("GET")
+		router.HandleFunc("/accounting/syncs", accounting.GetTenantSyncs(as)).Methods("GET")
+		router.HandleFunc("/accounting/syncs", accounting.SyncTenant(as)).Methods("POST")
+		router.HandleFunc("/accounting/syncs/{id}/retry", accounting.RetrySync(as)).Methods("POST")
+		router.HandleFunc("/webhooks", webhook.GetAllSubscriptions(ws)).Methods("GET")
+		router.HandleFunc("/webhooks", webhook.CreateSingleSubscription(ws)).Methods("POST")
+		router.HandleFunc("/webhooks/dead-letters", webhook.GetDeadLetters(ws)).Methods("GET")
+		router.HandleFunc("/webhooks/{id}", webhook.GetSingleSubscription(ws)).Methods("GET")
+		router.HandleFunc("/webhooks/{id}", webhook.UpdateSingleSubscription(ws)).Methods("PATCH")
+		router.HandleFunc("/webhooks/{id}", webhook.DeleteSingleSubscription(ws)).Methods("DELETE")
+		router.HandleFunc("/webhooks/{id}/test", webhook.TestSubscription(ws)).Methods("POST")
+		router.HandleFunc("/webhooks/{id}/deliveries", webhook.GetSubscriptionDeliveries(ws)).Methods("GET")
+		router.HandleFunc("/graphql", gql.Handler(schema, graphqlLimits)).Methods("GET", "POST")
+		if graphiql {
+			router.HandleFunc("/graphiql", gql.Playground("/graphql")).Methods("GET")
+		}
 
 		// Start the server
 		port := os.Getenv("PORT")
@@ -79,6 +544,8 @@ func main() {
 			port = "8000"
 		}
 		server := &http.Server{Addr: ":" + port, Handler: router}
+		// End the change feed streams, which would otherwise keep the server from shutting down
+		server.RegisterOnShutdown(bus.Close)
 
 		go func() {
 			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -88,15 +555,45 @@ func main() {
 
 		fmt.Printf("Listening on port %s...\n", port)
 
+		// Start the gRPC server beside the HTTP one
+		grpcPort := os.Getenv("GRPC_PORT")
+		if grpcPort == "" {
+			grpcPort = "9000"
+		}
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatalf("Could not listen on gRPC port %s: %v\n", grpcPort, err)
+		}
+		grpcServer := grpc.NewServer(
+			grpc.UnaryInterceptor(orgResolver.UnaryServerInterceptor()),
+			grpc.StreamInterceptor(orgResolver.StreamServerInterceptor()),
+		)
+		breedv1.RegisterBreedServiceServer(grpcServer, breed.NewGRPCServer(s))
+		healthServer := health.NewServer()
+		healthServer.SetServingStatus(breedv1.BreedService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(grpcServer, healthServer)
+		reflection.Register(grpcServer)
+
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatalf("Could not serve gRPC on port %s: %v\n", grpcPort, err)
+			}
+		}()
+
+		fmt.Printf("Listening for gRPC on port %s...\n", grpcPort)
+
 		// Wait for an interrupt signal to gracefully shutdown the server
 		stop := make(chan os.Signal, 1)
 		signal.Notify(stop, os.Interrupt)
 		<-stop
 
 		fmt.Println("Shutting down the server...")
+		healthServer.Shutdown()
+		grpcServer.GracefulStop()
 		if err := server.Shutdown(ctx); err != nil {
 			log.Fatalf("Could not gracefully shutdown the server: %v\n", err)
 		}
+		stopWorkers()
 		fmt.Println("Server stopped")
 	}
 }
@@ -0,0 +1,45 @@
+package media
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Image struct
+//
+// The image files themselves are kept in the BlobStore, keyed by the image ID.
+type Image struct {
+	ID                   uuid.UUID `json:"id" tigris:"primaryKey:1" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	BreedUniqueName      string    `json:"breedUniqueName" tigris:"index" example:"affenpinscher"`
+	Position             int64     `json:"position" example:"1"`
+	OriginalFilename     string    `json:"originalFilename" example:"affenpinscher.jpg"`
+	ContentType          string    `json:"contentType" example:"image/jpeg"`
+	Size                 int64     `json:"size" example:"204800"`
+	Width                int64     `json:"width" example:"1024"`
+	Height               int64     `json:"height" example:"768"`
+	ThumbnailContentType string    `json:"thumbnailContentType" example:"image/jpeg"`
+	URL                  string    `json:"url" example:"/breeds/affenpinscher/images/5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e/original"`
+	ThumbnailURL         string    `json:"thumbnailUrl" example:"/breeds/affenpinscher/images/5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e/thumbnail"`
+	CreatedAt            time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// UploadImage struct
+type UploadImage struct {
+	OriginalFilename string    `json:"originalFilename" validate:"omitempty,max=255" example:"affenpinscher.jpg"`
+	Data             []byte    `json:"-" validate:"required"`
+	CreatedAt        time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// Variant is one of the stored files of an image.
+type Variant string
+
+const (
+	Original  Variant = "original"
+	Thumbnail Variant = "thumbnail"
+)
+
+// key returns the BlobStore key of the image's variant.
+func (i Image) key(v Variant) string {
+	return "images/" + i.ID.String() + "/" + string(v)
+}
@@ -0,0 +1,205 @@
+package media
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/breed"
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+func GetBreedImages(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pathVar(r, "id")
+		qp := crud.PaginationQueryParams(r)
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /breeds/%s/images - PaginationQueryParams: %+v - Took: %v\n", id, qp, time.Since(begin))
+		}(time.Now())
+		data, metadata, err := s.GetBreedImages(r.Context(), id, qp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+
+		// create a new Response struct
+		response := Response{
+			Status:   http.StatusOK,
+			Message:  "success",
+			Data:     data,
+			Metadata: metadata,
+		}
+		writeResponse(w, response)
+	}
+}
+
+func UploadBreedImage(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pathVar(r, "id")
+
+		// Leave some room for the rest of the multipart body.
+		r.Body = http.MaxBytesReader(w, r.Body, s.MaxUploadSize()+1<<20)
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeError(w, ErrTooLarge)
+				return
+			}
+			writeResponse(w, Response{
+				Status:  http.StatusUnprocessableEntity,
+				Message: "missing image file: " + err.Error(),
+			})
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(io.LimitReader(file, s.MaxUploadSize()+1))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		dto := UploadImage{
+			OriginalFilename: header.Filename,
+			Data:             data,
+			CreatedAt:        time.Now().UTC(),
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /breeds/%s/images - Filename: %s - Size: %d - Took: %v\n", id, dto.OriginalFilename, len(dto.Data), time.Since(begin))
+		}(time.Now())
+		image, err := s.UploadBreedImage(r.Context(), id, dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		// create a new Response struct
+		response := Response{
+			Status:  http.StatusCreated,
+			Message: "success",
+			Data:    image,
+		}
+		writeResponse(w, response)
+	}
+}
+
+func GetImageFile(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pathVar(r, "id")
+		imageID, err := uuid.Parse(pathVar(r, "imageId"))
+		if err != nil {
+			writeError(w, ErrNotFound)
+			return
+		}
+		variant := Variant(pathVar(r, "variant"))
+		if variant != Original && variant != Thumbnail {
+			writeError(w, ErrNotFound)
+			return
+		}
+
+		f, contentType, err := s.GetImageFile(r.Context(), id, imageID, variant)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		defer f.Close()
+
+		// Images never change once uploaded.
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		if _, err := io.Copy(w, f); err != nil {
+			log.Printf("Unable to write image file %s/%s: %+v\n", imageID, variant, err)
+		}
+	}
+}
+
+func DeleteBreedImage(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pathVar(r, "id")
+		imageID, err := uuid.Parse(pathVar(r, "imageId"))
+		if err != nil {
+			writeError(w, ErrNotFound)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("DELETE /breeds/%s/images/%s - Took: %v\n", id, imageID, time.Since(begin))
+		}(time.Now())
+		if err := s.DeleteBreedImage(r.Context(), id, imageID); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		// create a new Response struct
+		response := Response{
+			Status:  http.StatusOK,
+			Message: "success",
+			Data:    nil,
+		}
+		writeResponse(w, response)
+	}
+}
+
+func pathVar(r *http.Request, name string) string {
+	v, ok := mux.Vars(r)[name]
+	if !ok {
+		panic(ErrBadRouting)
+	}
+	return v
+}
+
+// writeError maps the error to its HTTP status code and writes it as the response.
+func writeError(w http.ResponseWriter, err error) {
+	var status int
+	switch {
+	case errors.Is(err, breed.ErrNotFound), errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, breed.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrUnsupportedContentType):
+		status = http.StatusUnsupportedMediaType
+	case validation.IsValidationError(err):
+		status = http.StatusUnprocessableEntity
+	default:
+		log.Printf("Unable to handle image request: %+v\n", err)
+		status = http.StatusInternalServerError
+	}
+	writeResponse(w, Response{Status: status, Message: err.Error()})
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	// set the content type to application/json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
+
+	// encode the response struct as JSON and write it to the response writer
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		// handle the error
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,114 @@
+package media
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/sort"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+var (
+	// ErrNotFound is returned when no image matches the given breed and image ID.
+	ErrNotFound = errors.New("image not found")
+)
+
+// Repository is responsible for persisting the image metadata of the breeds.
+type Repository interface {
+	GetBreedImages(ctx context.Context, breedUniqueNames []string, qp params.PaginationQueryParams) ([]Image, *pagination.PaginationData, error)
+	GetSingleImage(ctx context.Context, breedUniqueNames []string, id uuid.UUID) (Image, error)
+	CreateSingleImage(ctx context.Context, image Image) (Image, error)
+	DeleteSingleImage(ctx context.Context, id uuid.UUID) error
+}
+
+type imageRepository struct {
+	collection *tigris.Collection[Image]
+}
+
+// NewImageRepository returns a concrete implementation of the Repository interface.
+func NewImageRepository(db *tigris.Database) Repository {
+	return &imageRepository{collection: tigris.GetCollection[Image](db)}
+}
+
+// GetBreedImages returns the images of a breed, by position.
+//
+// A breed keeps its images across renames, so all of its uniqueNames (current and aliases) are matched.
+func (r imageRepository) GetBreedImages(ctx context.Context, breedUniqueNames []string, qp params.PaginationQueryParams) ([]Image, *pagination.PaginationData, error) {
+	var images []Image = []Image{}
+	f := breedFilter(breedUniqueNames)
+
+	// Add initial pagination data
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	c, err := r.collection.Count(ctx, f)
+	if err != nil {
+		return images, &m, err
+	}
+	if !qp.Paginate {
+		m.Page = 1
+		m.PerPage = c
+	}
+	options := tigris.ReadOptions{
+		Skip:  (m.Page - 1) * m.PerPage,
+		Limit: m.PerPage,
+		Sort:  sort.Ascending("position"),
+	}
+	it, err := r.collection.ReadWithOptions(ctx, f, fields.All, &options)
+	if err != nil {
+		return images, &m, err
+	}
+	defer it.Close()
+
+	var image Image
+	for it.Next(&image) {
+		images = append(images, image)
+	}
+
+	m = pagination.New(m.Page, m.PerPage, c)
+
+	return images, &m, it.Err()
+}
+
+func (r imageRepository) GetSingleImage(ctx context.Context, breedUniqueNames []string, id uuid.UUID) (Image, error) {
+	image, err := r.collection.ReadOne(ctx, filter.Eq("id", id))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return Image{}, ErrNotFound
+	}
+	if err != nil {
+		return Image{}, err
+	}
+	for _, name := range breedUniqueNames {
+		if image.BreedUniqueName == name {
+			return *image, nil
+		}
+	}
+	return Image{}, ErrNotFound
+}
+
+func (r imageRepository) CreateSingleImage(ctx context.Context, image Image) (Image, error) {
+	_, err := r.collection.Insert(ctx, &image)
+	return image, err
+}
+
+func (r imageRepository) DeleteSingleImage(ctx context.Context, id uuid.UUID) error {
+	_, err := r.collection.DeleteOne(ctx, filter.Eq("id", id))
+	return err
+}
+
+func breedFilter(breedUniqueNames []string) filter.Filter {
+	if len(breedUniqueNames) == 1 {
+		return filter.Eq("breedUniqueName", breedUniqueNames[0])
+	}
+	ops := make([]filter.Expr, 0, len(breedUniqueNames))
+	for _, name := range breedUniqueNames {
+		ops = append(ops, filter.Eq("breedUniqueName", name))
+	}
+	return filter.Or(ops...)
+}
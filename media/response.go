@@ -0,0 +1,8 @@
+package media
+
+type Response struct {
+	Status   int         `json:"status"`
+	Message  string      `json:"message"`
+	Data     interface{} `json:"data,omitempty"`
+	Metadata interface{} `json:"metadata,omitempty"`
+}
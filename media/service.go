@@ -0,0 +1,264 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/breed"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+var (
+	// ErrUnsupportedContentType is returned when the uploaded file isn't a JPEG, PNG or GIF image.
+	ErrUnsupportedContentType = errors.New("unsupported image content type, expected one of: image/jpeg, image/png, image/gif")
+	// ErrTooLarge is returned when the uploaded file is larger than the maximum upload size.
+	ErrTooLarge = errors.New("image is too large")
+)
+
+const (
+	// DefaultMaxUploadSize is the default maximum size of an uploaded image in bytes.
+	DefaultMaxUploadSize = 5 << 20
+	// DefaultMaxPixels is the default maximum width×height of an uploaded image, a decoded image taking
+	// 4 bytes per pixel.
+	DefaultMaxPixels = 25_000_000
+)
+
+var allowedContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// BreedGetter looks up the breed an image belongs to, e.g. *breed.Service.
+type BreedGetter interface {
+	GetSingleBreed(ctx context.Context, id string) (breed.Breed, error)
+}
+
+type Service struct {
+	r             Repository
+	blobs         BlobStore
+	breeds        BreedGetter
+	maxUploadSize int64
+	maxPixels     int64
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithMaxUploadSize sets the maximum size of an uploaded image in bytes.
+func WithMaxUploadSize(size int64) Option {
+	return func(s *Service) {
+		s.maxUploadSize = size
+	}
+}
+
+// WithMaxPixels sets the maximum width×height of an uploaded image.
+func WithMaxPixels(pixels int64) Option {
+	return func(s *Service) {
+		s.maxPixels = pixels
+	}
+}
+
+// NewImageService returns a service
+func NewImageService(r Repository, blobs BlobStore, breeds BreedGetter, opts ...Option) *Service {
+	s := &Service{r: r, blobs: blobs, breeds: breeds, maxUploadSize: DefaultMaxUploadSize, maxPixels: DefaultMaxPixels}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// MaxUploadSize returns the maximum size of an uploaded image in bytes.
+func (s *Service) MaxUploadSize() int64 {
+	return s.maxUploadSize
+}
+
+// GetBreedImages godoc
+// @Summary Get all images of a breed
+// @Description Get the images of a breed resource, in their gallery order
+// @Security Bearer
+// @Tags Image
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the breed resource"
+// @Success 200 {object} JSONResultSuccess{data=[]Image} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /breeds/{id}/images [get]
+func (s *Service) GetBreedImages(ctx context.Context, breedID string, qp params.PaginationQueryParams) ([]Image, *pagination.PaginationData, error) {
+	names, err := s.breedUniqueNames(ctx, breedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.r.GetBreedImages(ctx, names, qp)
+}
+
+// UploadBreedImage godoc
+// @Summary Upload an image of a breed
+// @Description Upload a JPEG, PNG or GIF image of a breed resource as multipart form data, a thumbnail is generated for it
+// @Security Bearer
+// @Tags Image
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID of the breed resource"
+// @Param image formData file true "The image file"
+// @Success 201 {object} JSONResultSuccess{data=Image} "Created"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 403 {object} JSONResultFailure "Error: Forbidden"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 413 {object} JSONResultFailure "Error: Request Entity Too Large"
+// @Failure 415 {object} JSONResultFailure "Error: Unsupported Media Type"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /breeds/{id}/images [post]
+func (s *Service) UploadBreedImage(ctx context.Context, breedID string, dto UploadImage) (Image, error) {
+	if int64(len(dto.Data)) > s.maxUploadSize {
+		return Image{}, ErrTooLarge
+	}
+	b, err := s.breeds.GetSingleBreed(ctx, breedID)
+	if err != nil {
+		return Image{}, err
+	}
+	if err := breed.AuthorizeWrite(ctx, b); err != nil {
+		return Image{}, err
+	}
+
+	// Trust the file's content, not the content type sent by the client.
+	contentType := http.DetectContentType(dto.Data)
+	if !allowedContentTypes[contentType] {
+		return Image{}, ErrUnsupportedContentType
+	}
+	// A small file can decode to a huge image, so check the dimensions before decoding it.
+	config, _, err := image.DecodeConfig(bytes.NewReader(dto.Data))
+	if err != nil {
+		return Image{}, fmt.Errorf("%w: %v", ErrUnsupportedContentType, err)
+	}
+	if int64(config.Width)*int64(config.Height) > s.maxPixels {
+		return Image{}, fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrTooLarge, config.Width, config.Height, s.maxPixels)
+	}
+	src, _, err := image.Decode(bytes.NewReader(dto.Data))
+	if err != nil {
+		return Image{}, fmt.Errorf("%w: %v", ErrUnsupportedContentType, err)
+	}
+	thumbnail, thumbnailContentType, err := makeThumbnail(src, contentType)
+	if err != nil {
+		return Image{}, err
+	}
+
+	existing, _, err := s.r.GetBreedImages(ctx, append([]string{b.UniqeName}, b.Aliases...), params.PaginationQueryParams{Paginate: false})
+	if err != nil {
+		return Image{}, err
+	}
+	var position int64 = 1
+	for _, i := range existing {
+		if i.Position >= position {
+			position = i.Position + 1
+		}
+	}
+
+	img := Image{
+		ID:                   uuid.New(),
+		BreedUniqueName:      b.UniqeName,
+		Position:             position,
+		OriginalFilename:     dto.OriginalFilename,
+		ContentType:          contentType,
+		Size:                 int64(len(dto.Data)),
+		Width:                int64(src.Bounds().Dx()),
+		Height:               int64(src.Bounds().Dy()),
+		ThumbnailContentType: thumbnailContentType,
+		CreatedAt:            dto.CreatedAt,
+	}
+	img.URL = fmt.Sprintf("/breeds/%s/images/%s/%s", b.UniqeName, img.ID, Original)
+	img.ThumbnailURL = fmt.Sprintf("/breeds/%s/images/%s/%s", b.UniqeName, img.ID, Thumbnail)
+
+	if err := s.blobs.Put(ctx, img.key(Original), bytes.NewReader(dto.Data)); err != nil {
+		return Image{}, err
+	}
+	if err := s.blobs.Put(ctx, img.key(Thumbnail), bytes.NewReader(thumbnail)); err != nil {
+		_ = s.blobs.Delete(ctx, img.key(Original))
+		return Image{}, err
+	}
+	data, err := s.r.CreateSingleImage(ctx, img)
+	if err != nil {
+		_ = s.blobs.Delete(ctx, img.key(Original))
+		_ = s.blobs.Delete(ctx, img.key(Thumbnail))
+		return Image{}, err
+	}
+	return data, nil
+}
+
+// GetImageFile returns the contents and content type of the original image or its thumbnail.
+func (s *Service) GetImageFile(ctx context.Context, breedID string, id uuid.UUID, v Variant) (io.ReadCloser, string, error) {
+	names, err := s.breedUniqueNames(ctx, breedID)
+	if err != nil {
+		return nil, "", err
+	}
+	img, err := s.r.GetSingleImage(ctx, names, id)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := s.blobs.Get(ctx, img.key(v))
+	if errors.Is(err, ErrBlobNotFound) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if v == Thumbnail {
+		return f, img.ThumbnailContentType, nil
+	}
+	return f, img.ContentType, nil
+}
+
+// DeleteBreedImage godoc
+// @Summary Delete an image of a breed
+// @Description Delete an image of a breed resource and its thumbnail
+// @Security Bearer
+// @Tags Image
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the breed resource"
+// @Param imageId path string true "ID of the image"
+// @Success 200 {object} JSONResultSuccess{} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 403 {object} JSONResultFailure "Error: Forbidden"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /breeds/{id}/images/{imageId} [delete]
+func (s *Service) DeleteBreedImage(ctx context.Context, breedID string, id uuid.UUID) error {
+	b, err := s.breeds.GetSingleBreed(ctx, breedID)
+	if err != nil {
+		return err
+	}
+	if err := breed.AuthorizeWrite(ctx, b); err != nil {
+		return err
+	}
+	img, err := s.r.GetSingleImage(ctx, append([]string{b.UniqeName}, b.Aliases...), id)
+	if err != nil {
+		return err
+	}
+	if err := s.r.DeleteSingleImage(ctx, img.ID); err != nil {
+		return err
+	}
+	// The metadata is gone, so a failure to clean up the files only leaves unreachable blobs.
+	_ = s.blobs.Delete(ctx, img.key(Original))
+	_ = s.blobs.Delete(ctx, img.key(Thumbnail))
+	return nil
+}
+
+// breedUniqueNames returns the current uniqueName and the aliases of the breed.
+func (s *Service) breedUniqueNames(ctx context.Context, breedID string) ([]string, error) {
+	b, err := s.breeds.GetSingleBreed(ctx, breedID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{b.UniqeName}, b.Aliases...), nil
+}
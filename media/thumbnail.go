@@ -0,0 +1,78 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	// Register the decoders of the other accepted content types.
+	_ "image/gif"
+)
+
+// ThumbnailSize is the maximum width and height of a thumbnail.
+const ThumbnailSize = 256
+
+// makeThumbnail scales the image down to fit in ThumbnailSize x ThumbnailSize,
+// keeping its aspect ratio. JPEGs stay JPEGs, anything else becomes a PNG to keep transparency.
+func makeThumbnail(src image.Image, contentType string) ([]byte, string, error) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > ThumbnailSize || h > ThumbnailSize {
+		if w >= h {
+			w, h = ThumbnailSize, max(1, h*ThumbnailSize/b.Dx())
+		} else {
+			w, h = max(1, w*ThumbnailSize/b.Dy()), ThumbnailSize
+		}
+	}
+	dst := scale(src, w, h)
+
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80})
+		return buf.Bytes(), "image/jpeg", err
+	}
+	err := png.Encode(&buf, dst)
+	return buf.Bytes(), "image/png", err
+}
+
+// scale resizes the image by averaging the source pixels covered by each destination pixel.
+func scale(src image.Image, w, h int) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := b.Min.Y + y*b.Dy()/h
+		y1 := max(y0+1, b.Min.Y+(y+1)*b.Dy()/h)
+		for x := 0; x < w; x++ {
+			x0 := b.Min.X + x*b.Dx()/w
+			x1 := max(x0+1, b.Min.X+(x+1)*b.Dx()/w)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(src.At(sx, sy)).(color.NRGBA64)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					bl += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,43 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Range struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+	type Breed struct {
+		UniqeName    string    `json:"uniqueName" tigris:"primaryKey:1,searchIndex"`
+		Name         string    `json:"name" tigris:"index"`
+		URL          string    `json:"url"`
+		CreationType string    `json:"creationType" tigris:"searchIndex"`
+		Group        string    `json:"group" tigris:"index,searchIndex"`
+		Origin       string    `json:"origin" tigris:"index,searchIndex"`
+		Size         string    `json:"size" tigris:"index,searchIndex"`
+		Weight       Range     `json:"weight"`
+		Height       Range     `json:"height"`
+		Lifespan     Range     `json:"lifespan"`
+		Temperament  []string  `json:"temperament" tigris:"searchIndex"`
+		CreatedAt    time.Time `json:"createdAt"`
+		UpdatedAt    time.Time `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 1,
+		Name:    "create_breeds",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Creates the collection, or evolves the schema of an existing one.
+			return db.CreateCollections(ctx, &Breed{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[Breed](db).Drop(ctx)
+		},
+	})
+}
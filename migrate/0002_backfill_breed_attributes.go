@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Range struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+	type Breed struct {
+		UniqeName    string    `json:"uniqueName" tigris:"primaryKey:1,searchIndex"`
+		Name         string    `json:"name" tigris:"index"`
+		URL          string    `json:"url"`
+		CreationType string    `json:"creationType" tigris:"searchIndex"`
+		Group        string    `json:"group" tigris:"index,searchIndex"`
+		Origin       string    `json:"origin" tigris:"index,searchIndex"`
+		Size         string    `json:"size" tigris:"index,searchIndex"`
+		Weight       Range     `json:"weight"`
+		Height       Range     `json:"height"`
+		Lifespan     Range     `json:"lifespan"`
+		Temperament  []string  `json:"temperament" tigris:"searchIndex"`
+		CreatedAt    time.Time `json:"createdAt"`
+		UpdatedAt    time.Time `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 2,
+		Name:    "backfill_breed_attributes",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Breeds stored before the group, origin, size, range and temperament fields
+			// were added have none of them, so give them explicit empty values.
+			c := tigris.GetCollection[Breed](db)
+			it, err := c.Read(ctx, filter.All, fields.All)
+			if err != nil {
+				return err
+			}
+			var ids []string
+			var b Breed
+			for it.Next(&b) {
+				if b.Temperament == nil {
+					ids = append(ids, b.UniqeName)
+				}
+			}
+			it.Close()
+			if err := it.Err(); err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				update := fields.UpdateBuilder().
+					Set("group", "").
+					Set("origin", "").
+					Set("size", "").
+					Set("weight", Range{}).
+					Set("height", Range{}).
+					Set("lifespan", Range{}).
+					Set("temperament", []string{})
+				if _, err := c.UpdateOne(ctx, filter.Eq("uniqueName", id), update); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The fields stay in the schema, only their values are removed.
+			update := fields.UpdateBuilder().
+				Unset("group").
+				Unset("origin").
+				Unset("size").
+				Unset("weight").
+				Unset("height").
+				Unset("lifespan").
+				Unset("temperament")
+			_, err := tigris.GetCollection[Breed](db).Update(ctx, filter.All, update)
+			return err
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Range struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+	type Breed struct {
+		UniqeName      string            `json:"uniqueName" tigris:"primaryKey:1,searchIndex"`
+		Name           string            `json:"name" tigris:"index"`
+		URL            string            `json:"url"`
+		CreationType   string            `json:"creationType" tigris:"searchIndex"`
+		Group          string            `json:"group" tigris:"index,searchIndex"`
+		Origin         string            `json:"origin" tigris:"index,searchIndex"`
+		Size           string            `json:"size" tigris:"index,searchIndex"`
+		Weight         Range             `json:"weight"`
+		Height         Range             `json:"height"`
+		Lifespan       Range             `json:"lifespan"`
+		Temperament    []string          `json:"temperament" tigris:"searchIndex"`
+		Aliases        []string          `json:"aliases" tigris:"searchIndex"`
+		LocalizedNames map[string]string `json:"localizedNames" tigris:"searchIndex"`
+		CreatedAt      time.Time         `json:"createdAt"`
+		UpdatedAt      time.Time         `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 3,
+		Name:    "add_breed_aliases",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Adds the aliases and localizedNames fields to the schema and search index.
+			if err := db.CreateCollections(ctx, &Breed{}); err != nil {
+				return err
+			}
+
+			c := tigris.GetCollection[Breed](db)
+			it, err := c.Read(ctx, filter.All, fields.All)
+			if err != nil {
+				return err
+			}
+			var ids []string
+			var b Breed
+			for it.Next(&b) {
+				if b.Aliases == nil {
+					ids = append(ids, b.UniqeName)
+				}
+			}
+			it.Close()
+			if err := it.Err(); err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				update := fields.UpdateBuilder().
+					Set("aliases", []string{}).
+					Set("localizedNames", map[string]string{})
+				if _, err := c.UpdateOne(ctx, filter.Eq("uniqueName", id), update); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The fields stay in the schema, only their values are removed.
+			update := fields.UpdateBuilder().
+				Unset("aliases").
+				Unset("localizedNames")
+			_, err := tigris.GetCollection[Breed](db).Update(ctx, filter.All, update)
+			return err
+		},
+	})
+}
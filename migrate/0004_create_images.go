@@ -0,0 +1,39 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Image struct {
+		ID                   uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		BreedUniqueName      string    `json:"breedUniqueName" tigris:"index"`
+		Position             int64     `json:"position"`
+		OriginalFilename     string    `json:"originalFilename"`
+		ContentType          string    `json:"contentType"`
+		Size                 int64     `json:"size"`
+		Width                int64     `json:"width"`
+		Height               int64     `json:"height"`
+		ThumbnailContentType string    `json:"thumbnailContentType"`
+		URL                  string    `json:"url"`
+		ThumbnailURL         string    `json:"thumbnailUrl"`
+		CreatedAt            time.Time `json:"createdAt"`
+	}
+
+	Register(Migration{
+		Version: 4,
+		Name:    "create_images",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &Image{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The image files in the blob store are left in place.
+			return tigris.GetCollection[Image](db).Drop(ctx)
+		},
+	})
+}
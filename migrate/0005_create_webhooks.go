@@ -0,0 +1,62 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Subscription struct {
+		ID          uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		URL         string    `json:"url"`
+		Events      []string  `json:"events"`
+		Description string    `json:"description"`
+		Secret      string    `json:"secret,omitempty"`
+		Active      bool      `json:"active" tigris:"index"`
+		CreatedAt   time.Time `json:"createdAt"`
+		UpdatedAt   time.Time `json:"updatedAt"`
+	}
+	type Delivery struct {
+		ID             uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		SubscriptionID uuid.UUID `json:"subscriptionId" tigris:"index"`
+		EventID        int64     `json:"eventId"`
+		EventType      string    `json:"eventType"`
+		Attempt        int64     `json:"attempt"`
+		StatusCode     int64     `json:"statusCode"`
+		Error          string    `json:"error,omitempty"`
+		Succeeded      bool      `json:"succeeded"`
+		DurationMs     int64     `json:"durationMs"`
+		CreatedAt      time.Time `json:"createdAt" tigris:"index"`
+	}
+	type DeadLetter struct {
+		ID             uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		SubscriptionID uuid.UUID `json:"subscriptionId" tigris:"index"`
+		EventID        int64     `json:"eventId"`
+		EventType      string    `json:"eventType"`
+		Payload        string    `json:"payload"`
+		Attempts       int64     `json:"attempts"`
+		LastError      string    `json:"lastError"`
+		CreatedAt      time.Time `json:"createdAt" tigris:"index"`
+	}
+
+	Register(Migration{
+		Version: 5,
+		Name:    "create_webhooks",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &Subscription{}, &Delivery{}, &DeadLetter{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			if err := tigris.GetCollection[DeadLetter](db).Drop(ctx); err != nil {
+				return err
+			}
+			if err := tigris.GetCollection[Delivery](db).Drop(ctx); err != nil {
+				return err
+			}
+			return tigris.GetCollection[Subscription](db).Drop(ctx)
+		},
+	})
+}
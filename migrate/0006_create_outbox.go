@@ -0,0 +1,57 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type OutboxRecord struct {
+		ID        uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		Type      string    `json:"type"`
+		Subject   string    `json:"subject"`
+		Payload   string    `json:"payload"`
+		CreatedAt time.Time `json:"createdAt" tigris:"index"`
+	}
+	type Delivery struct {
+		ID             uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		SubscriptionID uuid.UUID `json:"subscriptionId" tigris:"index"`
+		EventID        int64     `json:"eventId,omitempty"`
+		EventKey       string    `json:"eventKey"`
+		EventType      string    `json:"eventType"`
+		Attempt        int64     `json:"attempt"`
+		StatusCode     int64     `json:"statusCode"`
+		Error          string    `json:"error,omitempty"`
+		Succeeded      bool      `json:"succeeded"`
+		DurationMs     int64     `json:"durationMs"`
+		CreatedAt      time.Time `json:"createdAt" tigris:"index"`
+	}
+	type DeadLetter struct {
+		ID             uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		SubscriptionID uuid.UUID `json:"subscriptionId" tigris:"index"`
+		EventID        int64     `json:"eventId,omitempty"`
+		EventKey       string    `json:"eventKey"`
+		EventType      string    `json:"eventType"`
+		Payload        string    `json:"payload"`
+		Attempts       int64     `json:"attempts"`
+		LastError      string    `json:"lastError"`
+		CreatedAt      time.Time `json:"createdAt" tigris:"index"`
+	}
+
+	Register(Migration{
+		Version: 6,
+		Name:    "create_outbox",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Also adds the eventKey field of the relayed events to the webhook deliveries and dead letters.
+			return db.CreateCollections(ctx, &OutboxRecord{}, &Delivery{}, &DeadLetter{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The eventKey fields stay in the webhook schemas.
+			return tigris.GetCollection[OutboxRecord](db).Drop(ctx)
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Header struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	}
+	type IdempotencyRecord struct {
+		Key         string    `json:"key" tigris:"primaryKey:1"`
+		Fingerprint string    `json:"fingerprint"`
+		Completed   bool      `json:"completed"`
+		Status      int       `json:"status,omitempty"`
+		Header      []Header  `json:"header,omitempty"`
+		Body        []byte    `json:"body,omitempty"`
+		CreatedAt   time.Time `json:"createdAt"`
+		ExpiresAt   time.Time `json:"expiresAt" tigris:"index"`
+	}
+
+	Register(Migration{
+		Version: 7,
+		Name:    "create_idempotency_records",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &IdempotencyRecord{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[IdempotencyRecord](db).Drop(ctx)
+		},
+	})
+}
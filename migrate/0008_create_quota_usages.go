@@ -0,0 +1,30 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type QuotaUsage struct {
+		ID        string    `json:"id" tigris:"primaryKey:1"`
+		Client    string    `json:"client"`
+		Day       time.Time `json:"day" tigris:"index"`
+		Used      int       `json:"used"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 8,
+		Name:    "create_quota_usages",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &QuotaUsage{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[QuotaUsage](db).Drop(ctx)
+		},
+	})
+}
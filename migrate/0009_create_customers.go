@@ -0,0 +1,32 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Customer struct {
+		ID        uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		Name      string    `json:"name" tigris:"index,searchIndex"`
+		Email     string    `json:"email" tigris:"index,searchIndex"`
+		Phone     string    `json:"phone"`
+		CreatedAt time.Time `json:"createdAt" tigris:"index"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 9,
+		Name:    "create_customers",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &Customer{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[Customer](db).Drop(ctx)
+		},
+	})
+}
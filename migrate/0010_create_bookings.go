@@ -0,0 +1,45 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Transition struct {
+		Action string    `json:"action"`
+		From   string    `json:"from"`
+		To     string    `json:"to"`
+		Reason string    `json:"reason,omitempty"`
+		At     time.Time `json:"at"`
+	}
+	type Booking struct {
+		ID            uuid.UUID    `json:"id" tigris:"primaryKey:1"`
+		CustomerID    uuid.UUID    `json:"customerId" tigris:"index"`
+		BreedID       string       `json:"breedId" tigris:"index"`
+		BookedFor     time.Time    `json:"bookedFor" tigris:"index"`
+		BookedForTime string       `json:"bookedForTime" tigris:"index"`
+		State         string       `json:"state" tigris:"index"`
+		Approved      bool         `json:"approved" tigris:"index"`
+		Paid          bool         `json:"paid" tigris:"index"`
+		Notes         string       `json:"notes"`
+		History       []Transition `json:"history"`
+		CreatedAt     time.Time    `json:"createdAt" tigris:"index"`
+		UpdatedAt     time.Time    `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 10,
+		Name:    "create_bookings",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &Booking{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[Booking](db).Drop(ctx)
+		},
+	})
+}
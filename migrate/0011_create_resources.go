@@ -0,0 +1,64 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type WorkingHours struct {
+		Weekday string `json:"weekday"`
+		Open    string `json:"open"`
+		Close   string `json:"close"`
+	}
+	type Resource struct {
+		ID           uuid.UUID      `json:"id" tigris:"primaryKey:1"`
+		Name         string         `json:"name" tigris:"index"`
+		Capacity     int            `json:"capacity"`
+		TimeZone     string         `json:"timeZone"`
+		SlotInterval int            `json:"slotInterval"`
+		WorkingHours []WorkingHours `json:"workingHours"`
+		CreatedAt    time.Time      `json:"createdAt"`
+		UpdatedAt    time.Time      `json:"updatedAt"`
+	}
+	type Transition struct {
+		Action string    `json:"action"`
+		From   string    `json:"from"`
+		To     string    `json:"to"`
+		Reason string    `json:"reason,omitempty"`
+		At     time.Time `json:"at"`
+	}
+	type Booking struct {
+		ID            uuid.UUID    `json:"id" tigris:"primaryKey:1"`
+		CustomerID    uuid.UUID    `json:"customerId" tigris:"index"`
+		BreedID       string       `json:"breedId" tigris:"index"`
+		ResourceID    uuid.UUID    `json:"resourceId" tigris:"index"`
+		BookedFor     time.Time    `json:"bookedFor" tigris:"index"`
+		BookedForTime string       `json:"bookedForTime" tigris:"index"`
+		Duration      int          `json:"duration"`
+		State         string       `json:"state" tigris:"index"`
+		Approved      bool         `json:"approved" tigris:"index"`
+		Paid          bool         `json:"paid" tigris:"index"`
+		Notes         string       `json:"notes"`
+		History       []Transition `json:"history"`
+		CreatedAt     time.Time    `json:"createdAt" tigris:"index"`
+		UpdatedAt     time.Time    `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 11,
+		Name:    "create_resources",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Also adds the resourceId and duration fields to the bookings.
+			return db.CreateCollections(ctx, &Resource{}, &Booking{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The resourceId and duration fields stay in the booking schema.
+			return tigris.GetCollection[Resource](db).Drop(ctx)
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Payment struct {
+		Reference        string    `json:"reference" tigris:"primaryKey:1"`
+		BookingID        uuid.UUID `json:"bookingId" tigris:"index"`
+		Email            string    `json:"email"`
+		Amount           int64     `json:"amount"`
+		Currency         string    `json:"currency"`
+		Status           string    `json:"status" tigris:"index"`
+		Test             bool      `json:"test"`
+		AuthorizationURL string    `json:"authorizationUrl"`
+		RefundedAmount   int64     `json:"refundedAmount"`
+		PaidAt           time.Time `json:"paidAt"`
+		CreatedAt        time.Time `json:"createdAt" tigris:"index"`
+		UpdatedAt        time.Time `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 12,
+		Name:    "create_payments",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &Payment{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[Payment](db).Drop(ctx)
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type InvoiceSync struct {
+		ID            string    `json:"id" tigris:"primaryKey:1"`
+		TenantID      string    `json:"tenantId" tigris:"index"`
+		BookingID     uuid.UUID `json:"bookingId" tigris:"index"`
+		InvoiceNumber string    `json:"invoiceNumber"`
+		Status        string    `json:"status" tigris:"index"`
+		Fingerprint   string    `json:"fingerprint"`
+		ExternalID    string    `json:"externalId"`
+		Attempts      int       `json:"attempts"`
+		LastError     string    `json:"lastError,omitempty"`
+		NextAttemptAt time.Time `json:"nextAttemptAt"`
+		SyncedAt      time.Time `json:"syncedAt"`
+		CreatedAt     time.Time `json:"createdAt"`
+		UpdatedAt     time.Time `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 13,
+		Name:    "create_invoice_syncs",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &InvoiceSync{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[InvoiceSync](db).Drop(ctx)
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema of the breeds as of this migration.
+	type Range struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+	type Breed struct {
+		UniqeName      string            `json:"uniqueName" tigris:"primaryKey:1,searchIndex"`
+		OrganizationID string            `json:"organizationId" tigris:"index,searchIndex"`
+		Name           string            `json:"name" tigris:"index"`
+		URL            string            `json:"url"`
+		CreationType   string            `json:"creationType" tigris:"searchIndex"`
+		Group          string            `json:"group" tigris:"index,searchIndex"`
+		Origin         string            `json:"origin" tigris:"index,searchIndex"`
+		Size           string            `json:"size" tigris:"index,searchIndex"`
+		Weight         Range             `json:"weight"`
+		Height         Range             `json:"height"`
+		Lifespan       Range             `json:"lifespan"`
+		Temperament    []string          `json:"temperament" tigris:"searchIndex"`
+		Aliases        []string          `json:"aliases" tigris:"searchIndex"`
+		LocalizedNames map[string]string `json:"localizedNames" tigris:"searchIndex"`
+		CreatedAt      time.Time         `json:"createdAt"`
+		UpdatedAt      time.Time         `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 14,
+		Name:    "add_breed_organizations",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Adds the organizationId field to the schema and search index.
+			if err := db.CreateCollections(ctx, &Breed{}); err != nil {
+				return err
+			}
+
+			// The existing breeds become shared, the filters don't match a missing organizationId.
+			update := fields.UpdateBuilder().Set("organizationId", "")
+			_, err := tigris.GetCollection[Breed](db).Update(ctx, filter.All, update)
+			return err
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The field stays in the schema, only its values are removed, so the private breeds are shared again.
+			update := fields.UpdateBuilder().Unset("organizationId")
+			_, err := tigris.GetCollection[Breed](db).Update(ctx, filter.All, update)
+			return err
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type BreedDeletion struct {
+		ID             string    `json:"id" tigris:"primaryKey:1"`
+		OrganizationID string    `json:"organizationId" tigris:"index"`
+		DeletedAt      time.Time `json:"deletedAt"`
+	}
+
+	Register(Migration{
+		Version: 15,
+		Name:    "create_breed_deletions",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &BreedDeletion{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[BreedDeletion](db).Drop(ctx)
+		},
+	})
+}
@@ -0,0 +1,48 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Subscription struct {
+		ID             uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		OrganizationID string    `json:"organizationId" tigris:"index"`
+		URL            string    `json:"url"`
+		Events         []string  `json:"events"`
+		Description    string    `json:"description"`
+		Secret         string    `json:"secret,omitempty"`
+		Active         bool      `json:"active" tigris:"index"`
+		CreatedAt      time.Time `json:"createdAt"`
+		UpdatedAt      time.Time `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 16,
+		Name:    "add_subscription_organizations",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Adds the organizationId field to the schema.
+			if err := db.CreateCollections(ctx, &Subscription{}); err != nil {
+				return err
+			}
+
+			// The existing subscriptions belong to no organization, the filters don't match a missing organizationId.
+			update := fields.UpdateBuilder().Set("organizationId", "")
+			_, err := tigris.GetCollection[Subscription](db).Update(ctx, filter.All, update)
+			return err
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The field stays in the schema, only its values are removed.
+			update := fields.UpdateBuilder().Unset("organizationId")
+			_, err := tigris.GetCollection[Subscription](db).Update(ctx, filter.All, update)
+			return err
+		},
+	})
+}
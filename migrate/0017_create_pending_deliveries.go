@@ -0,0 +1,35 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type PendingDelivery struct {
+		ID             uuid.UUID `json:"id" tigris:"primaryKey:1"`
+		SubscriptionID uuid.UUID `json:"subscriptionId" tigris:"index"`
+		OrganizationID string    `json:"organizationId"`
+		EventKey       string    `json:"eventKey"`
+		EventType      string    `json:"eventType"`
+		Payload        string    `json:"payload"`
+		Attempt        int64     `json:"attempt"`
+		NextAttemptAt  time.Time `json:"nextAttemptAt" tigris:"index"`
+		CreatedAt      time.Time `json:"createdAt"`
+	}
+
+	Register(Migration{
+		Version: 17,
+		Name:    "create_pending_deliveries",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			return db.CreateCollections(ctx, &PendingDelivery{})
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			return tigris.GetCollection[PendingDelivery](db).Drop(ctx)
+		},
+	})
+}
@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type WorkingHours struct {
+		Weekday string `json:"weekday"`
+		Open    string `json:"open"`
+		Close   string `json:"close"`
+	}
+	type Resource struct {
+		ID           uuid.UUID      `json:"id" tigris:"primaryKey:1"`
+		Name         string         `json:"name" tigris:"index"`
+		Capacity     int            `json:"capacity"`
+		TimeZone     string         `json:"timeZone"`
+		SlotInterval int            `json:"slotInterval"`
+		WorkingHours []WorkingHours `json:"workingHours"`
+		HourlyRate   int64          `json:"hourlyRate"`
+		Currency     string         `json:"currency"`
+		CreatedAt    time.Time      `json:"createdAt"`
+		UpdatedAt    time.Time      `json:"updatedAt"`
+	}
+	type Transition struct {
+		Action string    `json:"action"`
+		From   string    `json:"from"`
+		To     string    `json:"to"`
+		Reason string    `json:"reason,omitempty"`
+		At     time.Time `json:"at"`
+	}
+	type Booking struct {
+		ID            uuid.UUID    `json:"id" tigris:"primaryKey:1"`
+		CustomerID    uuid.UUID    `json:"customerId" tigris:"index"`
+		BreedID       string       `json:"breedId" tigris:"index"`
+		ResourceID    uuid.UUID    `json:"resourceId" tigris:"index"`
+		BookedFor     time.Time    `json:"bookedFor" tigris:"index"`
+		BookedForTime string       `json:"bookedForTime" tigris:"index"`
+		Duration      int          `json:"duration"`
+		Amount        int64        `json:"amount"`
+		Currency      string       `json:"currency"`
+		State         string       `json:"state" tigris:"index"`
+		Approved      bool         `json:"approved" tigris:"index"`
+		Paid          bool         `json:"paid" tigris:"index"`
+		Notes         string       `json:"notes"`
+		History       []Transition `json:"history"`
+		CreatedAt     time.Time    `json:"createdAt" tigris:"index"`
+		UpdatedAt     time.Time    `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 18,
+		Name:    "add_booking_amounts",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Adds the hourlyRate and currency fields to the resources, and the amount and currency fields to the bookings.
+			if err := db.CreateCollections(ctx, &Resource{}, &Booking{}); err != nil {
+				return err
+			}
+
+			// The existing resources are free until given a rate, and so are the bookings made on them.
+			update := fields.UpdateBuilder().Set("hourlyRate", 0).Set("currency", "NGN")
+			if _, err := tigris.GetCollection[Resource](db).Update(ctx, filter.All, update); err != nil {
+				return err
+			}
+			update = fields.UpdateBuilder().Set("amount", 0).Set("currency", "NGN")
+			_, err := tigris.GetCollection[Booking](db).Update(ctx, filter.All, update)
+			return err
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The fields stay in the schemas, only their values are removed.
+			update := fields.UpdateBuilder().Unset("hourlyRate").Unset("currency")
+			if _, err := tigris.GetCollection[Resource](db).Update(ctx, filter.All, update); err != nil {
+				return err
+			}
+			update = fields.UpdateBuilder().Unset("amount").Unset("currency")
+			_, err := tigris.GetCollection[Booking](db).Update(ctx, filter.All, update)
+			return err
+		},
+	})
+}
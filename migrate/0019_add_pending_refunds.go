@@ -0,0 +1,51 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+func init() {
+	// The schema as of this migration.
+	type Payment struct {
+		Reference           string    `json:"reference" tigris:"primaryKey:1"`
+		BookingID           uuid.UUID `json:"bookingId" tigris:"index"`
+		Email               string    `json:"email"`
+		Amount              int64     `json:"amount"`
+		Currency            string    `json:"currency"`
+		Status              string    `json:"status" tigris:"index"`
+		Test                bool      `json:"test"`
+		AuthorizationURL    string    `json:"authorizationUrl"`
+		RefundedAmount      int64     `json:"refundedAmount"`
+		PendingRefundAmount int64     `json:"pendingRefundAmount"`
+		PaidAt              time.Time `json:"paidAt"`
+		CreatedAt           time.Time `json:"createdAt" tigris:"index"`
+		UpdatedAt           time.Time `json:"updatedAt"`
+	}
+
+	Register(Migration{
+		Version: 19,
+		Name:    "add_pending_refunds",
+		Up: func(ctx context.Context, db *tigris.Database) error {
+			// Adds the pendingRefundAmount field to the schema.
+			if err := db.CreateCollections(ctx, &Payment{}); err != nil {
+				return err
+			}
+
+			update := fields.UpdateBuilder().Set("pendingRefundAmount", 0)
+			_, err := tigris.GetCollection[Payment](db).Update(ctx, filter.All, update)
+			return err
+		},
+		Down: func(ctx context.Context, db *tigris.Database) error {
+			// The field stays in the schema, only its values are removed.
+			update := fields.UpdateBuilder().Unset("pendingRefundAmount")
+			_, err := tigris.GetCollection[Payment](db).Update(ctx, filter.All, update)
+			return err
+		},
+	})
+}
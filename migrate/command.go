@@ -0,0 +1,58 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Run executes a `migrate` CLI command: `up`, `down [n]` or `status`.
+func Run(ctx context.Context, m *Migrator, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command, expected one of: up, down [n], status")
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, mg := range done {
+			fmt.Printf("Applied migration %d_%s\n", mg.Version, mg.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("No pending migrations")
+		}
+		return nil
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			var err error
+			n, err = strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations to roll back: %q", args[1])
+			}
+		}
+		done, err := m.Down(ctx, n)
+		for _, mg := range done {
+			fmt.Printf("Rolled back migration %d_%s\n", mg.Version, mg.Name)
+		}
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				fmt.Printf("[x] %d_%s (applied at %s)\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02T15:04:05Z"))
+			} else {
+				fmt.Printf("[ ] %d_%s\n", s.Version, s.Name)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected one of: up, down [n], status", args[0])
+	}
+}
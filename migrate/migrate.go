@@ -0,0 +1,197 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+var (
+	// ErrNoMigrationsApplied is returned when rolling back without any applied migrations.
+	ErrNoMigrationsApplied = errors.New("no migrations have been applied")
+)
+
+// Migration is a single, versioned change to the database schema and/or its data.
+//
+// Up and Down run inside a Tigris transaction together with the bookkeeping write,
+// so a failed migration leaves neither its changes nor its record behind.
+//
+// Up and Down declare their own copies of the models, as they were when the migration was written,
+// so later changes to the models don't change what an old migration does.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(ctx context.Context, db *tigris.Database) error
+	Down    func(ctx context.Context, db *tigris.Database) error
+}
+
+// _Migration is a record of an applied migration.
+// The leading underscore makes Tigris name the collection `_migrations`.
+type _Migration struct {
+	Version   int64     `json:"version" tigris:"primaryKey:1"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// Status describes whether a registered migration has been applied.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+var registry = map[int64]Migration{}
+
+// Register adds a migration to the set run by every Migrator.
+// It is meant to be called from the init function of the file declaring the migration.
+func Register(m Migration) {
+	if m.Version < 1 {
+		panic(fmt.Sprintf("migration %q must have a positive version", m.Name))
+	}
+	if m.Up == nil || m.Down == nil {
+		panic(fmt.Sprintf("migration %d_%s must define both Up and Down", m.Version, m.Name))
+	}
+	if existing, ok := registry[m.Version]; ok {
+		panic(fmt.Sprintf("migration version %d is registered twice (%s and %s)", m.Version, existing.Name, m.Name))
+	}
+	registry[m.Version] = m
+}
+
+// Migrator applies and rolls back the registered migrations in version order.
+type Migrator struct {
+	db         *tigris.Database
+	collection *tigris.Collection[_Migration]
+	migrations []Migration
+}
+
+// NewMigrator returns a Migrator for the database, creating the `_migrations` collection if needed.
+func NewMigrator(ctx context.Context, db *tigris.Database) (*Migrator, error) {
+	if err := db.CreateCollections(ctx, &_Migration{}); err != nil {
+		return nil, err
+	}
+	migrations := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return &Migrator{
+		db:         db,
+		collection: tigris.GetCollection[_Migration](db),
+		migrations: migrations,
+	}, nil
+}
+
+// Status returns every registered migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		s := Status{Version: mg.Version, Name: mg.Name}
+		if a, ok := applied[mg.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = a.AppliedAt
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// Pending returns the registered migrations which have not been applied yet, in version order.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, mg := range m.migrations {
+		if _, ok := applied[mg.Version]; !ok {
+			pending = append(pending, mg)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies all pending migrations in version order, stopping at the first failure.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, mg := range pending {
+		mg := mg
+		err := m.db.Tx(ctx, func(ctx context.Context) error {
+			if err := mg.Up(ctx, m.db); err != nil {
+				return err
+			}
+			_, err := m.collection.Insert(ctx, &_Migration{
+				Version:   mg.Version,
+				Name:      mg.Name,
+				AppliedAt: time.Now().UTC(),
+			})
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s failed: %w", mg.Version, mg.Name, err)
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// Down rolls back the last n applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(applied) == 0 {
+		return nil, ErrNoMigrationsApplied
+	}
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < n; i-- {
+		mg := m.migrations[i]
+		if _, ok := applied[mg.Version]; !ok {
+			continue
+		}
+		err := m.db.Tx(ctx, func(ctx context.Context) error {
+			if err := mg.Down(ctx, m.db); err != nil {
+				return err
+			}
+			_, err := m.collection.DeleteOne(ctx, filter.Eq("version", mg.Version))
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("rollback of migration %d_%s failed: %w", mg.Version, mg.Name, err)
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]_Migration, error) {
+	it, err := m.collection.Read(ctx, filter.All, fields.All)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	applied := map[int64]_Migration{}
+	var record _Migration
+	for it.Next(&record) {
+		applied[record.Version] = record
+	}
+	return applied, it.Err()
+}
@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/events"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/sort"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+// OutboxRecord is an event waiting to be relayed to the publishers.
+// The type name makes Tigris name the collection `outbox_records`.
+//
+// The ID doubles as the dedupe key of the event: a record is relayed at least once,
+// so consumers can see it again after a crash or a failed publisher, always with the same key.
+type OutboxRecord struct {
+	ID        uuid.UUID `json:"id" tigris:"primaryKey:1"`
+	Type      string    `json:"type"`
+	Subject   string    `json:"subject"`
+	Payload   string    `json:"payload"`
+	CreatedAt time.Time `json:"createdAt" tigris:"index"`
+}
+
+// Event returns the event relayed for the record, its data being the raw JSON payload.
+func (r OutboxRecord) Event() events.Event {
+	return events.Event{
+		Key:     r.ID.String(),
+		Type:    r.Type,
+		Subject: r.Subject,
+		Time:    r.CreatedAt,
+		Data:    json.RawMessage(r.Payload),
+	}
+}
+
+// Append writes an event to the outbox.
+//
+// It is meant to be called with the context of the Tigris transaction writing the change
+// the event is about, so the event is stored if and only if the change is.
+func Append(ctx context.Context, db *tigris.Database, eventType, subject string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = tigris.GetCollection[OutboxRecord](db).Insert(ctx, &OutboxRecord{
+		ID:        uuid.New(),
+		Type:      eventType,
+		Subject:   subject,
+		Payload:   string(payload),
+		CreatedAt: time.Now().UTC(),
+	})
+	return err
+}
+
+// Repository reads the pending outbox records for the relay.
+type Repository interface {
+	GetPendingRecords(ctx context.Context, limit int) ([]OutboxRecord, error)
+	DeleteSingleRecord(ctx context.Context, id uuid.UUID) error
+}
+
+type outboxRepository struct {
+	collection *tigris.Collection[OutboxRecord]
+}
+
+// NewOutboxRepository returns a concrete implementation of the Repository interface.
+func NewOutboxRepository(db *tigris.Database) Repository {
+	return &outboxRepository{collection: tigris.GetCollection[OutboxRecord](db)}
+}
+
+// GetPendingRecords returns the oldest records of the outbox, in the order they were appended.
+func (r outboxRepository) GetPendingRecords(ctx context.Context, limit int) ([]OutboxRecord, error) {
+	var records []OutboxRecord = []OutboxRecord{}
+	options := tigris.ReadOptions{
+		Limit: int64(limit),
+		Sort:  sort.Ascending("createdAt"),
+	}
+	it, err := r.collection.ReadWithOptions(ctx, filter.All, fields.All, &options)
+	if err != nil {
+		return records, err
+	}
+	defer it.Close()
+
+	var record OutboxRecord
+	for it.Next(&record) {
+		records = append(records, record)
+	}
+	return records, it.Err()
+}
+
+func (r outboxRepository) DeleteSingleRecord(ctx context.Context, id uuid.UUID) error {
+	_, err := r.collection.DeleteOne(ctx, filter.Eq("id", id))
+	return err
+}
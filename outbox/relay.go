@@ -0,0 +1,116 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/events"
+)
+
+const (
+	// DefaultPollInterval is the default delay between two reads of the outbox.
+	DefaultPollInterval = time.Second
+	// DefaultBatchSize is the default number of records read from the outbox at once.
+	DefaultBatchSize = 100
+)
+
+// Publisher receives the events relayed from the outbox.
+//
+// An event may be published more than once, e.g. when another publisher failed,
+// so publishers and their consumers should dedupe events by their Key.
+type Publisher interface {
+	Publish(ctx context.Context, e events.Event) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface.
+type PublisherFunc func(ctx context.Context, e events.Event) error
+
+// Publish calls f(ctx, e).
+func (f PublisherFunc) Publish(ctx context.Context, e events.Event) error {
+	return f(ctx, e)
+}
+
+// Relay hands the outbox records to the publishers, in the order they were appended.
+//
+// A record is deleted once every publisher has accepted it. When one fails, the relay
+// stops and retries the record on the next poll, so events are published at least once.
+type Relay struct {
+	r            Repository
+	publishers   []Publisher
+	pollInterval time.Duration
+	batchSize    int
+}
+
+// RelayOption configures a Relay.
+type RelayOption func(*Relay)
+
+// WithPollInterval sets the delay between two reads of the outbox.
+func WithPollInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.pollInterval = d
+	}
+}
+
+// WithBatchSize sets the number of records read from the outbox at once.
+func WithBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		r.batchSize = n
+	}
+}
+
+// NewRelay returns a relay
+func NewRelay(r Repository, publishers []Publisher, opts ...RelayOption) *Relay {
+	relay := &Relay{
+		r:            r,
+		publishers:   publishers,
+		pollInterval: DefaultPollInterval,
+		batchSize:    DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(relay)
+	}
+	return relay
+}
+
+// Run relays the outbox records until the context is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		// Drain the outbox before waiting for the next poll
+		for ctx.Err() == nil && r.relayBatch(ctx) == r.batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayBatch relays a batch of records, returning how many were relayed.
+func (r *Relay) relayBatch(ctx context.Context) int {
+	records, err := r.r.GetPendingRecords(ctx, r.batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Unable to read the outbox: %+v\n", err)
+		}
+		return 0
+	}
+	for i, record := range records {
+		e := record.Event()
+		for _, p := range r.publishers {
+			if err := p.Publish(ctx, e); err != nil {
+				log.Printf("Unable to publish outbox record %s (%s): %+v\n", record.ID, record.Type, err)
+				return i
+			}
+		}
+		if err := r.r.DeleteSingleRecord(ctx, record.ID); err != nil {
+			log.Printf("Unable to delete outbox record %s, it will be published again: %+v\n", record.ID, err)
+			return i
+		}
+	}
+	return len(records)
+}
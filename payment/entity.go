@@ -0,0 +1,48 @@
+package payment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment struct
+//
+// The amounts are in the currency's minor unit, e.g. kobo for NGN. A payment made with the provider's test keys is a
+// test payment, verified and refunded with the same keys. PendingRefundAmount is reserved by the refunds sent to the
+// provider and not confirmed yet, so concurrent refunds can't exceed the amount.
+type Payment struct {
+	Reference           string    `json:"reference" tigris:"primaryKey:1" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	BookingID           uuid.UUID `json:"bookingId" tigris:"index" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	Email               string    `json:"email" example:"jane.doe@example.com"`
+	Amount              int64     `json:"amount" example:"500000"`
+	Currency            string    `json:"currency" example:"NGN"`
+	Status              string    `json:"status" tigris:"index" example:"pending"`
+	Test                bool      `json:"test" example:"false"`
+	AuthorizationURL    string    `json:"authorizationUrl" example:"https://checkout.paystack.com/0peioxfhpn"`
+	RefundedAmount      int64     `json:"refundedAmount" example:"0"`
+	PendingRefundAmount int64     `json:"pendingRefundAmount" example:"0"`
+	PaidAt              time.Time `json:"paidAt" example:"2023-01-05T00:00:00.000Z"`
+	CreatedAt           time.Time `json:"createdAt" tigris:"index" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt           time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// CreatePayment struct
+//
+// The amount and currency are those due by the booking. When given, they are only checked against them.
+type CreatePayment struct {
+	BookingID   uuid.UUID `json:"bookingId" validate:"required" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	Amount      int64     `json:"amount" validate:"omitempty,min=1" example:"500000"`
+	Currency    string    `json:"currency" validate:"omitempty,oneof=NGN GHS ZAR KES USD" example:"NGN"`
+	CallbackURL string    `json:"callbackUrl" validate:"omitempty,url" example:"https://example.com/bookings/paid"`
+	CreatedAt   time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt   time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// RefundPayment struct
+//
+// The whole remaining amount is refunded when the amount is omitted.
+type RefundPayment struct {
+	Amount    int64     `json:"amount" validate:"omitempty,min=1" example:"500000"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
@@ -0,0 +1,266 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// FakePaystack is an http.Handler implementing the parts of the Paystack API used by the Paystack adapter,
+// so the payments can be made without a Paystack account, e.g. in tests and local development. It is served
+// at the root of a server, e.g. an httptest.Server, whose URL the Paystack adapter is configured with.
+//
+// The authorization URL of an initialized transaction is a page of the server: opening it completes the transaction,
+// as the customer paying it would.
+type FakePaystack struct {
+	secretKey string
+	router    *mux.Router
+
+	mu           sync.Mutex
+	transactions map[string]*fakeTransaction
+	accessCodes  map[string]string
+}
+
+type fakeTransaction struct {
+	paystackTransaction
+	refunded int64
+}
+
+// NewFakePaystack returns a FakePaystack accepting the given secret key, which signs its webhooks.
+func NewFakePaystack(secretKey string) *FakePaystack {
+	f := &FakePaystack{
+		secretKey:    secretKey,
+		transactions: map[string]*fakeTransaction{},
+		accessCodes:  map[string]string{},
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/transaction/initialize", f.authorized(f.initialize)).Methods(http.MethodPost)
+	router.HandleFunc("/transaction/verify/{reference}", f.authorized(f.verify)).Methods(http.MethodGet)
+	router.HandleFunc("/refund", f.authorized(f.refund)).Methods(http.MethodPost)
+	router.HandleFunc("/checkout/{accessCode}", f.checkout).Methods(http.MethodGet)
+	f.router = router
+	return f
+}
+
+// ServeFakePaystack serves a FakePaystack accepting a random secret key on a loopback port, for local development,
+// and returns the Paystack adapter using it, and the function shutting the server down.
+func ServeFakePaystack() (*Paystack, func(), error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, nil, err
+	}
+	secretKey := "sk_fake_" + hex.EncodeToString(b)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, err
+	}
+	server := &http.Server{Handler: NewFakePaystack(secretKey), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = server.Serve(l)
+	}()
+	p := NewPaystack(secretKey, WithBaseURL("http://"+l.Addr().String()))
+	return p, func() { _ = server.Close() }, nil
+}
+
+// ServeHTTP serves the Paystack API.
+func (f *FakePaystack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.router.ServeHTTP(w, r)
+}
+
+// Complete marks the pending transaction as successful.
+func (f *FakePaystack) Complete(reference string) error {
+	return f.settle(reference, StatusSuccess)
+}
+
+// Fail marks the pending transaction as failed.
+func (f *FakePaystack) Fail(reference string) error {
+	return f.settle(reference, StatusFailed)
+}
+
+// SendWebhook posts the event about the transaction to the URL, signed like Paystack signs it.
+func (f *FakePaystack) SendWebhook(ctx context.Context, url, event, reference string) error {
+	f.mu.Lock()
+	t, ok := f.transactions[reference]
+	var data paystackTransaction
+	if ok {
+		data = t.paystackTransaction
+	}
+	f.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("fake paystack: transaction %q not found", reference)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"event": event, "data": data})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(PaystackSignatureHeader, PaystackSignature(f.secretKey, body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("fake paystack: webhook answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (f *FakePaystack) settle(reference, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.transactions[reference]
+	if !ok {
+		return fmt.Errorf("fake paystack: transaction %q not found", reference)
+	}
+	if t.Status != StatusPending {
+		return fmt.Errorf("fake paystack: transaction %q is %s", reference, t.Status)
+	}
+	t.Status = status
+	if status == StatusSuccess {
+		t.PaidAt = time.Now().UTC()
+	}
+	return nil
+}
+
+// authorized rejects the requests which aren't authenticated with the secret key.
+func (f *FakePaystack) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+f.secretKey {
+			writeFake(w, http.StatusUnauthorized, "Invalid key", nil)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (f *FakePaystack) initialize(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reference string `json:"reference"`
+		Email     string `json:"email"`
+		Amount    int64  `json:"amount"`
+		Currency  string `json:"currency"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" || req.Amount <= 0 {
+		writeFake(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+	if req.Reference == "" {
+		req.Reference = randomCode()
+	}
+	if req.Currency == "" {
+		req.Currency = "NGN"
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.transactions[req.Reference]; ok {
+		writeFake(w, http.StatusBadRequest, "Duplicate Transaction Reference", nil)
+		return
+	}
+	accessCode := randomCode()
+	f.transactions[req.Reference] = &fakeTransaction{paystackTransaction: paystackTransaction{
+		Reference: req.Reference,
+		Status:    StatusPending,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+	}}
+	f.accessCodes[accessCode] = req.Reference
+	writeFake(w, http.StatusOK, "Authorization URL created", map[string]string{
+		"authorization_url": "http://" + r.Host + "/checkout/" + accessCode,
+		"access_code":       accessCode,
+		"reference":         req.Reference,
+	})
+}
+
+func (f *FakePaystack) verify(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.transactions[mux.Vars(r)["reference"]]
+	if !ok {
+		writeFake(w, http.StatusNotFound, "Transaction reference not found", nil)
+		return
+	}
+	writeFake(w, http.StatusOK, "Verification successful", t.paystackTransaction)
+}
+
+func (f *FakePaystack) refund(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Transaction string `json:"transaction"`
+		Amount      int64  `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFake(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.transactions[req.Transaction]
+	switch {
+	case !ok:
+		writeFake(w, http.StatusNotFound, "Transaction not found", nil)
+		return
+	case t.Status != StatusSuccess:
+		writeFake(w, http.StatusBadRequest, "Transaction has not been paid", nil)
+		return
+	}
+	amount := req.Amount
+	if amount == 0 {
+		amount = t.Amount - t.refunded
+	}
+	if amount <= 0 || t.refunded+amount > t.Amount {
+		writeFake(w, http.StatusBadRequest, "Refund amount cannot be greater than the transaction amount", nil)
+		return
+	}
+	t.refunded += amount
+	writeFake(w, http.StatusOK, "Refund has been queued for processing", map[string]interface{}{
+		"amount": amount,
+		"status": "processed",
+	})
+}
+
+func (f *FakePaystack) checkout(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	reference, ok := f.accessCodes[mux.Vars(r)["accessCode"]]
+	f.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if err := f.Complete(reference); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	fmt.Fprintf(w, "Transaction %s paid.\n", reference)
+}
+
+func writeFake(w http.ResponseWriter, status int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  status < 300,
+		"message": message,
+		"data":    data,
+	})
+}
+
+func randomCode() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
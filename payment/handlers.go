@@ -0,0 +1,238 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+func GetBookingPayments(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v, ok := mux.Vars(r)["id"]
+		if !ok {
+			panic(ErrBadRouting)
+		}
+		id, err := uuid.Parse(v)
+		if err != nil {
+			writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: []Payment{}})
+			return
+		}
+
+		qp := crud.PaginationQueryParams(r)
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /bookings/%s/payments - PaginationQueryParams: %+v - Took: %v\n", id, qp, time.Since(begin))
+		}(time.Now())
+		data, metadata, err := s.GetBookingPayments(r.Context(), id, qp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data, Metadata: metadata})
+	}
+}
+
+func GetSinglePayment(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reference := paymentReference(r)
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /payments/%s - Took: %v\n", reference, time.Since(begin))
+		}(time.Now())
+		data, err := s.GetSinglePayment(r.Context(), reference)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func CreateSinglePayment(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pqp, err := paystackQueryParams(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		var dto CreatePayment
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+		// Set default timestamps
+		now := time.Now().UTC()
+		dto.CreatedAt = now
+		dto.UpdatedAt = now
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /payments - PaystackQueryParams: %+v - CreatePaymentDTO: %+v - Took: %v\n", pqp, dto, time.Since(begin))
+		}(time.Now())
+		data, err := s.CreateSinglePayment(r.Context(), pqp, dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusCreated, Message: "success", Data: data})
+	}
+}
+
+func VerifySinglePayment(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reference := paymentReference(r)
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /payments/%s/verify - Took: %v\n", reference, time.Since(begin))
+		}(time.Now())
+		data, err := s.VerifySinglePayment(r.Context(), reference)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func RefundSinglePayment(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reference := paymentReference(r)
+		var dto RefundPayment
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil && err != io.EOF {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+		dto.UpdatedAt = time.Now().UTC()
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /payments/%s/refund - RefundPaymentDTO: %+v - Took: %v\n", reference, dto, time.Since(begin))
+		}(time.Now())
+		data, err := s.RefundSinglePayment(r.Context(), reference, dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+// HandleWebhook returns the handler of the provider's webhooks. It answers with a 5xx when the event couldn't be
+// processed, so the provider retries it.
+func HandleWebhook(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		event, test, err := s.ParseWebhook(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /payments/webhook - Event: %s - Reference: %s - Took: %v\n", event.Event, event.Transaction.Reference, time.Since(begin))
+		}(time.Now())
+		if err := s.HandleWebhook(r.Context(), event, test); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success"})
+	}
+}
+
+// paymentReference reads the `reference` path variable.
+func paymentReference(r *http.Request) string {
+	reference, ok := mux.Vars(r)["reference"]
+	if !ok {
+		panic(ErrBadRouting)
+	}
+	return reference
+}
+
+// paystackQueryParams reads the isTest query param, false when missing.
+func paystackQueryParams(r *http.Request) (params.PaystackQueryParams, error) {
+	var pqp params.PaystackQueryParams
+	if v := r.URL.Query().Get("isTest"); v != "" {
+		isTest, err := strconv.ParseBool(v)
+		if err != nil {
+			return pqp, &queryParamError{fmt.Sprintf("invalid isTest query param %q: must be a boolean", v)}
+		}
+		pqp.IsTest = isTest
+	}
+	return pqp, nil
+}
+
+// queryParamError is returned for a query param which can't be parsed.
+type queryParamError struct {
+	message string
+}
+
+func (e *queryParamError) Error() string {
+	return e.message
+}
+
+// writeError maps the error to its HTTP status code and writes it as the response.
+func writeError(w http.ResponseWriter, err error) {
+	var qpErr *queryParamError
+	var pErr *ProviderError
+	var status int
+	message := err.Error()
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrInvalidSignature):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrNotPayable), errors.Is(err, ErrNothingDue), errors.Is(err, ErrNotRefundable), errors.Is(err, ErrAmountMismatch):
+		status = http.StatusConflict
+	case errors.Is(err, ErrUnknownBooking), errors.Is(err, ErrAmountNotDue), errors.Is(err, ErrTestModeUnavailable):
+		status = http.StatusUnprocessableEntity
+	case validation.IsValidationError(err), errors.As(err, &qpErr):
+		status = http.StatusUnprocessableEntity
+	case errors.As(err, &pErr):
+		log.Printf("Payment provider error: %+v\n", err)
+		status = http.StatusBadGateway
+		message = "payment provider error: " + pErr.Message
+	default:
+		log.Printf("Unable to handle payment request: %+v\n", err)
+		status = http.StatusInternalServerError
+		message = "internal error"
+	}
+	writeResponse(w, Response{Status: status, Message: message})
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	// set the content type to application/json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
+
+	// encode the response struct as JSON and write it to the response writer
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		// handle the error
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,205 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// PaystackBaseURL is the base URL of the Paystack API.
+	PaystackBaseURL = "https://api.paystack.co"
+	// PaystackSignatureHeader is the header carrying the HMAC-SHA512 signature of the Paystack webhooks.
+	PaystackSignatureHeader = "X-Paystack-Signature"
+	// maxWebhookSize is the maximum size of a webhook body.
+	maxWebhookSize = 1 << 20
+)
+
+// PaystackOption configures the Paystack adapter.
+type PaystackOption func(*Paystack)
+
+// WithBaseURL sets the base URL of the API, e.g. the URL of a FakePaystack.
+func WithBaseURL(baseURL string) PaystackOption {
+	return func(p *Paystack) {
+		p.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client the API is called with.
+func WithHTTPClient(client *http.Client) PaystackOption {
+	return func(p *Paystack) {
+		p.client = client
+	}
+}
+
+// Paystack is the Provider calling the Paystack API, or any API shaped like it.
+type Paystack struct {
+	secretKey string
+	baseURL   string
+	client    *http.Client
+}
+
+// NewPaystack returns the Paystack Provider authenticating with the secret key,
+// a test key (sk_test_...) making test transactions.
+func NewPaystack(secretKey string, opts ...PaystackOption) *Paystack {
+	p := &Paystack{
+		secretKey: secretKey,
+		baseURL:   PaystackBaseURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// paystackResponse is the envelope of the Paystack API responses.
+type paystackResponse struct {
+	Status  bool            `json:"status"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// paystackTransaction is a transaction, as returned by the API and sent in the webhooks.
+type paystackTransaction struct {
+	Reference            string    `json:"reference"`
+	TransactionReference string    `json:"transaction_reference"`
+	Status               string    `json:"status"`
+	Amount               int64     `json:"amount"`
+	Currency             string    `json:"currency"`
+	PaidAt               time.Time `json:"paid_at"`
+}
+
+func (t paystackTransaction) transaction() Transaction {
+	reference := t.Reference
+	if reference == "" {
+		reference = t.TransactionReference
+	}
+	return Transaction{Reference: reference, Status: t.Status, Amount: t.Amount, Currency: t.Currency, PaidAt: t.PaidAt}
+}
+
+func (p *Paystack) Initialize(ctx context.Context, req InitializeRequest) (Initialization, error) {
+	body := map[string]interface{}{
+		"reference": req.Reference,
+		"email":     req.Email,
+		"amount":    req.Amount,
+		"currency":  req.Currency,
+		"metadata":  req.Metadata,
+	}
+	if req.CallbackURL != "" {
+		body["callback_url"] = req.CallbackURL
+	}
+	var data struct {
+		AuthorizationURL string `json:"authorization_url"`
+		AccessCode       string `json:"access_code"`
+		Reference        string `json:"reference"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/transaction/initialize", body, &data); err != nil {
+		return Initialization{}, err
+	}
+	return Initialization{AuthorizationURL: data.AuthorizationURL, AccessCode: data.AccessCode, Reference: data.Reference}, nil
+}
+
+func (p *Paystack) Verify(ctx context.Context, reference string) (Transaction, error) {
+	var data paystackTransaction
+	if err := p.do(ctx, http.MethodGet, "/transaction/verify/"+url.PathEscape(reference), nil, &data); err != nil {
+		return Transaction{}, err
+	}
+	return data.transaction(), nil
+}
+
+func (p *Paystack) Refund(ctx context.Context, reference string, amount int64) (Refund, error) {
+	body := map[string]interface{}{"transaction": reference}
+	if amount > 0 {
+		body["amount"] = amount
+	}
+	var data struct {
+		Amount int64  `json:"amount"`
+		Status string `json:"status"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/refund", body, &data); err != nil {
+		return Refund{}, err
+	}
+	return Refund{Reference: reference, Amount: data.Amount, Status: data.Status}, nil
+}
+
+// ParseWebhook verifies the X-Paystack-Signature header, the hex-encoded HMAC-SHA512 of the body keyed with the secret key.
+func (p *Paystack) ParseWebhook(r *http.Request) (WebhookEvent, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookSize))
+	if err != nil {
+		return WebhookEvent{}, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if !hmac.Equal([]byte(PaystackSignature(p.secretKey, body)), []byte(r.Header.Get(PaystackSignatureHeader))) {
+		return WebhookEvent{}, ErrInvalidSignature
+	}
+	var event struct {
+		Event string              `json:"event"`
+		Data  paystackTransaction `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return WebhookEvent{}, fmt.Errorf("invalid webhook body: %w", err)
+	}
+	return WebhookEvent{Event: event.Event, Transaction: event.Data.transaction()}, nil
+}
+
+// PaystackSignature returns the signature of a webhook body.
+func PaystackSignature(secretKey string, body []byte) string {
+	mac := hmac.New(sha512.New, []byte(secretKey))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// do calls the API, decoding the data of its response into out.
+func (p *Paystack) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope paystackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("paystack %s %s: unexpected response (status %d): %w", method, path, resp.StatusCode, err)
+	}
+	if resp.StatusCode >= 300 || !envelope.Status {
+		return &ProviderError{StatusCode: resp.StatusCode, Message: envelope.Message}
+	}
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// ProviderError is an error returned by the provider's API.
+type ProviderError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("payment provider error (status %d): %s", e.StatusCode, e.Message)
+}
@@ -0,0 +1,76 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// The statuses of the provider transactions, and of the payments.
+const (
+	StatusPending  = "pending"
+	StatusSuccess  = "success"
+	StatusFailed   = "failed"
+	StatusRefunded = "refunded"
+)
+
+// The webhook events handled.
+const (
+	EventChargeSuccess   = "charge.success"
+	EventRefundProcessed = "refund.processed"
+)
+
+// ErrInvalidSignature is returned for the webhooks whose signature doesn't match their body.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Provider is a payment provider, e.g. Paystack.
+type Provider interface {
+	// Initialize starts a transaction, returning the page the customer pays it on.
+	Initialize(ctx context.Context, req InitializeRequest) (Initialization, error)
+	// Verify returns the current state of a transaction.
+	Verify(ctx context.Context, reference string) (Transaction, error)
+	// Refund refunds the amount of a successful transaction, all of it when the amount is 0.
+	Refund(ctx context.Context, reference string, amount int64) (Refund, error)
+	// ParseWebhook verifies the signature of a webhook request and parses its event.
+	ParseWebhook(r *http.Request) (WebhookEvent, error)
+}
+
+// InitializeRequest is a transaction to start, the amount being in the currency's minor unit (e.g. kobo).
+type InitializeRequest struct {
+	Reference   string
+	Email       string
+	Amount      int64
+	Currency    string
+	CallbackURL string
+	Metadata    map[string]string
+}
+
+// Initialization is a started transaction.
+type Initialization struct {
+	AuthorizationURL string
+	AccessCode       string
+	Reference        string
+}
+
+// Transaction is the state of a transaction at the provider.
+type Transaction struct {
+	Reference string
+	Status    string
+	Amount    int64
+	Currency  string
+	PaidAt    time.Time
+}
+
+// Refund is a refund of a transaction.
+type Refund struct {
+	Reference string
+	Amount    int64
+	Status    string
+}
+
+// WebhookEvent is an event sent by the provider, about one of the transactions.
+type WebhookEvent struct {
+	Event       string
+	Transaction Transaction
+}
@@ -0,0 +1,118 @@
+package payment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/sort"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+// ErrNotFound is returned when no payment has the given reference.
+var ErrNotFound = errors.New("payment not found")
+
+// Repository is responsible for managing the persistence layer of the payments. (e.g. database operations)
+type Repository interface {
+	GetBookingPayments(ctx context.Context, bookingID uuid.UUID, qp params.PaginationQueryParams) ([]Payment, *pagination.PaginationData, error)
+	GetSinglePayment(ctx context.Context, reference string) (Payment, error)
+	CreateSinglePayment(ctx context.Context, payment Payment) (Payment, error)
+	// UpdateSinglePayment applies the update to the payment in a transaction, retried on conflicts.
+	// The payment is only written when the update reports it changed it.
+	UpdateSinglePayment(ctx context.Context, reference string, update func(*Payment) (bool, error)) (Payment, error)
+}
+
+type paymentRepository struct {
+	db         *tigris.Database
+	collection *tigris.Collection[Payment]
+}
+
+// NewPaymentRepository returns a concrete implementation of the Repository interface.
+func NewPaymentRepository(db *tigris.Database) Repository {
+	return &paymentRepository{db: db, collection: tigris.GetCollection[Payment](db)}
+}
+
+func (r paymentRepository) GetBookingPayments(ctx context.Context, bookingID uuid.UUID, qp params.PaginationQueryParams) ([]Payment, *pagination.PaginationData, error) {
+	var payments []Payment = []Payment{}
+	f := filter.Eq("bookingId", bookingID)
+
+	// Add initial pagination data
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	c, err := r.collection.Count(ctx, f)
+	if err != nil {
+		return payments, &m, err
+	}
+	if !qp.Paginate {
+		m.Page = 1
+		m.PerPage = c
+	}
+	options := tigris.ReadOptions{
+		Skip:  (m.Page - 1) * m.PerPage,
+		Limit: m.PerPage,
+		Sort:  sort.Ascending("createdAt"),
+	}
+	it, err := r.collection.ReadWithOptions(ctx, f, fields.All, &options)
+	if err != nil {
+		return payments, &m, err
+	}
+	defer it.Close()
+
+	var payment Payment
+	for it.Next(&payment) {
+		payments = append(payments, payment)
+	}
+
+	m = pagination.New(m.Page, m.PerPage, c)
+
+	return payments, &m, it.Err()
+}
+
+func (r paymentRepository) GetSinglePayment(ctx context.Context, reference string) (Payment, error) {
+	payment, err := r.collection.ReadOne(ctx, filter.Eq("reference", reference))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return Payment{}, ErrNotFound
+	}
+	if err != nil {
+		return Payment{}, err
+	}
+	return *payment, nil
+}
+
+func (r paymentRepository) CreateSinglePayment(ctx context.Context, payment Payment) (Payment, error) {
+	if _, err := r.collection.Insert(ctx, &payment); err != nil {
+		return Payment{}, err
+	}
+	return payment, nil
+}
+
+func (r paymentRepository) UpdateSinglePayment(ctx context.Context, reference string, update func(*Payment) (bool, error)) (Payment, error) {
+	var updated Payment
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		payment, err := r.GetSinglePayment(ctx, reference)
+		if err != nil {
+			return err
+		}
+		changed, err := update(&payment)
+		if err != nil {
+			return err
+		}
+		if changed {
+			if _, err := r.collection.InsertOrReplace(ctx, &payment); err != nil {
+				return err
+			}
+		}
+		updated = payment
+		return nil
+	}, tigris.TxOptions{AutoRetry: true})
+	if err != nil {
+		return Payment{}, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,407 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/booking"
+	"github.com/simply-alliv/tigris-go-explore/customer"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+var (
+	// ErrUnknownBooking is returned when a payment is made for a booking which doesn't exist.
+	ErrUnknownBooking = errors.New("payment booking not found")
+	// ErrNotPayable is returned when a payment is made for a booking which isn't waiting for one.
+	ErrNotPayable = errors.New("booking can't be paid in its state")
+	// ErrNothingDue is returned when a payment is made for a booking which is free.
+	ErrNothingDue = errors.New("booking has no amount due")
+	// ErrAmountNotDue is returned when a payment is made for another amount or currency than the booking's.
+	ErrAmountNotDue = errors.New("payment amount doesn't match the amount due by the booking")
+	// ErrNotRefundable is returned when refunding a payment which wasn't successful, or more than its remaining amount.
+	ErrNotRefundable = errors.New("payment can't be refunded")
+	// ErrAmountMismatch is returned when the provider's transaction isn't for the amount of the payment.
+	ErrAmountMismatch = errors.New("transaction amount doesn't match the payment")
+	// ErrTestModeUnavailable is returned for the test payments when no test provider is configured.
+	ErrTestModeUnavailable = errors.New("test payments aren't configured")
+)
+
+// DefaultCurrency is the currency of the bookings made before they had one.
+const DefaultCurrency = "NGN"
+
+// BookingPayer reads the bookings the payments are made for, and marks them as paid.
+type BookingPayer interface {
+	GetSingleBooking(ctx context.Context, id uuid.UUID) (booking.Booking, error)
+	TransitionSingleBooking(ctx context.Context, id uuid.UUID, action string, dto booking.TransitionBooking) (booking.Booking, error)
+}
+
+// CustomerReader reads the customers the payments are made by.
+type CustomerReader interface {
+	GetSingleCustomer(ctx context.Context, id uuid.UUID) (customer.Customer, error)
+}
+
+// Option configures the Service.
+type Option func(*Service)
+
+// WithTestProvider sets the provider of the test payments, e.g. Paystack with a test secret key.
+func WithTestProvider(p Provider) Option {
+	return func(s *Service) {
+		s.test = p
+	}
+}
+
+type Service struct {
+	r         Repository
+	live      Provider
+	test      Provider
+	bookings  BookingPayer
+	customers CustomerReader
+}
+
+// NewPaymentService returns a service
+func NewPaymentService(r Repository, live Provider, bookings BookingPayer, customers CustomerReader, opts ...Option) *Service {
+	s := &Service{r: r, live: live, bookings: bookings, customers: customers}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetBookingPayments godoc
+// @Summary Get the payments of a booking
+// @Description Get the payments made for a booking, oldest first
+// @Security Bearer
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the booking resource"
+// @Success 200 {object} JSONResultSuccess{data=[]Payment} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /bookings/{id}/payments [get]
+func (s *Service) GetBookingPayments(ctx context.Context, bookingID uuid.UUID, qp params.PaginationQueryParams) ([]Payment, *pagination.PaginationData, error) {
+	return s.r.GetBookingPayments(ctx, bookingID, qp)
+}
+
+// GetSinglePayment godoc
+// @Summary Get single payment resource
+// @Description Get a single payment resource
+// @Security Bearer
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param reference path string true "Reference of the payment resource"
+// @Success 200 {object} JSONResultSuccess{data=Payment} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /payments/{reference} [get]
+func (s *Service) GetSinglePayment(ctx context.Context, reference string) (Payment, error) {
+	return s.r.GetSinglePayment(ctx, reference)
+}
+
+// CreateSinglePayment godoc
+// @Summary Create single payment resource
+// @Description Initialize a payment of the amount due by an approved booking, the customer paying it at the returned authorization URL. A given amount or currency must be the booking's
+// @Security Bearer
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param isTest query bool false "Whether to make a test payment"
+// @Param body body CreatePayment true "JSON body to create a payment resource"
+// @Success 201 {object} JSONResultSuccess{data=Payment} "Created"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Failure 502 {object} JSONResultFailure "Error: Bad Gateway"
+// @Router /payments [post]
+func (s *Service) CreateSinglePayment(ctx context.Context, pqp params.PaystackQueryParams, dto CreatePayment) (Payment, error) {
+	provider, err := s.provider(pqp.IsTest)
+	if err != nil {
+		return Payment{}, err
+	}
+	b, err := s.bookings.GetSingleBooking(ctx, dto.BookingID)
+	if errors.Is(err, booking.ErrNotFound) {
+		return Payment{}, ErrUnknownBooking
+	}
+	if err != nil {
+		return Payment{}, err
+	}
+	if b.State != booking.StateApproved || b.Paid {
+		return Payment{}, ErrNotPayable
+	}
+	if b.Amount <= 0 {
+		return Payment{}, ErrNothingDue
+	}
+	currency := b.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if (dto.Amount != 0 && dto.Amount != b.Amount) || (dto.Currency != "" && dto.Currency != currency) {
+		return Payment{}, ErrAmountNotDue
+	}
+	c, err := s.customers.GetSingleCustomer(ctx, b.CustomerID)
+	if err != nil {
+		return Payment{}, fmt.Errorf("unable to read the booking customer: %w", err)
+	}
+
+	payment := Payment{
+		Reference: uuid.NewString(),
+		BookingID: b.ID,
+		Email:     c.Email,
+		Amount:    b.Amount,
+		Currency:  currency,
+		Status:    StatusPending,
+		Test:      pqp.IsTest,
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}
+	init, err := provider.Initialize(ctx, InitializeRequest{
+		Reference:   payment.Reference,
+		Email:       payment.Email,
+		Amount:      payment.Amount,
+		Currency:    payment.Currency,
+		CallbackURL: dto.CallbackURL,
+		Metadata:    map[string]string{"bookingId": b.ID.String()},
+	})
+	if err != nil {
+		return Payment{}, err
+	}
+	payment.AuthorizationURL = init.AuthorizationURL
+	return s.r.CreateSinglePayment(ctx, payment)
+}
+
+// VerifySinglePayment godoc
+// @Summary Verify single payment resource
+// @Description Update a payment from the state of its transaction at the provider, marking its booking as paid once it is successful. Verifying a payment again is a no-op
+// @Security Bearer
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param reference path string true "Reference of the payment resource"
+// @Success 200 {object} JSONResultSuccess{data=Payment} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Failure 502 {object} JSONResultFailure "Error: Bad Gateway"
+// @Router /payments/{reference}/verify [post]
+func (s *Service) VerifySinglePayment(ctx context.Context, reference string) (Payment, error) {
+	payment, err := s.r.GetSinglePayment(ctx, reference)
+	if err != nil {
+		return Payment{}, err
+	}
+	provider, err := s.provider(payment.Test)
+	if err != nil {
+		return Payment{}, err
+	}
+	t, err := provider.Verify(ctx, reference)
+	if err != nil {
+		return Payment{}, err
+	}
+	return s.settle(ctx, t)
+}
+
+// RefundSinglePayment godoc
+// @Summary Refund single payment resource
+// @Description Refund a successful payment, in full or in part. The amount is reserved before the provider is called, so concurrent refunds can't exceed the payment. The booking isn't changed, cancel it separately if needed. Send an Idempotency-Key to retry a refund safely
+// @Security Bearer
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param reference path string true "Reference of the payment resource"
+// @Param body body RefundPayment false "JSON body to refund a payment resource"
+// @Success 200 {object} JSONResultSuccess{data=Payment} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 409 {object} JSONResultFailure "Error: Conflict"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Failure 502 {object} JSONResultFailure "Error: Bad Gateway"
+// @Router /payments/{reference}/refund [post]
+func (s *Service) RefundSinglePayment(ctx context.Context, reference string, dto RefundPayment) (Payment, error) {
+	payment, err := s.r.GetSinglePayment(ctx, reference)
+	if err != nil {
+		return Payment{}, err
+	}
+	provider, err := s.provider(payment.Test)
+	if err != nil {
+		return Payment{}, err
+	}
+
+	// Reserve the amount before calling the provider, in a transaction, so concurrent refunds can't both take it
+	var amount int64
+	_, err = s.r.UpdateSinglePayment(ctx, reference, func(p *Payment) (bool, error) {
+		remaining := p.Amount - p.RefundedAmount - p.PendingRefundAmount
+		amount = dto.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if p.Status != StatusSuccess || amount <= 0 || amount > remaining {
+			return false, ErrNotRefundable
+		}
+		p.PendingRefundAmount += amount
+		p.UpdatedAt = dto.UpdatedAt
+		return true, nil
+	})
+	if err != nil {
+		return Payment{}, err
+	}
+
+	refund, err := provider.Refund(ctx, reference, amount)
+	var pErr *ProviderError
+	if errors.As(err, &pErr) && pErr.StatusCode < 500 {
+		// The provider refused the refund, the amount can be refunded again
+		if _, rErr := s.release(ctx, reference, amount); rErr != nil {
+			log.Printf("Unable to release the refund of %d of payment %s: %+v\n", amount, reference, rErr)
+		}
+		return Payment{}, err
+	}
+	if err != nil {
+		// The refund may have been made, so the amount stays reserved until the payment is looked into
+		log.Printf("Unable to confirm the refund of %d of payment %s, it stays reserved: %+v\n", amount, reference, err)
+		return Payment{}, err
+	}
+	if refund.Amount != 0 && refund.Amount != amount {
+		log.Printf("Refund of payment %s is of %d instead of %d\n", reference, refund.Amount, amount)
+	}
+	return s.r.UpdateSinglePayment(ctx, reference, func(p *Payment) (bool, error) {
+		p.PendingRefundAmount -= amount
+		p.RefundedAmount += amount
+		if p.RefundedAmount >= p.Amount {
+			p.Status = StatusRefunded
+		}
+		p.UpdatedAt = time.Now().UTC()
+		return true, nil
+	})
+}
+
+// release gives back the amount reserved by a refund which wasn't made.
+func (s *Service) release(ctx context.Context, reference string, amount int64) (Payment, error) {
+	return s.r.UpdateSinglePayment(ctx, reference, func(p *Payment) (bool, error) {
+		p.PendingRefundAmount -= amount
+		p.UpdatedAt = time.Now().UTC()
+		return true, nil
+	})
+}
+
+// ParseWebhook verifies the signature of a provider's webhook, and returns its event and whether it is about a test
+// payment, the live and test providers signing them with different keys.
+func (s *Service) ParseWebhook(r *http.Request) (WebhookEvent, bool, error) {
+	event, err := s.live.ParseWebhook(r)
+	if errors.Is(err, ErrInvalidSignature) && s.test != nil {
+		event, err = s.test.ParseWebhook(r)
+		return event, true, err
+	}
+	return event, false, err
+}
+
+// HandleWebhook godoc
+// @Summary Handle a payment provider webhook
+// @Description Update a payment from a charge.success event of the provider, signed with its X-Paystack-Signature header, marking its booking as paid. The events are processed idempotently, so redeliveries are no-ops
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Success 200 {object} JSONResultSuccess{} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /payments/webhook [post]
+func (s *Service) HandleWebhook(ctx context.Context, event WebhookEvent, test bool) error {
+	if event.Event != EventChargeSuccess {
+		return nil
+	}
+	payment, err := s.r.GetSinglePayment(ctx, event.Transaction.Reference)
+	if errors.Is(err, ErrNotFound) {
+		// Not one of the payments, e.g. made from the provider's dashboard.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if payment.Test != test {
+		return ErrInvalidSignature
+	}
+	_, err = s.settle(ctx, event.Transaction)
+	if errors.Is(err, ErrAmountMismatch) || errors.Is(err, ErrNotPayable) {
+		// Retrying the webhook wouldn't help, the payment needs looking into.
+		log.Printf("Unable to settle payment %s: %+v\n", payment.Reference, err)
+		return nil
+	}
+	return err
+}
+
+// settle updates the payment from its transaction at the provider, then marks its booking as paid once it is
+// successful. Only the pending payments are updated, so settling a payment again only retries marking its booking.
+func (s *Service) settle(ctx context.Context, t Transaction) (Payment, error) {
+	payment, err := s.r.UpdateSinglePayment(ctx, t.Reference, func(p *Payment) (bool, error) {
+		if p.Status != StatusPending {
+			return false, nil
+		}
+		switch t.Status {
+		case StatusSuccess:
+			if t.Amount != p.Amount || (t.Currency != "" && t.Currency != p.Currency) {
+				p.Status = StatusFailed
+				break
+			}
+			p.Status = StatusSuccess
+			p.PaidAt = t.PaidAt
+			if p.PaidAt.IsZero() {
+				p.PaidAt = time.Now().UTC()
+			}
+		case StatusFailed, "reversed":
+			p.Status = StatusFailed
+		default:
+			// e.g. abandoned or ongoing, the customer can still pay it.
+			return false, nil
+		}
+		p.UpdatedAt = time.Now().UTC()
+		return true, nil
+	})
+	if err != nil {
+		return Payment{}, err
+	}
+	if t.Status == StatusSuccess && payment.Status == StatusFailed {
+		return payment, ErrAmountMismatch
+	}
+	if payment.Status == StatusSuccess {
+		return payment, s.markPaid(ctx, payment)
+	}
+	return payment, nil
+}
+
+// markPaid applies the pay action to the payment's booking, a booking which is already paid being left as is.
+func (s *Service) markPaid(ctx context.Context, payment Payment) error {
+	_, err := s.bookings.TransitionSingleBooking(ctx, payment.BookingID, booking.ActionPay, booking.TransitionBooking{
+		Action:    booking.ActionPay,
+		Reason:    "payment " + payment.Reference,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if !errors.Is(err, booking.ErrInvalidTransition) {
+		return err
+	}
+	b, err := s.bookings.GetSingleBooking(ctx, payment.BookingID)
+	if err != nil {
+		return err
+	}
+	if b.Paid {
+		return nil
+	}
+	return ErrNotPayable
+}
+
+func (s *Service) provider(test bool) (Provider, error) {
+	if !test {
+		return s.live, nil
+	}
+	if s.test == nil {
+		return nil, ErrTestModeUnavailable
+	}
+	return s.test, nil
+}
@@ -0,0 +1,362 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/booking"
+	"github.com/simply-alliv/tigris-go-explore/customer"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+const testSecretKey = "sk_test_secret"
+
+// memoryRepository is a Repository keeping the payments in memory, implementing the methods the service calls.
+type memoryRepository struct {
+	Repository
+	mu       sync.Mutex
+	payments map[string]Payment
+}
+
+func (r *memoryRepository) GetSinglePayment(ctx context.Context, reference string) (Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.payments[reference]
+	if !ok {
+		return Payment{}, ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *memoryRepository) CreateSinglePayment(ctx context.Context, payment Payment) (Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payments[payment.Reference] = payment
+	return payment, nil
+}
+
+func (r *memoryRepository) UpdateSinglePayment(ctx context.Context, reference string, update func(*Payment) (bool, error)) (Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.payments[reference]
+	if !ok {
+		return Payment{}, ErrNotFound
+	}
+	changed, err := update(&p)
+	if err != nil {
+		return Payment{}, err
+	}
+	if changed {
+		r.payments[reference] = p
+	}
+	return p, nil
+}
+
+// bookings is a BookingPayer and CustomerReader keeping an approved booking of 5000 NGN and its customer in memory.
+type bookings struct {
+	mu          sync.Mutex
+	booking     booking.Booking
+	customer    customer.Customer
+	transitions int
+}
+
+func newBookings() *bookings {
+	c := customer.Customer{ID: uuid.New(), Email: "jane.doe@example.com"}
+	return &bookings{
+		booking: booking.Booking{
+			ID:         uuid.New(),
+			CustomerID: c.ID,
+			State:      booking.StateApproved,
+			Approved:   true,
+			Amount:     500000,
+			Currency:   "NGN",
+		},
+		customer: c,
+	}
+}
+
+func (b *bookings) GetSingleBooking(ctx context.Context, id uuid.UUID) (booking.Booking, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if id != b.booking.ID {
+		return booking.Booking{}, booking.ErrNotFound
+	}
+	return b.booking, nil
+}
+
+func (b *bookings) TransitionSingleBooking(ctx context.Context, id uuid.UUID, action string, dto booking.TransitionBooking) (booking.Booking, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if id != b.booking.ID {
+		return booking.Booking{}, booking.ErrNotFound
+	}
+	if err := b.booking.Apply(action, dto); err != nil {
+		return booking.Booking{}, err
+	}
+	b.transitions++
+	return b.booking, nil
+}
+
+func (b *bookings) GetSingleCustomer(ctx context.Context, id uuid.UUID) (customer.Customer, error) {
+	if id != b.customer.ID {
+		return customer.Customer{}, customer.ErrNotFound
+	}
+	return b.customer, nil
+}
+
+// newService returns a payment service of the bookings, using Paystack against a FakePaystack.
+func newService(t *testing.T, b *bookings) (*Service, *FakePaystack) {
+	t.Helper()
+	fake := NewFakePaystack(testSecretKey)
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	provider := NewPaystack(testSecretKey, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+	return NewPaymentService(&memoryRepository{payments: map[string]Payment{}}, provider, b, b), fake
+}
+
+// pay initializes a payment of the booking and completes it at the provider.
+func pay(t *testing.T, s *Service, fake *FakePaystack, bookingID uuid.UUID) Payment {
+	t.Helper()
+	p, err := s.CreateSinglePayment(context.Background(), params.PaystackQueryParams{}, CreatePayment{BookingID: bookingID})
+	if err != nil {
+		t.Fatalf("CreateSinglePayment() = %v", err)
+	}
+	if err := fake.Complete(p.Reference); err != nil {
+		t.Fatalf("Complete() = %v", err)
+	}
+	return p
+}
+
+func TestCreateSinglePayment(t *testing.T) {
+	tests := []struct {
+		name    string
+		booking func(*booking.Booking)
+		dto     CreatePayment
+		wantErr error
+	}{
+		{name: "amount due"},
+		{name: "given amount due", dto: CreatePayment{Amount: 500000, Currency: "NGN"}},
+		{name: "other amount", dto: CreatePayment{Amount: 100}, wantErr: ErrAmountNotDue},
+		{name: "other currency", dto: CreatePayment{Currency: "USD"}, wantErr: ErrAmountNotDue},
+		{name: "free booking", booking: func(b *booking.Booking) { b.Amount = 0 }, wantErr: ErrNothingDue},
+		{name: "not approved", booking: func(b *booking.Booking) { b.State = booking.StateCreated }, wantErr: ErrNotPayable},
+		{name: "paid", booking: func(b *booking.Booking) { b.Paid = true }, wantErr: ErrNotPayable},
+		{name: "unknown booking", dto: CreatePayment{BookingID: uuid.New()}, wantErr: ErrUnknownBooking},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBookings()
+			s, _ := newService(t, b)
+			if tt.booking != nil {
+				tt.booking(&b.booking)
+			}
+			if tt.dto.BookingID == uuid.Nil {
+				tt.dto.BookingID = b.booking.ID
+			}
+
+			p, err := s.CreateSinglePayment(context.Background(), params.PaystackQueryParams{}, tt.dto)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreateSinglePayment() = %v, want %v", err, tt.wantErr)
+				}
+				if n := len(s.r.(*memoryRepository).payments); n != 0 {
+					t.Errorf("%d payments created, want none", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateSinglePayment() = %v", err)
+			}
+			if p.Amount != 500000 || p.Currency != "NGN" || p.Status != StatusPending || p.Email != "jane.doe@example.com" {
+				t.Errorf("payment = %+v, want a pending payment of the amount due", p)
+			}
+			if p.AuthorizationURL == "" {
+				t.Error("payment has no authorization URL")
+			}
+		})
+	}
+}
+
+func TestVerifySinglePayment(t *testing.T) {
+	b := newBookings()
+	s, _ := newService(t, b)
+	p, err := s.CreateSinglePayment(context.Background(), params.PaystackQueryParams{}, CreatePayment{BookingID: b.booking.ID})
+	if err != nil {
+		t.Fatalf("CreateSinglePayment() = %v", err)
+	}
+
+	// Still pending until the customer pays at the authorization URL
+	p, err = s.VerifySinglePayment(context.Background(), p.Reference)
+	if err != nil || p.Status != StatusPending {
+		t.Fatalf("VerifySinglePayment() = %s, %v, want pending", p.Status, err)
+	}
+	resp, err := http.Get(p.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("GET authorization URL: %v", err)
+	}
+	resp.Body.Close()
+
+	for i := 0; i < 2; i++ {
+		p, err = s.VerifySinglePayment(context.Background(), p.Reference)
+		if err != nil || p.Status != StatusSuccess || p.PaidAt.IsZero() {
+			t.Fatalf("VerifySinglePayment() = %+v, %v, want success", p, err)
+		}
+	}
+	if !b.booking.Paid || b.transitions != 1 {
+		t.Errorf("booking paid = %v after %d transitions, want paid once", b.booking.Paid, b.transitions)
+	}
+}
+
+func TestHandleWebhook(t *testing.T) {
+	b := newBookings()
+	s, fake := newService(t, b)
+	p := pay(t, s, fake, b.booking.ID)
+	srv := httptest.NewServer(HandleWebhook(s))
+	defer srv.Close()
+
+	// Paystack redelivers the webhooks it isn't sure were received
+	for i := 0; i < 2; i++ {
+		if err := fake.SendWebhook(context.Background(), srv.URL, EventChargeSuccess, p.Reference); err != nil {
+			t.Fatalf("SendWebhook() = %v", err)
+		}
+	}
+
+	p, _ = s.GetSinglePayment(context.Background(), p.Reference)
+	if p.Status != StatusSuccess {
+		t.Errorf("payment status = %s, want success", p.Status)
+	}
+	if !b.booking.Paid || b.transitions != 1 {
+		t.Errorf("booking paid = %v after %d transitions, want paid once", b.booking.Paid, b.transitions)
+	}
+}
+
+func TestHandleWebhookRejectsInvalidSignatures(t *testing.T) {
+	b := newBookings()
+	s, fake := newService(t, b)
+	p := pay(t, s, fake, b.booking.ID)
+	srv := httptest.NewServer(HandleWebhook(s))
+	defer srv.Close()
+
+	body := []byte(`{"event":"charge.success","data":{"reference":"` + p.Reference + `","status":"success","amount":500000,"currency":"NGN"}}`)
+	for name, signature := range map[string]string{
+		"missing":    "",
+		"other key":  PaystackSignature("sk_test_other", body),
+		"other body": PaystackSignature(testSecretKey, []byte(`{}`)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, srv.URL, bytes.NewReader(body))
+			req.Header.Set(PaystackSignatureHeader, signature)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("POST webhook: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", resp.StatusCode)
+			}
+		})
+	}
+
+	p, _ = s.GetSinglePayment(context.Background(), p.Reference)
+	if p.Status != StatusPending || b.booking.Paid {
+		t.Errorf("payment status = %s, booking paid = %v, want both untouched", p.Status, b.booking.Paid)
+	}
+}
+
+func TestRefundSinglePayment(t *testing.T) {
+	b := newBookings()
+	s, fake := newService(t, b)
+	p := pay(t, s, fake, b.booking.ID)
+	if _, err := s.VerifySinglePayment(context.Background(), p.Reference); err != nil {
+		t.Fatalf("VerifySinglePayment() = %v", err)
+	}
+
+	p, err := s.RefundSinglePayment(context.Background(), p.Reference, RefundPayment{Amount: 200000})
+	if err != nil || p.RefundedAmount != 200000 || p.Status != StatusSuccess {
+		t.Fatalf("partial RefundSinglePayment() = %+v, %v, want 200000 refunded", p, err)
+	}
+	if _, err := s.RefundSinglePayment(context.Background(), p.Reference, RefundPayment{Amount: 400000}); !errors.Is(err, ErrNotRefundable) {
+		t.Fatalf("RefundSinglePayment() of more than the remaining amount = %v, want ErrNotRefundable", err)
+	}
+
+	// The remaining amount is refunded by default
+	p, err = s.RefundSinglePayment(context.Background(), p.Reference, RefundPayment{})
+	if err != nil || p.RefundedAmount != 500000 || p.PendingRefundAmount != 0 || p.Status != StatusRefunded {
+		t.Fatalf("RefundSinglePayment() = %+v, %v, want fully refunded", p, err)
+	}
+	if _, err := s.RefundSinglePayment(context.Background(), p.Reference, RefundPayment{}); !errors.Is(err, ErrNotRefundable) {
+		t.Errorf("RefundSinglePayment() of a refunded payment = %v, want ErrNotRefundable", err)
+	}
+}
+
+func TestRefundSinglePaymentConcurrently(t *testing.T) {
+	b := newBookings()
+	s, fake := newService(t, b)
+	p := pay(t, s, fake, b.booking.ID)
+	if _, err := s.VerifySinglePayment(context.Background(), p.Reference); err != nil {
+		t.Fatalf("VerifySinglePayment() = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	refunded := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.RefundSinglePayment(context.Background(), p.Reference, RefundPayment{Amount: 300000})
+			if err == nil {
+				mu.Lock()
+				refunded++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrNotRefundable) {
+				t.Errorf("RefundSinglePayment() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refunded != 1 {
+		t.Errorf("%d refunds made, want 1", refunded)
+	}
+	p, _ = s.GetSinglePayment(context.Background(), p.Reference)
+	if p.RefundedAmount != 300000 || p.PendingRefundAmount != 0 {
+		t.Errorf("payment refunded %d with %d pending, want 300000 and none pending", p.RefundedAmount, p.PendingRefundAmount)
+	}
+}
+
+// refusingProvider refuses every refund, as the provider does e.g. for a disputed transaction.
+type refusingProvider struct {
+	Provider
+}
+
+func (refusingProvider) Refund(ctx context.Context, reference string, amount int64) (Refund, error) {
+	return Refund{}, &ProviderError{StatusCode: http.StatusBadRequest, Message: "Transaction is disputed"}
+}
+
+func TestRefundSinglePaymentReleasesRefusedRefunds(t *testing.T) {
+	b := newBookings()
+	s, fake := newService(t, b)
+	p := pay(t, s, fake, b.booking.ID)
+	if _, err := s.VerifySinglePayment(context.Background(), p.Reference); err != nil {
+		t.Fatalf("VerifySinglePayment() = %v", err)
+	}
+	s.live = refusingProvider{s.live}
+
+	var pErr *ProviderError
+	if _, err := s.RefundSinglePayment(context.Background(), p.Reference, RefundPayment{UpdatedAt: time.Now()}); !errors.As(err, &pErr) {
+		t.Fatalf("RefundSinglePayment() = %v, want the provider error", err)
+	}
+	p, _ = s.GetSinglePayment(context.Background(), p.Reference)
+	if p.RefundedAmount != 0 || p.PendingRefundAmount != 0 || p.Status != StatusSuccess {
+		t.Errorf("payment = %+v, want nothing refunded nor reserved", p)
+	}
+}
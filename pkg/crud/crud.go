@@ -0,0 +1,92 @@
+// Package crud is a generic toolkit for the CRUD resources stored in Tigris: a repository, a service and the
+// registration of their mux routes, parameterised over the entity, its create and update DTOs and the type of its
+// primary key.
+//
+// A resource is described once by a Resource, whose hooks build and change the entities, scope the reads to the
+// caller, authorize the writes and record them, e.g. in the outbox, in the same transaction.
+package crud
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/sort"
+)
+
+var (
+	// ErrNotFound is returned when no entity has the given key, unless the Resource sets its own error.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is wrapped by the resource errors reported with a 409.
+	ErrConflict = errors.New("conflict")
+	// ErrForbidden is wrapped by the resource errors reported with a 403.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Op is the operation an authorization hook is called for.
+type Op string
+
+// The operations of the resources.
+const (
+	OpRead   Op = "read"
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// Key is the type of the primary keys, the Tigris field types entities can be read by.
+type Key interface {
+	string | int | int32 | int64 | uuid.UUID
+}
+
+// Resource describes how the entities of a resource are built, stored and guarded.
+type Resource[T, C, U any, K Key] struct {
+	// KeyField is the field of the primary key, e.g. "id".
+	KeyField string
+	// Key returns the primary key of the entity.
+	Key func(entity T) K
+	// Lookups are the fields the entities are read by, tried in order, e.g. an alias after the primary key.
+	// Defaults to the KeyField.
+	Lookups []string
+	// SearchFields are the fields of the full-text searches, all the indexed ones by default.
+	SearchFields []string
+	// New builds the entity created from the DTO, with its defaults set.
+	New func(ctx context.Context, dto C) (T, error)
+	// Changes returns the fields set by the update DTO.
+	Changes func(dto U) map[string]interface{}
+	// Scope returns the filter of the entities the caller of the context sees, e.g. the ones of its organization.
+	// Every entity is visible when it's nil.
+	Scope func(ctx context.Context) filter.Expr
+	// Authorize is called with the created entity, or the stored one before it's updated or deleted,
+	// and fails the write when it returns an error.
+	Authorize func(ctx context.Context, op Op, entity T) error
+	// OnWrite is called with the written entity in the transaction of the write, e.g. to append its event to the outbox.
+	OnWrite func(ctx context.Context, op Op, entity T) error
+	// ErrNotFound is returned when no entity has the given key, defaults to ErrNotFound.
+	ErrNotFound error
+}
+
+// Query is the filter and order of a list.
+type Query interface {
+	Filter() filter.Filter
+	Sort() sort.Order
+}
+
+// NewQuery returns the Query of the filter and order, a nil order leaving the entities unsorted.
+func NewQuery(f filter.Filter, order sort.Order) Query {
+	return query{f: f, order: order}
+}
+
+type query struct {
+	f     filter.Filter
+	order sort.Order
+}
+
+func (q query) Filter() filter.Filter {
+	return q.f
+}
+
+func (q query) Sort() sort.Order {
+	return q.order
+}
@@ -0,0 +1,129 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tigrisdata/tigris-client-go/fields"
+)
+
+// ErrInvalidFields is wrapped by the errors of the sparse fieldsets with fields the entities don't have.
+var ErrInvalidFields = errors.New("invalid fields")
+
+type fieldsKey struct{}
+
+// WithFields returns a copy of the context whose reads only return the fields, and the key field of the entities.
+// The reads of a context without fields return whole entities.
+func WithFields(ctx context.Context, fields []string) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// FieldsFromContext returns the fields the reads of the context are restricted to, none when they aren't.
+func FieldsFromContext(ctx context.Context) []string {
+	fields, _ := ctx.Value(fieldsKey{}).([]string)
+	return fields
+}
+
+// JSONFields returns the names of the top-level JSON fields of the struct type T.
+func JSONFields[T any]() []string {
+	var names []string
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// ParseFields reads the sparse fieldset of the comma-separated `fields` query param, e.g. `fields=name,uniqueName`,
+// checking them against the JSON fields of T. The fields are sorted and deduplicated, none meaning all of them.
+func ParseFields[T any](r *http.Request) ([]string, error) {
+	v := r.URL.Query().Get("fields")
+	if v == "" {
+		return nil, nil
+	}
+	allowed := map[string]bool{}
+	for _, name := range JSONFields[T]() {
+		allowed[name] = true
+	}
+	seen := map[string]bool{}
+	var fs []string
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if !allowed[name] {
+			return nil, fmt.Errorf("%w: unknown field %q, must be one of %s", ErrInvalidFields, name, strings.Join(JSONFields[T](), ", "))
+		}
+		seen[name] = true
+		fs = append(fs, name)
+	}
+	sort.Strings(fs)
+	return fs, nil
+}
+
+// Project returns the JSON objects of the data, an entity or a slice of them, with only the fields,
+// leaving out the zero values of the ones that weren't read. The data is returned as is without fields.
+func Project(data interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	keep := func(v interface{}) interface{} {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		projected := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			if fv, ok := m[f]; ok {
+				projected[f] = fv
+			}
+		}
+		return projected
+	}
+	if list, ok := v.([]interface{}); ok {
+		for i := range list {
+			list[i] = keep(list[i])
+		}
+		return list, nil
+	}
+	return keep(v), nil
+}
+
+// projection returns the Tigris projection of the fields of the context, along with the key field.
+func projection(ctx context.Context, keyField string) *fields.Read {
+	fs := FieldsFromContext(ctx)
+	if len(fs) == 0 {
+		return fields.All
+	}
+	read := fields.Include(keyField)
+	for _, f := range fs {
+		read.Include(f)
+	}
+	return read
+}
@@ -0,0 +1,63 @@
+package crud
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+// Routes are the handlers of the routes of a resource, the nil ones aren't registered.
+type Routes struct {
+	List   http.HandlerFunc
+	Search http.HandlerFunc
+	Get    http.HandlerFunc
+	Create http.HandlerFunc
+	Update http.HandlerFunc
+	Delete http.HandlerFunc
+}
+
+// Register registers the routes of the resource under its collection path, e.g. `GET /customers/{id}` for "/customers".
+//
+// The routes of the single entities match any `{id}`, so the other routes under the path must be registered first.
+func Register(router *mux.Router, path string, routes Routes) {
+	for _, route := range []struct {
+		path    string
+		method  string
+		handler http.HandlerFunc
+	}{
+		{path, http.MethodGet, routes.List},
+		{path + "/search", http.MethodGet, routes.Search},
+		{path + "/{id}", http.MethodGet, routes.Get},
+		{path, http.MethodPost, routes.Create},
+		{path + "/{id}", http.MethodPatch, routes.Update},
+		{path + "/{id}", http.MethodDelete, routes.Delete},
+	} {
+		if route.handler != nil {
+			router.HandleFunc(route.path, route.handler).Methods(route.method)
+		}
+	}
+}
+
+// PaginationQueryParams reads the pagination params from the request's query string,
+// falling back to the defaults for missing or invalid values.
+func PaginationQueryParams(r *http.Request) params.PaginationQueryParams {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	paginate, err := strconv.ParseBool(r.URL.Query().Get("paginate"))
+	if err != nil {
+		paginate = true
+	}
+	return params.PaginationQueryParams{
+		Page:     page,
+		Limit:    limit,
+		Paginate: paginate,
+	}
+}
@@ -0,0 +1,256 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/search"
+	"github.com/tigrisdata/tigris-client-go/sort"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+// Repository is the persistence layer of a resource.
+type Repository[T, C, U any, K Key] interface {
+	List(ctx context.Context, qp params.PaginationQueryParams, q Query) ([]T, *pagination.PaginationData, error)
+	Search(ctx context.Context, q string, qp params.PaginationQueryParams) ([]T, *pagination.PaginationData, error)
+	Get(ctx context.Context, id K) (T, error)
+	Create(ctx context.Context, dto C) (T, error)
+	Update(ctx context.Context, id K, dto U) (T, error)
+	Delete(ctx context.Context, id K) error
+}
+
+// TigrisRepository is the Repository of a resource stored in a Tigris collection.
+//
+// The writes are made in a transaction with their OnWrite hook, and deleting an unknown entity is a no-op.
+type TigrisRepository[T, C, U any, K Key] struct {
+	db         *tigris.Database
+	collection *tigris.Collection[T]
+	res        Resource[T, C, U, K]
+}
+
+// NewTigrisRepository returns the Repository of the resource, stored in the collection of its entity type.
+func NewTigrisRepository[T, C, U any, K Key](db *tigris.Database, res Resource[T, C, U, K]) *TigrisRepository[T, C, U, K] {
+	if len(res.Lookups) == 0 {
+		res.Lookups = []string{res.KeyField}
+	}
+	if res.ErrNotFound == nil {
+		res.ErrNotFound = ErrNotFound
+	}
+	return &TigrisRepository[T, C, U, K]{db: db, collection: tigris.GetCollection[T](db), res: res}
+}
+
+func (r *TigrisRepository[T, C, U, K]) List(ctx context.Context, qp params.PaginationQueryParams, q Query) ([]T, *pagination.PaginationData, error) {
+	var entities []T = []T{}
+	f := r.scoped(ctx, q.Filter())
+
+	// Add initial pagination data
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	c, err := r.collection.Count(ctx, f)
+	if err != nil {
+		return entities, &m, err
+	}
+	if !qp.Paginate {
+		m.Page = 1
+		m.PerPage = c
+	}
+	options := tigris.ReadOptions{
+		Skip:  (m.Page - 1) * m.PerPage,
+		Limit: m.PerPage,
+		Sort:  q.Sort(),
+	}
+	it, err := r.collection.ReadWithOptions(ctx, f, projection(ctx, r.res.KeyField), &options)
+	if err != nil {
+		return entities, &m, err
+	}
+	defer it.Close()
+
+	var entity T
+	for it.Next(&entity) {
+		entities = append(entities, entity)
+	}
+
+	m = pagination.New(m.Page, m.PerPage, c)
+
+	return entities, &m, it.Err()
+}
+
+// Search runs a full-text search over the entities the caller sees, only returning the fields of the context.
+func (r *TigrisRepository[T, C, U, K]) Search(ctx context.Context, q string, qp params.PaginationQueryParams) ([]T, *pagination.PaginationData, error) {
+	var entities []T = []T{}
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	req := search.NewRequestBuilder().
+		WithQuery(q).
+		WithOptions(&search.Options{Page: int32(qp.Page), PageSize: int32(qp.Limit)})
+	if len(r.res.SearchFields) > 0 {
+		req = req.WithSearchFields(r.res.SearchFields...)
+	}
+	if f := r.scoped(ctx, filter.All); len(f) > 0 {
+		req = req.WithFilter(f)
+	}
+	if fs := FieldsFromContext(ctx); len(fs) > 0 {
+		req = req.WithIncludeFields(append([]string{r.res.KeyField}, fs...)...)
+	}
+	it, err := r.collection.Search(ctx, req.Build())
+	if err != nil {
+		return entities, &m, err
+	}
+	defer it.Close()
+
+	var res search.Result[T]
+	for it.Next(&res) {
+		for _, hit := range res.Hits {
+			entities = append(entities, *hit.Document)
+		}
+		m.Total = res.Meta.Found
+	}
+	if err := it.Err(); err != nil {
+		return entities, &m, err
+	}
+	m = pagination.New(m.Page, m.PerPage, m.Total)
+
+	return entities, &m, nil
+}
+
+// Get reads the entity by each of the lookup fields in turn, only reading the fields of the context.
+func (r *TigrisRepository[T, C, U, K]) Get(ctx context.Context, id K) (T, error) {
+	var zero T
+	for _, field := range r.res.Lookups {
+		entity, err := r.collection.ReadOne(ctx, r.scoped(ctx, filter.Eq(field, id)), projection(ctx, r.res.KeyField))
+		if errors.Is(err, tigris.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return zero, err
+		}
+		return *entity, nil
+	}
+	return zero, r.res.ErrNotFound
+}
+
+func (r *TigrisRepository[T, C, U, K]) Create(ctx context.Context, dto C) (T, error) {
+	var zero T
+	entity, err := r.res.New(ctx, dto)
+	if err != nil {
+		return zero, err
+	}
+	if err := r.authorize(ctx, OpCreate, entity); err != nil {
+		return zero, err
+	}
+	var resp *tigris.InsertResponse
+	err = r.db.Tx(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = r.collection.Insert(ctx, &entity)
+		if err != nil {
+			return err
+		}
+		return r.onWrite(ctx, OpCreate, entity)
+	})
+	if err != nil {
+		return zero, err
+	}
+	if len(resp.Keys) != 1 {
+		return zero, fmt.Errorf("error counting length of response keys, only 1 is expected, %v received", len(resp.Keys))
+	}
+	return entity, nil
+}
+
+// Update sets the changed fields of the entity read by any of the lookup fields, and returns the updated entity.
+// The entity is read whole, whatever the fields of the context.
+func (r *TigrisRepository[T, C, U, K]) Update(ctx context.Context, id K, dto U) (T, error) {
+	var zero T
+	ctx = WithFields(ctx, nil)
+	update := fields.Update{SetF: r.res.Changes(dto)}
+	var updated T
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		entity, err := r.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := r.authorize(ctx, OpUpdate, entity); err != nil {
+			return err
+		}
+		key := r.res.Key(entity)
+		if _, err := r.collection.UpdateOne(ctx, filter.Eq(r.res.KeyField, key), &update); err != nil {
+			return err
+		}
+		updated, err = r.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		return r.onWrite(ctx, OpUpdate, updated)
+	})
+	if err != nil {
+		return zero, err
+	}
+	return updated, nil
+}
+
+// Delete deletes the entity by its primary key, deleting an unknown entity is a no-op.
+func (r *TigrisRepository[T, C, U, K]) Delete(ctx context.Context, id K) error {
+	return r.db.Tx(ctx, func(ctx context.Context) error {
+		entity, err := r.collection.ReadOne(ctx, r.scoped(ctx, filter.Eq(r.res.KeyField, id)))
+		if errors.Is(err, tigris.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.authorize(ctx, OpDelete, *entity); err != nil {
+			return err
+		}
+		if _, err := r.collection.DeleteOne(ctx, filter.Eq(r.res.KeyField, id)); err != nil {
+			return err
+		}
+		return r.onWrite(ctx, OpDelete, *entity)
+	})
+}
+
+// scoped restricts the filter to the entities the caller of the context sees.
+func (r *TigrisRepository[T, C, U, K]) scoped(ctx context.Context, f filter.Filter) filter.Filter {
+	if r.res.Scope == nil {
+		return f
+	}
+	scope := r.res.Scope(ctx)
+	if len(f) == 0 {
+		return scope
+	}
+	return filter.And(f, scope)
+}
+
+func (r *TigrisRepository[T, C, U, K]) authorize(ctx context.Context, op Op, entity T) error {
+	if r.res.Authorize == nil {
+		return nil
+	}
+	return r.res.Authorize(ctx, op, entity)
+}
+
+func (r *TigrisRepository[T, C, U, K]) onWrite(ctx context.Context, op Op, entity T) error {
+	if r.res.OnWrite == nil {
+		return nil
+	}
+	return r.res.OnWrite(ctx, op, entity)
+}
+
+// SortOrder converts a sort query param, a field name optionally prefixed with "-" for a descending order,
+// into a Tigris sort order, sorting by the default field when it's empty.
+func SortOrder(s *string, defaultField string) sort.Order {
+	if s == nil || *s == "" {
+		return sort.Ascending(defaultField)
+	}
+	if strings.HasPrefix(*s, "-") {
+		return sort.Descending(strings.TrimPrefix(*s, "-"))
+	}
+	return sort.Ascending(*s)
+}
@@ -0,0 +1,103 @@
+package crud
+
+import (
+	"context"
+
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+// Service is the business layer of a resource, validating the DTOs and authorizing the operations
+// before handing them to its Repository.
+type Service[T, C, U any, K Key] struct {
+	r         Repository[T, C, U, K]
+	validate  func(dto interface{}) error
+	authorize func(ctx context.Context, op Op) error
+}
+
+// Option configures a Service.
+type Option func(*hooks)
+
+type hooks struct {
+	validate  func(dto interface{}) error
+	authorize func(ctx context.Context, op Op) error
+}
+
+// WithValidator sets how the create and update DTOs are validated, against their `validate` struct tags by default.
+func WithValidator(fn func(dto interface{}) error) Option {
+	return func(h *hooks) {
+		h.validate = fn
+	}
+}
+
+// WithAuthorizer sets the hook authorizing the operations of the context's caller, which are all allowed by default.
+// The writes of single entities are authorized by the Resource.
+func WithAuthorizer(fn func(ctx context.Context, op Op) error) Option {
+	return func(h *hooks) {
+		h.authorize = fn
+	}
+}
+
+// NewService returns the Service of the resource stored in the repository.
+func NewService[T, C, U any, K Key](r Repository[T, C, U, K], opts ...Option) *Service[T, C, U, K] {
+	h := hooks{
+		validate:  validation.Struct,
+		authorize: func(context.Context, Op) error { return nil },
+	}
+	for _, opt := range opts {
+		opt(&h)
+	}
+	return &Service[T, C, U, K]{r: r, validate: h.validate, authorize: h.authorize}
+}
+
+func (s *Service[T, C, U, K]) List(ctx context.Context, qp params.PaginationQueryParams, q Query) ([]T, *pagination.PaginationData, error) {
+	if err := s.authorize(ctx, OpRead); err != nil {
+		return []T{}, nil, err
+	}
+	return s.r.List(ctx, qp, q)
+}
+
+func (s *Service[T, C, U, K]) Search(ctx context.Context, q string, qp params.PaginationQueryParams) ([]T, *pagination.PaginationData, error) {
+	if err := s.authorize(ctx, OpRead); err != nil {
+		return []T{}, nil, err
+	}
+	return s.r.Search(ctx, q, qp)
+}
+
+func (s *Service[T, C, U, K]) Get(ctx context.Context, id K) (T, error) {
+	if err := s.authorize(ctx, OpRead); err != nil {
+		var zero T
+		return zero, err
+	}
+	return s.r.Get(ctx, id)
+}
+
+func (s *Service[T, C, U, K]) Create(ctx context.Context, dto C) (T, error) {
+	var zero T
+	if err := s.validate(dto); err != nil {
+		return zero, err
+	}
+	if err := s.authorize(ctx, OpCreate); err != nil {
+		return zero, err
+	}
+	return s.r.Create(ctx, dto)
+}
+
+func (s *Service[T, C, U, K]) Update(ctx context.Context, id K, dto U) (T, error) {
+	var zero T
+	if err := s.validate(dto); err != nil {
+		return zero, err
+	}
+	if err := s.authorize(ctx, OpUpdate); err != nil {
+		return zero, err
+	}
+	return s.r.Update(ctx, id, dto)
+}
+
+func (s *Service[T, C, U, K]) Delete(ctx context.Context, id K) error {
+	if err := s.authorize(ctx, OpDelete); err != nil {
+		return err
+	}
+	return s.r.Delete(ctx, id)
+}
@@ -0,0 +1,1375 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        (unknown)
+// source: breed/v1/breed.proto
+
+package breedv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Range is an inclusive min/max pair.
+// Weight is measured in kg, height in cm and lifespan in years.
+type Range struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min float64 `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max float64 `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *Range) Reset() {
+	*x = Range{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Range) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Range) ProtoMessage() {}
+
+func (x *Range) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Range.ProtoReflect.Descriptor instead.
+func (*Range) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Range) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Range) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type Breed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UniqueName     string                 `protobuf:"bytes,1,opt,name=unique_name,json=uniqueName,proto3" json:"unique_name,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Url            string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	CreationType   string                 `protobuf:"bytes,4,opt,name=creation_type,json=creationType,proto3" json:"creation_type,omitempty"`
+	Group          string                 `protobuf:"bytes,5,opt,name=group,proto3" json:"group,omitempty"`
+	Origin         string                 `protobuf:"bytes,6,opt,name=origin,proto3" json:"origin,omitempty"`
+	Size           string                 `protobuf:"bytes,7,opt,name=size,proto3" json:"size,omitempty"`
+	Weight         *Range                 `protobuf:"bytes,8,opt,name=weight,proto3" json:"weight,omitempty"`
+	Height         *Range                 `protobuf:"bytes,9,opt,name=height,proto3" json:"height,omitempty"`
+	Lifespan       *Range                 `protobuf:"bytes,10,opt,name=lifespan,proto3" json:"lifespan,omitempty"`
+	Temperament    []string               `protobuf:"bytes,11,rep,name=temperament,proto3" json:"temperament,omitempty"`
+	Aliases        []string               `protobuf:"bytes,12,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	LocalizedNames map[string]string      `protobuf:"bytes,13,rep,name=localized_names,json=localizedNames,proto3" json:"localized_names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *Breed) Reset() {
+	*x = Breed{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Breed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Breed) ProtoMessage() {}
+
+func (x *Breed) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Breed.ProtoReflect.Descriptor instead.
+func (*Breed) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Breed) GetUniqueName() string {
+	if x != nil {
+		return x.UniqueName
+	}
+	return ""
+}
+
+func (x *Breed) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Breed) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Breed) GetCreationType() string {
+	if x != nil {
+		return x.CreationType
+	}
+	return ""
+}
+
+func (x *Breed) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Breed) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Breed) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
+func (x *Breed) GetWeight() *Range {
+	if x != nil {
+		return x.Weight
+	}
+	return nil
+}
+
+func (x *Breed) GetHeight() *Range {
+	if x != nil {
+		return x.Height
+	}
+	return nil
+}
+
+func (x *Breed) GetLifespan() *Range {
+	if x != nil {
+		return x.Lifespan
+	}
+	return nil
+}
+
+func (x *Breed) GetTemperament() []string {
+	if x != nil {
+		return x.Temperament
+	}
+	return nil
+}
+
+func (x *Breed) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *Breed) GetLocalizedNames() map[string]string {
+	if x != nil {
+		return x.LocalizedNames
+	}
+	return nil
+}
+
+func (x *Breed) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Breed) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Pagination struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Total     int64 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Page      int64 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PerPage   int64 `protobuf:"varint,3,opt,name=per_page,json=perPage,proto3" json:"per_page,omitempty"`
+	Prev      int64 `protobuf:"varint,4,opt,name=prev,proto3" json:"prev,omitempty"`
+	Next      int64 `protobuf:"varint,5,opt,name=next,proto3" json:"next,omitempty"`
+	TotalPage int64 `protobuf:"varint,6,opt,name=total_page,json=totalPage,proto3" json:"total_page,omitempty"`
+}
+
+func (x *Pagination) Reset() {
+	*x = Pagination{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pagination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pagination) ProtoMessage() {}
+
+func (x *Pagination) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
+func (*Pagination) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Pagination) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Pagination) GetPage() int64 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Pagination) GetPerPage() int64 {
+	if x != nil {
+		return x.PerPage
+	}
+	return 0
+}
+
+func (x *Pagination) GetPrev() int64 {
+	if x != nil {
+		return x.Prev
+	}
+	return 0
+}
+
+func (x *Pagination) GetNext() int64 {
+	if x != nil {
+		return x.Next
+	}
+	return 0
+}
+
+func (x *Pagination) GetTotalPage() int64 {
+	if x != nil {
+		return x.TotalPage
+	}
+	return 0
+}
+
+type ListBreedsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Defaults to 1.
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// Defaults to 20.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Defaults to true, when false every matching breed is returned in a single page.
+	Paginate     *bool    `protobuf:"varint,3,opt,name=paginate,proto3,oneof" json:"paginate,omitempty"`
+	CreationType *string  `protobuf:"bytes,4,opt,name=creation_type,json=creationType,proto3,oneof" json:"creation_type,omitempty"`
+	Group        *string  `protobuf:"bytes,5,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Origin       *string  `protobuf:"bytes,6,opt,name=origin,proto3,oneof" json:"origin,omitempty"`
+	Size         *string  `protobuf:"bytes,7,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	Temperament  *string  `protobuf:"bytes,8,opt,name=temperament,proto3,oneof" json:"temperament,omitempty"`
+	MinWeight    *float64 `protobuf:"fixed64,9,opt,name=min_weight,json=minWeight,proto3,oneof" json:"min_weight,omitempty"`
+	MaxWeight    *float64 `protobuf:"fixed64,10,opt,name=max_weight,json=maxWeight,proto3,oneof" json:"max_weight,omitempty"`
+	MinHeight    *float64 `protobuf:"fixed64,11,opt,name=min_height,json=minHeight,proto3,oneof" json:"min_height,omitempty"`
+	MaxHeight    *float64 `protobuf:"fixed64,12,opt,name=max_height,json=maxHeight,proto3,oneof" json:"max_height,omitempty"`
+	MinLifespan  *float64 `protobuf:"fixed64,13,opt,name=min_lifespan,json=minLifespan,proto3,oneof" json:"min_lifespan,omitempty"`
+	MaxLifespan  *float64 `protobuf:"fixed64,14,opt,name=max_lifespan,json=maxLifespan,proto3,oneof" json:"max_lifespan,omitempty"`
+}
+
+func (x *ListBreedsRequest) Reset() {
+	*x = ListBreedsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBreedsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBreedsRequest) ProtoMessage() {}
+
+func (x *ListBreedsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBreedsRequest.ProtoReflect.Descriptor instead.
+func (*ListBreedsRequest) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListBreedsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListBreedsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListBreedsRequest) GetPaginate() bool {
+	if x != nil && x.Paginate != nil {
+		return *x.Paginate
+	}
+	return false
+}
+
+func (x *ListBreedsRequest) GetCreationType() string {
+	if x != nil && x.CreationType != nil {
+		return *x.CreationType
+	}
+	return ""
+}
+
+func (x *ListBreedsRequest) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *ListBreedsRequest) GetOrigin() string {
+	if x != nil && x.Origin != nil {
+		return *x.Origin
+	}
+	return ""
+}
+
+func (x *ListBreedsRequest) GetSize() string {
+	if x != nil && x.Size != nil {
+		return *x.Size
+	}
+	return ""
+}
+
+func (x *ListBreedsRequest) GetTemperament() string {
+	if x != nil && x.Temperament != nil {
+		return *x.Temperament
+	}
+	return ""
+}
+
+func (x *ListBreedsRequest) GetMinWeight() float64 {
+	if x != nil && x.MinWeight != nil {
+		return *x.MinWeight
+	}
+	return 0
+}
+
+func (x *ListBreedsRequest) GetMaxWeight() float64 {
+	if x != nil && x.MaxWeight != nil {
+		return *x.MaxWeight
+	}
+	return 0
+}
+
+func (x *ListBreedsRequest) GetMinHeight() float64 {
+	if x != nil && x.MinHeight != nil {
+		return *x.MinHeight
+	}
+	return 0
+}
+
+func (x *ListBreedsRequest) GetMaxHeight() float64 {
+	if x != nil && x.MaxHeight != nil {
+		return *x.MaxHeight
+	}
+	return 0
+}
+
+func (x *ListBreedsRequest) GetMinLifespan() float64 {
+	if x != nil && x.MinLifespan != nil {
+		return *x.MinLifespan
+	}
+	return 0
+}
+
+func (x *ListBreedsRequest) GetMaxLifespan() float64 {
+	if x != nil && x.MaxLifespan != nil {
+		return *x.MaxLifespan
+	}
+	return 0
+}
+
+type ListBreedsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Breeds     []*Breed    `protobuf:"bytes,1,rep,name=breeds,proto3" json:"breeds,omitempty"`
+	Pagination *Pagination `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+func (x *ListBreedsResponse) Reset() {
+	*x = ListBreedsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBreedsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBreedsResponse) ProtoMessage() {}
+
+func (x *ListBreedsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBreedsResponse.ProtoReflect.Descriptor instead.
+func (*ListBreedsResponse) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListBreedsResponse) GetBreeds() []*Breed {
+	if x != nil {
+		return x.Breeds
+	}
+	return nil
+}
+
+func (x *ListBreedsResponse) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type GetBreedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetBreedRequest) Reset() {
+	*x = GetBreedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBreedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBreedRequest) ProtoMessage() {}
+
+func (x *GetBreedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBreedRequest.ProtoReflect.Descriptor instead.
+func (*GetBreedRequest) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetBreedRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateBreedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name           string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	UniqueName     string            `protobuf:"bytes,2,opt,name=unique_name,json=uniqueName,proto3" json:"unique_name,omitempty"`
+	Url            string            `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	CreationType   string            `protobuf:"bytes,4,opt,name=creation_type,json=creationType,proto3" json:"creation_type,omitempty"`
+	Group          string            `protobuf:"bytes,5,opt,name=group,proto3" json:"group,omitempty"`
+	Origin         string            `protobuf:"bytes,6,opt,name=origin,proto3" json:"origin,omitempty"`
+	Size           string            `protobuf:"bytes,7,opt,name=size,proto3" json:"size,omitempty"`
+	Weight         *Range            `protobuf:"bytes,8,opt,name=weight,proto3" json:"weight,omitempty"`
+	Height         *Range            `protobuf:"bytes,9,opt,name=height,proto3" json:"height,omitempty"`
+	Lifespan       *Range            `protobuf:"bytes,10,opt,name=lifespan,proto3" json:"lifespan,omitempty"`
+	Temperament    []string          `protobuf:"bytes,11,rep,name=temperament,proto3" json:"temperament,omitempty"`
+	Aliases        []string          `protobuf:"bytes,12,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	LocalizedNames map[string]string `protobuf:"bytes,13,rep,name=localized_names,json=localizedNames,proto3" json:"localized_names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *CreateBreedRequest) Reset() {
+	*x = CreateBreedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateBreedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBreedRequest) ProtoMessage() {}
+
+func (x *CreateBreedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBreedRequest.ProtoReflect.Descriptor instead.
+func (*CreateBreedRequest) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateBreedRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateBreedRequest) GetUniqueName() string {
+	if x != nil {
+		return x.UniqueName
+	}
+	return ""
+}
+
+func (x *CreateBreedRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateBreedRequest) GetCreationType() string {
+	if x != nil {
+		return x.CreationType
+	}
+	return ""
+}
+
+func (x *CreateBreedRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *CreateBreedRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *CreateBreedRequest) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
+func (x *CreateBreedRequest) GetWeight() *Range {
+	if x != nil {
+		return x.Weight
+	}
+	return nil
+}
+
+func (x *CreateBreedRequest) GetHeight() *Range {
+	if x != nil {
+		return x.Height
+	}
+	return nil
+}
+
+func (x *CreateBreedRequest) GetLifespan() *Range {
+	if x != nil {
+		return x.Lifespan
+	}
+	return nil
+}
+
+func (x *CreateBreedRequest) GetTemperament() []string {
+	if x != nil {
+		return x.Temperament
+	}
+	return nil
+}
+
+func (x *CreateBreedRequest) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *CreateBreedRequest) GetLocalizedNames() map[string]string {
+	if x != nil {
+		return x.LocalizedNames
+	}
+	return nil
+}
+
+// UpdateBreedRequest only updates the fields which are set.
+// The repeated and map fields are updated when they are non-empty.
+type UpdateBreedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           *string           `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Url            *string           `protobuf:"bytes,3,opt,name=url,proto3,oneof" json:"url,omitempty"`
+	Group          *string           `protobuf:"bytes,4,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Origin         *string           `protobuf:"bytes,5,opt,name=origin,proto3,oneof" json:"origin,omitempty"`
+	Size           *string           `protobuf:"bytes,6,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	Weight         *Range            `protobuf:"bytes,7,opt,name=weight,proto3" json:"weight,omitempty"`
+	Height         *Range            `protobuf:"bytes,8,opt,name=height,proto3" json:"height,omitempty"`
+	Lifespan       *Range            `protobuf:"bytes,9,opt,name=lifespan,proto3" json:"lifespan,omitempty"`
+	Temperament    []string          `protobuf:"bytes,10,rep,name=temperament,proto3" json:"temperament,omitempty"`
+	Aliases        []string          `protobuf:"bytes,11,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	LocalizedNames map[string]string `protobuf:"bytes,12,rep,name=localized_names,json=localizedNames,proto3" json:"localized_names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *UpdateBreedRequest) Reset() {
+	*x = UpdateBreedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateBreedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBreedRequest) ProtoMessage() {}
+
+func (x *UpdateBreedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBreedRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBreedRequest) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateBreedRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateBreedRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateBreedRequest) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *UpdateBreedRequest) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *UpdateBreedRequest) GetOrigin() string {
+	if x != nil && x.Origin != nil {
+		return *x.Origin
+	}
+	return ""
+}
+
+func (x *UpdateBreedRequest) GetSize() string {
+	if x != nil && x.Size != nil {
+		return *x.Size
+	}
+	return ""
+}
+
+func (x *UpdateBreedRequest) GetWeight() *Range {
+	if x != nil {
+		return x.Weight
+	}
+	return nil
+}
+
+func (x *UpdateBreedRequest) GetHeight() *Range {
+	if x != nil {
+		return x.Height
+	}
+	return nil
+}
+
+func (x *UpdateBreedRequest) GetLifespan() *Range {
+	if x != nil {
+		return x.Lifespan
+	}
+	return nil
+}
+
+func (x *UpdateBreedRequest) GetTemperament() []string {
+	if x != nil {
+		return x.Temperament
+	}
+	return nil
+}
+
+func (x *UpdateBreedRequest) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *UpdateBreedRequest) GetLocalizedNames() map[string]string {
+	if x != nil {
+		return x.LocalizedNames
+	}
+	return nil
+}
+
+type DeleteBreedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteBreedRequest) Reset() {
+	*x = DeleteBreedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteBreedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBreedRequest) ProtoMessage() {}
+
+func (x *DeleteBreedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBreedRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBreedRequest) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteBreedRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteBreedResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteBreedResponse) Reset() {
+	*x = DeleteBreedResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_breed_v1_breed_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteBreedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBreedResponse) ProtoMessage() {}
+
+func (x *DeleteBreedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_breed_v1_breed_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBreedResponse.ProtoReflect.Descriptor instead.
+func (*DeleteBreedResponse) Descriptor() ([]byte, []int) {
+	return file_breed_v1_breed_proto_rawDescGZIP(), []int{9}
+}
+
+var File_breed_v1_breed_proto protoreflect.FileDescriptor
+
+var file_breed_v1_breed_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x72, 0x65, 0x65, 0x64,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31,
+	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x2b, 0x0a, 0x05, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03,
+	0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0xf7,
+	0x04, 0x0a, 0x05, 0x42, 0x72, 0x65, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x6e, 0x69, 0x71,
+	0x75, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x75,
+	0x6e, 0x69, 0x71, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12,
+	0x23, 0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x27, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2b, 0x0a, 0x08, 0x6c, 0x69, 0x66, 0x65,
+	0x73, 0x70, 0x61, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x72, 0x65,
+	0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x08, 0x6c, 0x69, 0x66,
+	0x65, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61,
+	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70,
+	0x65, 0x72, 0x61, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73,
+	0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65,
+	0x73, 0x12, 0x4c, 0x0a, 0x0f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x62, 0x72, 0x65,
+	0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x4c, 0x6f, 0x63, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12,
+	0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x1a, 0x41, 0x0a, 0x13, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x98, 0x01, 0x0a, 0x0a, 0x50, 0x61, 0x67,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x70, 0x61, 0x67,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x65, 0x72, 0x50, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x72, 0x65, 0x76, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x70, 0x72, 0x65, 0x76,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x6e, 0x65, 0x78, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x70, 0x61,
+	0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x50,
+	0x61, 0x67, 0x65, 0x22, 0x8b, 0x05, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x72, 0x65, 0x65,
+	0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x08, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0c, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x19,
+	0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x06, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x25, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x05, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x6d,
+	0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x5f, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x48, 0x06, 0x52, 0x09, 0x6d, 0x69,
+	0x6e, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x61,
+	0x78, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x48, 0x07,
+	0x52, 0x09, 0x6d, 0x61, 0x78, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x22,
+	0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x08, 0x52, 0x09, 0x6d, 0x69, 0x6e, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x88,
+	0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x48, 0x09, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x48, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x6d, 0x69, 0x6e, 0x5f, 0x6c, 0x69,
+	0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x48, 0x0a, 0x52, 0x0b,
+	0x6d, 0x69, 0x6e, 0x4c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x26,
+	0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x0b, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x4c, 0x69, 0x66, 0x65, 0x73,
+	0x70, 0x61, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x61, 0x67, 0x69, 0x6e,
+	0x61, 0x74, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x6d,
+	0x65, 0x6e, 0x74, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x77, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x42,
+	0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e,
+	0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61,
+	0x6e, 0x22, 0x73, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x72, 0x65, 0x65, 0x64, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x62, 0x72, 0x65, 0x65, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x06, 0x62, 0x72, 0x65, 0x65, 0x64, 0x73,
+	0x12, 0x34, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x42, 0x72, 0x65,
+	0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x9b, 0x04, 0x0a, 0x12, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x6e, 0x69, 0x71, 0x75,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x27,
+	0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x2b, 0x0a, 0x08, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x08, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x20, 0x0a,
+	0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x59, 0x0a, 0x0f, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x30, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x1a, 0x41, 0x0a, 0x13, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xad, 0x04, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x19,
+	0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x06, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x27, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x12, 0x2b, 0x0a, 0x08, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x52, 0x08, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x20,
+	0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0a, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x59, 0x0a, 0x0f, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x0c, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64,
+	0x4e, 0x61, 0x6d, 0x65, 0x73, 0x1a, 0x41, 0x0a, 0x13, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x75, 0x72, 0x6c, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x42, 0x07,
+	0x0a, 0x05, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x24, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x15, 0x0a,
+	0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x32, 0x97, 0x03, 0x0a, 0x0c, 0x42, 0x72, 0x65, 0x65, 0x64, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x72, 0x65,
+	0x65, 0x64, 0x73, 0x12, 0x1b, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x42, 0x72, 0x65, 0x65, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x42, 0x72, 0x65, 0x65, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e,
+	0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42, 0x72, 0x65, 0x65, 0x64, 0x73, 0x12, 0x1b,
+	0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x72,
+	0x65, 0x65, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x72,
+	0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x65, 0x65, 0x64, 0x30, 0x01, 0x12, 0x36,
+	0x0a, 0x08, 0x47, 0x65, 0x74, 0x42, 0x72, 0x65, 0x65, 0x64, 0x12, 0x19, 0x2e, 0x62, 0x72, 0x65,
+	0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x72, 0x65, 0x65, 0x64, 0x12, 0x3c, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x42, 0x72, 0x65, 0x65, 0x64, 0x12, 0x1c, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x72, 0x65, 0x65, 0x64, 0x12, 0x3c, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x72,
+	0x65, 0x65, 0x64, 0x12, 0x1c, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x65,
+	0x65, 0x64, 0x12, 0x4a, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x72, 0x65, 0x65,
+	0x64, 0x12, 0x1c, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x42, 0x72, 0x65, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x43,
+	0x5a, 0x41, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x69, 0x6d,
+	0x70, 0x6c, 0x79, 0x2d, 0x61, 0x6c, 0x6c, 0x69, 0x76, 0x2f, 0x74, 0x69, 0x67, 0x72, 0x69, 0x73,
+	0x2d, 0x67, 0x6f, 0x2d, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
+	0x70, 0x62, 0x2f, 0x62, 0x72, 0x65, 0x65, 0x64, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x72, 0x65, 0x65,
+	0x64, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_breed_v1_breed_proto_rawDescOnce sync.Once
+	file_breed_v1_breed_proto_rawDescData = file_breed_v1_breed_proto_rawDesc
+)
+
+func file_breed_v1_breed_proto_rawDescGZIP() []byte {
+	file_breed_v1_breed_proto_rawDescOnce.Do(func() {
+		file_breed_v1_breed_proto_rawDescData = protoimpl.X.CompressGZIP(file_breed_v1_breed_proto_rawDescData)
+	})
+	return file_breed_v1_breed_proto_rawDescData
+}
+
+var file_breed_v1_breed_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_breed_v1_breed_proto_goTypes = []interface{}{
+	(*Range)(nil),                 // 0: breed.v1.Range
+	(*Breed)(nil),                 // 1: breed.v1.Breed
+	(*Pagination)(nil),            // 2: breed.v1.Pagination
+	(*ListBreedsRequest)(nil),     // 3: breed.v1.ListBreedsRequest
+	(*ListBreedsResponse)(nil),    // 4: breed.v1.ListBreedsResponse
+	(*GetBreedRequest)(nil),       // 5: breed.v1.GetBreedRequest
+	(*CreateBreedRequest)(nil),    // 6: breed.v1.CreateBreedRequest
+	(*UpdateBreedRequest)(nil),    // 7: breed.v1.UpdateBreedRequest
+	(*DeleteBreedRequest)(nil),    // 8: breed.v1.DeleteBreedRequest
+	(*DeleteBreedResponse)(nil),   // 9: breed.v1.DeleteBreedResponse
+	nil,                           // 10: breed.v1.Breed.LocalizedNamesEntry
+	nil,                           // 11: breed.v1.CreateBreedRequest.LocalizedNamesEntry
+	nil,                           // 12: breed.v1.UpdateBreedRequest.LocalizedNamesEntry
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+}
+var file_breed_v1_breed_proto_depIdxs = []int32{
+	0,  // 0: breed.v1.Breed.weight:type_name -> breed.v1.Range
+	0,  // 1: breed.v1.Breed.height:type_name -> breed.v1.Range
+	0,  // 2: breed.v1.Breed.lifespan:type_name -> breed.v1.Range
+	10, // 3: breed.v1.Breed.localized_names:type_name -> breed.v1.Breed.LocalizedNamesEntry
+	13, // 4: breed.v1.Breed.created_at:type_name -> google.protobuf.Timestamp
+	13, // 5: breed.v1.Breed.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 6: breed.v1.ListBreedsResponse.breeds:type_name -> breed.v1.Breed
+	2,  // 7: breed.v1.ListBreedsResponse.pagination:type_name -> breed.v1.Pagination
+	0,  // 8: breed.v1.CreateBreedRequest.weight:type_name -> breed.v1.Range
+	0,  // 9: breed.v1.CreateBreedRequest.height:type_name -> breed.v1.Range
+	0,  // 10: breed.v1.CreateBreedRequest.lifespan:type_name -> breed.v1.Range
+	11, // 11: breed.v1.CreateBreedRequest.localized_names:type_name -> breed.v1.CreateBreedRequest.LocalizedNamesEntry
+	0,  // 12: breed.v1.UpdateBreedRequest.weight:type_name -> breed.v1.Range
+	0,  // 13: breed.v1.UpdateBreedRequest.height:type_name -> breed.v1.Range
+	0,  // 14: breed.v1.UpdateBreedRequest.lifespan:type_name -> breed.v1.Range
+	12, // 15: breed.v1.UpdateBreedRequest.localized_names:type_name -> breed.v1.UpdateBreedRequest.LocalizedNamesEntry
+	3,  // 16: breed.v1.BreedService.ListBreeds:input_type -> breed.v1.ListBreedsRequest
+	3,  // 17: breed.v1.BreedService.StreamBreeds:input_type -> breed.v1.ListBreedsRequest
+	5,  // 18: breed.v1.BreedService.GetBreed:input_type -> breed.v1.GetBreedRequest
+	6,  // 19: breed.v1.BreedService.CreateBreed:input_type -> breed.v1.CreateBreedRequest
+	7,  // 20: breed.v1.BreedService.UpdateBreed:input_type -> breed.v1.UpdateBreedRequest
+	8,  // 21: breed.v1.BreedService.DeleteBreed:input_type -> breed.v1.DeleteBreedRequest
+	4,  // 22: breed.v1.BreedService.ListBreeds:output_type -> breed.v1.ListBreedsResponse
+	1,  // 23: breed.v1.BreedService.StreamBreeds:output_type -> breed.v1.Breed
+	1,  // 24: breed.v1.BreedService.GetBreed:output_type -> breed.v1.Breed
+	1,  // 25: breed.v1.BreedService.CreateBreed:output_type -> breed.v1.Breed
+	1,  // 26: breed.v1.BreedService.UpdateBreed:output_type -> breed.v1.Breed
+	9,  // 27: breed.v1.BreedService.DeleteBreed:output_type -> breed.v1.DeleteBreedResponse
+	22, // [22:28] is the sub-list for method output_type
+	16, // [16:22] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_breed_v1_breed_proto_init() }
+func file_breed_v1_breed_proto_init() {
+	if File_breed_v1_breed_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_breed_v1_breed_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Range); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Breed); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pagination); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBreedsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBreedsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBreedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateBreedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateBreedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBreedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_breed_v1_breed_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBreedResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_breed_v1_breed_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_breed_v1_breed_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_breed_v1_breed_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_breed_v1_breed_proto_goTypes,
+		DependencyIndexes: file_breed_v1_breed_proto_depIdxs,
+		MessageInfos:      file_breed_v1_breed_proto_msgTypes,
+	}.Build()
+	File_breed_v1_breed_proto = out.File
+	file_breed_v1_breed_proto_rawDesc = nil
+	file_breed_v1_breed_proto_goTypes = nil
+	file_breed_v1_breed_proto_depIdxs = nil
+}
@@ -0,0 +1,338 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: breed/v1/breed.proto
+
+package breedv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	BreedService_ListBreeds_FullMethodName   = "/breed.v1.BreedService/ListBreeds"
+	BreedService_StreamBreeds_FullMethodName = "/breed.v1.BreedService/StreamBreeds"
+	BreedService_GetBreed_FullMethodName     = "/breed.v1.BreedService/GetBreed"
+	BreedService_CreateBreed_FullMethodName  = "/breed.v1.BreedService/CreateBreed"
+	BreedService_UpdateBreed_FullMethodName  = "/breed.v1.BreedService/UpdateBreed"
+	BreedService_DeleteBreed_FullMethodName  = "/breed.v1.BreedService/DeleteBreed"
+)
+
+// BreedServiceClient is the client API for BreedService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BreedServiceClient interface {
+	// ListBreeds returns a page of breeds, ordered by name.
+	ListBreeds(ctx context.Context, in *ListBreedsRequest, opts ...grpc.CallOption) (*ListBreedsResponse, error)
+	// StreamBreeds streams every breed matching the filters, ordered by name.
+	// The pagination fields of the request are ignored.
+	StreamBreeds(ctx context.Context, in *ListBreedsRequest, opts ...grpc.CallOption) (BreedService_StreamBreedsClient, error)
+	// GetBreed returns a breed by its unique name or one of its aliases.
+	GetBreed(ctx context.Context, in *GetBreedRequest, opts ...grpc.CallOption) (*Breed, error)
+	// CreateBreed creates a breed, the unique name is derived from the name when empty.
+	// Near-duplicate warnings are returned in the "warning" header metadata.
+	CreateBreed(ctx context.Context, in *CreateBreedRequest, opts ...grpc.CallOption) (*Breed, error)
+	// UpdateBreed updates the fields of a breed which are set in the request.
+	UpdateBreed(ctx context.Context, in *UpdateBreedRequest, opts ...grpc.CallOption) (*Breed, error)
+	// DeleteBreed deletes a breed.
+	DeleteBreed(ctx context.Context, in *DeleteBreedRequest, opts ...grpc.CallOption) (*DeleteBreedResponse, error)
+}
+
+type breedServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBreedServiceClient(cc grpc.ClientConnInterface) BreedServiceClient {
+	return &breedServiceClient{cc}
+}
+
+func (c *breedServiceClient) ListBreeds(ctx context.Context, in *ListBreedsRequest, opts ...grpc.CallOption) (*ListBreedsResponse, error) {
+	out := new(ListBreedsResponse)
+	err := c.cc.Invoke(ctx, BreedService_ListBreeds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *breedServiceClient) StreamBreeds(ctx context.Context, in *ListBreedsRequest, opts ...grpc.CallOption) (BreedService_StreamBreedsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BreedService_ServiceDesc.Streams[0], BreedService_StreamBreeds_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &breedServiceStreamBreedsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BreedService_StreamBreedsClient interface {
+	Recv() (*Breed, error)
+	grpc.ClientStream
+}
+
+type breedServiceStreamBreedsClient struct {
+	grpc.ClientStream
+}
+
+func (x *breedServiceStreamBreedsClient) Recv() (*Breed, error) {
+	m := new(Breed)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *breedServiceClient) GetBreed(ctx context.Context, in *GetBreedRequest, opts ...grpc.CallOption) (*Breed, error) {
+	out := new(Breed)
+	err := c.cc.Invoke(ctx, BreedService_GetBreed_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *breedServiceClient) CreateBreed(ctx context.Context, in *CreateBreedRequest, opts ...grpc.CallOption) (*Breed, error) {
+	out := new(Breed)
+	err := c.cc.Invoke(ctx, BreedService_CreateBreed_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *breedServiceClient) UpdateBreed(ctx context.Context, in *UpdateBreedRequest, opts ...grpc.CallOption) (*Breed, error) {
+	out := new(Breed)
+	err := c.cc.Invoke(ctx, BreedService_UpdateBreed_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *breedServiceClient) DeleteBreed(ctx context.Context, in *DeleteBreedRequest, opts ...grpc.CallOption) (*DeleteBreedResponse, error) {
+	out := new(DeleteBreedResponse)
+	err := c.cc.Invoke(ctx, BreedService_DeleteBreed_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BreedServiceServer is the server API for BreedService service.
+// All implementations must embed UnimplementedBreedServiceServer
+// for forward compatibility
+type BreedServiceServer interface {
+	// ListBreeds returns a page of breeds, ordered by name.
+	ListBreeds(context.Context, *ListBreedsRequest) (*ListBreedsResponse, error)
+	// StreamBreeds streams every breed matching the filters, ordered by name.
+	// The pagination fields of the request are ignored.
+	StreamBreeds(*ListBreedsRequest, BreedService_StreamBreedsServer) error
+	// GetBreed returns a breed by its unique name or one of its aliases.
+	GetBreed(context.Context, *GetBreedRequest) (*Breed, error)
+	// CreateBreed creates a breed, the unique name is derived from the name when empty.
+	// Near-duplicate warnings are returned in the "warning" header metadata.
+	CreateBreed(context.Context, *CreateBreedRequest) (*Breed, error)
+	// UpdateBreed updates the fields of a breed which are set in the request.
+	UpdateBreed(context.Context, *UpdateBreedRequest) (*Breed, error)
+	// DeleteBreed deletes a breed.
+	DeleteBreed(context.Context, *DeleteBreedRequest) (*DeleteBreedResponse, error)
+	mustEmbedUnimplementedBreedServiceServer()
+}
+
+// UnimplementedBreedServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBreedServiceServer struct {
+}
+
+func (UnimplementedBreedServiceServer) ListBreeds(context.Context, *ListBreedsRequest) (*ListBreedsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBreeds not implemented")
+}
+func (UnimplementedBreedServiceServer) StreamBreeds(*ListBreedsRequest, BreedService_StreamBreedsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBreeds not implemented")
+}
+func (UnimplementedBreedServiceServer) GetBreed(context.Context, *GetBreedRequest) (*Breed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBreed not implemented")
+}
+func (UnimplementedBreedServiceServer) CreateBreed(context.Context, *CreateBreedRequest) (*Breed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBreed not implemented")
+}
+func (UnimplementedBreedServiceServer) UpdateBreed(context.Context, *UpdateBreedRequest) (*Breed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBreed not implemented")
+}
+func (UnimplementedBreedServiceServer) DeleteBreed(context.Context, *DeleteBreedRequest) (*DeleteBreedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBreed not implemented")
+}
+func (UnimplementedBreedServiceServer) mustEmbedUnimplementedBreedServiceServer() {}
+
+// UnsafeBreedServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BreedServiceServer will
+// result in compilation errors.
+type UnsafeBreedServiceServer interface {
+	mustEmbedUnimplementedBreedServiceServer()
+}
+
+func RegisterBreedServiceServer(s grpc.ServiceRegistrar, srv BreedServiceServer) {
+	s.RegisterService(&BreedService_ServiceDesc, srv)
+}
+
+func _BreedService_ListBreeds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBreedsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BreedServiceServer).ListBreeds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BreedService_ListBreeds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BreedServiceServer).ListBreeds(ctx, req.(*ListBreedsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BreedService_StreamBreeds_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBreedsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BreedServiceServer).StreamBreeds(m, &breedServiceStreamBreedsServer{stream})
+}
+
+type BreedService_StreamBreedsServer interface {
+	Send(*Breed) error
+	grpc.ServerStream
+}
+
+type breedServiceStreamBreedsServer struct {
+	grpc.ServerStream
+}
+
+func (x *breedServiceStreamBreedsServer) Send(m *Breed) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BreedService_GetBreed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBreedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BreedServiceServer).GetBreed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BreedService_GetBreed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BreedServiceServer).GetBreed(ctx, req.(*GetBreedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BreedService_CreateBreed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBreedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BreedServiceServer).CreateBreed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BreedService_CreateBreed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BreedServiceServer).CreateBreed(ctx, req.(*CreateBreedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BreedService_UpdateBreed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBreedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BreedServiceServer).UpdateBreed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BreedService_UpdateBreed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BreedServiceServer).UpdateBreed(ctx, req.(*UpdateBreedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BreedService_DeleteBreed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBreedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BreedServiceServer).DeleteBreed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BreedService_DeleteBreed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BreedServiceServer).DeleteBreed(ctx, req.(*DeleteBreedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BreedService_ServiceDesc is the grpc.ServiceDesc for BreedService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BreedService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "breed.v1.BreedService",
+	HandlerType: (*BreedServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBreeds",
+			Handler:    _BreedService_ListBreeds_Handler,
+		},
+		{
+			MethodName: "GetBreed",
+			Handler:    _BreedService_GetBreed_Handler,
+		},
+		{
+			MethodName: "CreateBreed",
+			Handler:    _BreedService_CreateBreed_Handler,
+		},
+		{
+			MethodName: "UpdateBreed",
+			Handler:    _BreedService_UpdateBreed_Handler,
+		},
+		{
+			MethodName: "DeleteBreed",
+			Handler:    _BreedService_DeleteBreed_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBreeds",
+			Handler:       _BreedService_StreamBreeds_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "breed/v1/breed.proto",
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, 0)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Get("a")
+	c.Add("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) found the least recently used entry, want it evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if v, ok := c.Get(key); !ok || v != want {
+			t.Errorf("Get(%s) = %d, %v, want %d", key, v, ok, want)
+		}
+	}
+
+	// Adding an existing key replaces its value without evicting
+	c.Add("a", 10)
+	if v, _ := c.Get("a"); v != 10 {
+		t.Errorf("Get(a) = %d, want 10", v)
+	}
+	if s := c.Stats(); s.Size != 2 || s.Evictions != 1 {
+		t.Errorf("Stats() = %+v, want 2 entries and 1 eviction", s)
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	c := NewLRU[string, int](10, 30*time.Millisecond)
+	c.Add("a", 1)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Get(a) missed before the TTL")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) hit after the TTL, want it expired")
+	}
+	if s := c.Stats(); s.Size != 0 {
+		t.Errorf("Stats().Size = %d, want the expired entry dropped", s.Size)
+	}
+}
+
+func TestLRURemove(t *testing.T) {
+	c := NewLRU[string, int](10, 0)
+	for i, key := range []string{"breed:a", "breed:b", "list:1"} {
+		c.Add(key, i)
+	}
+	c.Remove("breed:a")
+	c.RemoveFunc(func(key string, v int) bool { return v == 2 })
+
+	if s := c.Stats(); s.Size != 1 {
+		t.Errorf("Stats().Size = %d, want 1", s.Size)
+	}
+	if _, ok := c.Get("breed:b"); !ok {
+		t.Error("Get(breed:b) missed, want it kept")
+	}
+}
+
+func TestLRUStats(t *testing.T) {
+	c := NewLRU[string, int](1, 0)
+	if r := c.Stats().HitRatio(); r != 0 {
+		t.Errorf("HitRatio() without lookups = %v, want 0", r)
+	}
+	c.Add("a", 1)
+	c.Get("a")
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Add("b", 2)
+
+	want := Stats{Hits: 3, Misses: 1, Evictions: 1, Size: 1, Capacity: 1}
+	if s := c.Stats(); s != want {
+		t.Errorf("Stats() = %+v, want %+v", s, want)
+	}
+	if r := c.Stats().HitRatio(); r != 0.75 {
+		t.Errorf("HitRatio() = %v, want 0.75", r)
+	}
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is the default number of recent events kept for replay.
+const DefaultBufferSize = 256
+
+// subscriberBuffer is the number of events queued for a subscriber before it is dropped.
+const subscriberBuffer = 64
+
+// Event is a change published on a Bus.
+//
+// IDs are assigned by the bus, starting at 1 and increasing with every event,
+// so a subscriber can resume after the last event it has seen.
+// The Key, when set, identifies the event across retries and restarts, for deduplication.
+type Event struct {
+	ID      uint64      `json:"id"`
+	Key     string      `json:"key,omitempty"`
+	Type    string      `json:"type"`
+	Subject string      `json:"subject"`
+	Time    time.Time   `json:"time"`
+	Data    interface{} `json:"data"`
+}
+
+// Bus is an in-process publish/subscribe event bus keeping a bounded buffer of recent events.
+//
+// Publish never blocks: a subscriber which doesn't keep up has its channel closed
+// and is expected to resubscribe from the last event it has received.
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	buffer      []Event
+	size        int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBus returns a bus keeping the last size events for replay.
+func NewBus(size int) *Bus {
+	if size < 1 {
+		size = DefaultBufferSize
+	}
+	return &Bus{size: size, subscribers: map[*Subscription]struct{}{}}
+}
+
+// Publish assigns the event its ID and time, and sends it to every subscriber.
+// An event with the Key of a buffered event is a duplicate, it is dropped and the buffered event returned.
+func (b *Bus) Publish(e Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if e.Key != "" {
+		for _, buffered := range b.buffer {
+			if buffered.Key == e.Key {
+				return buffered
+			}
+		}
+	}
+
+	b.lastID++
+	e.ID = b.lastID
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b.buffer = append(b.buffer, e)
+	if len(b.buffer) > b.size {
+		b.buffer = b.buffer[len(b.buffer)-b.size:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.c <- e:
+		default:
+			b.unsubscribe(sub)
+		}
+	}
+	return e
+}
+
+// Subscription receives the events published after it was created.
+type Subscription struct {
+	bus *Bus
+	c   chan Event
+}
+
+// Events returns the channel the events are delivered on.
+// It is closed when the subscription is closed or falls behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.c
+}
+
+// Close stops the delivery of events to the subscription.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.unsubscribe(s)
+}
+
+// Subscribe returns a new subscription, along with the buffered events published after lastID.
+// complete is false when some of the events after lastID are no longer buffered.
+// A lastID of 0 replays nothing.
+func (b *Bus) Subscribe(lastID uint64) (sub *Subscription, replay []Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		replay, complete = b.since(lastID)
+	}
+	sub = &Subscription{bus: b, c: make(chan Event, subscriberBuffer)}
+	if b.closed {
+		close(sub.c)
+		return sub, replay, complete
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub, replay, complete
+}
+
+// Closed reports whether the bus has been closed.
+func (b *Bus) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// Close closes every subscription, letting the streams built on them end, e.g. on server shutdown.
+// Events published afterwards are still buffered, but no one subscribes to them anymore.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subscribers {
+		b.unsubscribe(sub)
+	}
+}
+
+func (b *Bus) since(lastID uint64) ([]Event, bool) {
+	if lastID >= b.lastID {
+		return nil, lastID == b.lastID
+	}
+	var replay []Event
+	for _, e := range b.buffer {
+		if e.ID > lastID {
+			replay = append(replay, e)
+		}
+	}
+	return replay, len(replay) > 0 && replay[0].ID == lastID+1
+}
+
+func (b *Bus) unsubscribe(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.c)
+	}
+}
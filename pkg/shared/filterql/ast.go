@@ -0,0 +1,76 @@
+// Package filterql parses the filter expressions of the list endpoints, e.g.
+// `name~"^Bor" and createdAt>2023-01-01 and creationType in (custom)`.
+//
+// An expression is parsed into an AST, checked against the Fields a resource allows filtering on,
+// and then either compiled to a Tigris filter or evaluated in memory against the entities.
+package filterql
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalid is wrapped by the errors of the invalid expressions.
+var ErrInvalid = errors.New("invalid filter")
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	Eq    Op = "="
+	Ne    Op = "!="
+	Lt    Op = "<"
+	Lte   Op = "<="
+	Gt    Op = ">"
+	Gte   Op = ">="
+	Match Op = "~"
+	In    Op = "in"
+)
+
+// Node is a node of the AST of an expression: And, Or or Cond.
+type Node interface {
+	String() string
+}
+
+// And matches when all of its nodes match.
+type And []Node
+
+// Or matches when any of its nodes matches.
+type Or []Node
+
+// Cond compares a field to its values, the single value of the operators other than In.
+//
+// The values are the raw strings of the expression until the Cond is checked against its Field,
+// which converts them to the string, float64, time.Time or bool of the field's Type.
+type Cond struct {
+	Field  string
+	Op     Op
+	Values []interface{}
+	Type   Type
+
+	re *regexp.Regexp
+}
+
+func (n And) String() string { return join(n, " and ") }
+
+func (n Or) String() string { return "(" + join(n, " or ") + ")" }
+
+func (c Cond) String() string {
+	values := make([]string, len(c.Values))
+	for i, v := range c.Values {
+		values[i] = format(v)
+	}
+	if c.Op == In {
+		return c.Field + " in (" + strings.Join(values, ", ") + ")"
+	}
+	return c.Field + string(c.Op) + strings.Join(values, "")
+}
+
+func join(nodes []Node, sep string) string {
+	s := make([]string, len(nodes))
+	for i, n := range nodes {
+		s[i] = n.String()
+	}
+	return strings.Join(s, sep)
+}
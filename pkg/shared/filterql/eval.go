@@ -0,0 +1,198 @@
+package filterql
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Filter returns the items matching the checked AST, for the repositories that can't compile it.
+func Filter[T any](n Node, items []T) ([]T, error) {
+	matched := []T{}
+	for _, item := range items {
+		ok, err := Eval(n, item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+	return matched, nil
+}
+
+// Eval reports whether the document, a struct or map, matches the checked AST.
+//
+// The fields are read by their JSON path, a missing field never matching. A condition on an array field matches
+// when any of its elements does.
+func Eval(n Node, doc interface{}) (bool, error) {
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return false, err
+		}
+	}
+	return eval(n, m)
+}
+
+func eval(n Node, doc map[string]interface{}) (bool, error) {
+	switch n := n.(type) {
+	case And:
+		for _, n := range n {
+			if ok, err := eval(n, doc); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case Or:
+		for _, n := range n {
+			if ok, err := eval(n, doc); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case Cond:
+		v, ok := lookup(doc, n.Field)
+		if !ok {
+			return false, nil
+		}
+		if values, ok := v.([]interface{}); ok {
+			for _, v := range values {
+				if ok, err := evalCond(n, v); err != nil || ok {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		return evalCond(n, v)
+	default:
+		return false, fmt.Errorf("%w: unexpected node %T", ErrInvalid, n)
+	}
+}
+
+// lookup returns the value of the dotted path in the document.
+func lookup(doc map[string]interface{}, path string) (interface{}, bool) {
+	var v interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+func evalCond(c Cond, raw interface{}) (bool, error) {
+	v, err := docValue(c.Type, raw)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid %q value %v: %v", ErrInvalid, c.Field, raw, err)
+	}
+	switch c.Op {
+	case Match:
+		s, ok := v.(string)
+		if !ok {
+			return false, nil
+		}
+		re := c.re
+		if re == nil {
+			if re, err = regexp.Compile(fmt.Sprint(c.Values[0])); err != nil {
+				return false, fmt.Errorf("%w: invalid regular expression for %q", ErrInvalid, c.Field)
+			}
+		}
+		return re.MatchString(s), nil
+	case In:
+		for _, want := range c.Values {
+			if cmp, ok := compare(v, want); ok && cmp == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	cmp, ok := compare(v, c.Values[0])
+	if !ok {
+		return false, nil
+	}
+	switch c.Op {
+	case Eq:
+		return cmp == 0, nil
+	case Ne:
+		return cmp != 0, nil
+	case Lt:
+		return cmp < 0, nil
+	case Lte:
+		return cmp <= 0, nil
+	case Gt:
+		return cmp > 0, nil
+	case Gte:
+		return cmp >= 0, nil
+	default:
+		return false, fmt.Errorf("%w: unknown operator %q", ErrInvalid, c.Op)
+	}
+}
+
+// docValue converts the JSON value of the document to the type of the field.
+func docValue(t Type, raw interface{}) (interface{}, error) {
+	if t == Time {
+		s, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a timestamp")
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	}
+	return raw, nil
+}
+
+// compare compares the values of the same type, reporting false for values of different types.
+// The booleans aren't ordered, unequal ones comparing as 1.
+func compare(a, b interface{}) (int, bool) {
+	switch a := a.(type) {
+	case string:
+		b, ok := b.(string)
+		return strings.Compare(a, b), ok
+	case float64:
+		b, ok := b.(float64)
+		switch {
+		case !ok:
+			return 0, false
+		case a < b:
+			return -1, true
+		case a > b:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case time.Time:
+		b, ok := b.(time.Time)
+		switch {
+		case !ok:
+			return 0, false
+		case a.Before(b):
+			return -1, true
+		case a.After(b):
+			return 1, true
+		default:
+			return 0, true
+		}
+	case bool:
+		b, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		if a == b {
+			return 0, true
+		}
+		return 1, true
+	default:
+		return 0, false
+	}
+}
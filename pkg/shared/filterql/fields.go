@@ -0,0 +1,154 @@
+package filterql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the type of a field, which its values are converted to.
+type Type int
+
+const (
+	String Type = iota
+	Number
+	Time
+	Bool
+)
+
+// Field is a field that can be filtered on, with its operators.
+type Field struct {
+	Type Type
+	Ops  []Op
+}
+
+// Fields are the fields a resource allows filtering on, by their JSON path, e.g. "weight.min".
+type Fields map[string]Field
+
+// The operators of the usual fields.
+var (
+	EqualityOps   = []Op{Eq, Ne, In}
+	ComparisonOps = []Op{Eq, Ne, Lt, Lte, Gt, Gte}
+	TextOps       = []Op{Eq, Ne, Lt, Lte, Gt, Gte, In, Match}
+)
+
+// Check checks the fields and operators of the AST against the whitelist, and returns the AST
+// with the values converted to the types of their fields.
+//
+// The times are either RFC 3339 timestamps or dates, in UTC.
+func (fs Fields) Check(n Node) (Node, error) {
+	switch n := n.(type) {
+	case And:
+		nodes, err := fs.checkAll(n)
+		return And(nodes), err
+	case Or:
+		nodes, err := fs.checkAll(n)
+		return Or(nodes), err
+	case Cond:
+		return fs.checkCond(n)
+	default:
+		return nil, fmt.Errorf("%w: unexpected node %T", ErrInvalid, n)
+	}
+}
+
+func (fs Fields) checkAll(nodes []Node) ([]Node, error) {
+	checked := make([]Node, len(nodes))
+	for i, n := range nodes {
+		var err error
+		if checked[i], err = fs.Check(n); err != nil {
+			return nil, err
+		}
+	}
+	return checked, nil
+}
+
+func (fs Fields) checkCond(c Cond) (Node, error) {
+	f, ok := fs[c.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %q, must be one of %s", ErrInvalid, c.Field, strings.Join(fs.names(), ", "))
+	}
+	if !f.allows(c.Op) {
+		return nil, fmt.Errorf("%w: operator %q isn't supported on %q", ErrInvalid, c.Op, c.Field)
+	}
+
+	checked := Cond{Field: c.Field, Op: c.Op, Type: f.Type, Values: make([]interface{}, len(c.Values))}
+	for i, v := range c.Values {
+		s := fmt.Sprint(v)
+		if c.Op == Match {
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid regular expression %q for %q", ErrInvalid, s, c.Field)
+			}
+			checked.Values[i], checked.re = s, re
+			continue
+		}
+		var err error
+		if checked.Values[i], err = convert(f.Type, s); err != nil {
+			return nil, fmt.Errorf("%w: invalid value %q for %q: %v", ErrInvalid, s, c.Field, err)
+		}
+	}
+	return checked, nil
+}
+
+func (f Field) allows(op Op) bool {
+	for _, o := range f.Ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (fs Fields) names() []string {
+	names := make([]string, 0, len(fs))
+	for name := range fs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// convert converts the raw value to the type.
+func convert(t Type, s string) (interface{}, error) {
+	switch t {
+	case Number:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return f, nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t.UTC(), nil
+		}
+		if t, err := time.Parse("2006-01-02", s); err == nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("must be a date or an RFC 3339 timestamp")
+	case Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+	default:
+		return s, nil
+	}
+}
+
+// format formats the value as in an expression, quoting the strings.
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
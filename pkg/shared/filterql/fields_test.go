@@ -0,0 +1,88 @@
+package filterql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testFields are the fields of the documents of the tests, see testDoc.
+var testFields = Fields{
+	"name":       {Type: String, Ops: TextOps},
+	"group":      {Type: String, Ops: EqualityOps},
+	"tags":       {Type: String, Ops: EqualityOps},
+	"weight.min": {Type: Number, Ops: ComparisonOps},
+	"createdAt":  {Type: Time, Ops: ComparisonOps},
+	"active":     {Type: Bool, Ops: EqualityOps},
+}
+
+func check(t *testing.T, expr string) (Node, error) {
+	t.Helper()
+	n, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q) = %v", expr, err)
+	}
+	return testFields.Check(n)
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		expr string
+		want Node
+	}{
+		{expr: `name=Borzoi`, want: Cond{Field: "name", Op: Eq, Type: String, Values: []interface{}{"Borzoi"}}},
+		{expr: `weight.min>=10.5`, want: Cond{Field: "weight.min", Op: Gte, Type: Number, Values: []interface{}{10.5}}},
+		{expr: `active=true`, want: Cond{Field: "active", Op: Eq, Type: Bool, Values: []interface{}{true}}},
+		{
+			expr: `createdAt>2023-01-05`,
+			want: Cond{Field: "createdAt", Op: Gt, Type: Time, Values: []interface{}{time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			expr: `createdAt<2023-01-05T10:00:00+01:00`,
+			want: Cond{Field: "createdAt", Op: Lt, Type: Time, Values: []interface{}{time.Date(2023, 1, 5, 9, 0, 0, 0, time.UTC)}},
+		},
+		{
+			expr: `group in (toy, hound) or weight.min<5`,
+			want: Or{
+				Cond{Field: "group", Op: In, Type: String, Values: []interface{}{"toy", "hound"}},
+				Cond{Field: "weight.min", Op: Lt, Type: Number, Values: []interface{}{5.0}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := check(t, tt.expr)
+			if err != nil {
+				t.Fatalf("Check() = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	tests := []string{
+		`color=black`,
+		`weight=10`,
+		`group~"^to"`,
+		`group<toy`,
+		`weight.min in (10, 20)`,
+		`weight.min>ten`,
+		`createdAt>yesterday`,
+		`createdAt>2023-13-01`,
+		`active=maybe`,
+		`name~"(unclosed"`,
+		`name=Borzoi and weight.min>heavy`,
+		`name=Borzoi or color=black`,
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := check(t, expr); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Check() = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package filterql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parse parses the expression into its AST.
+//
+// An expression is made of conditions, `<field><op><value>` or `<field> in (<value>, ...)`, combined with the
+// `and` and `or` keywords and grouped with parentheses, `and` taking precedence over `or`. The operators are
+// =, !=, <, <=, >, >= and ~, matching a regular expression. The values are either bare words, e.g. numbers and
+// dates, or double-quoted strings, escaping their quotes and backslashes with a backslash.
+func Parse(s string) (Node, error) {
+	p := &parser{s: s}
+	p.next()
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return n, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokInvalid
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+type parser struct {
+	s   string
+	pos int
+	tok token
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalid, fmt.Sprintf(format, args...), p.tok.pos+1)
+}
+
+// keyword reports whether the token is the case-insensitive keyword.
+func (p *parser) keyword(kw string) bool {
+	return p.tok.kind == tokWord && strings.EqualFold(p.tok.text, kw)
+}
+
+func (p *parser) or() (Node, error) {
+	n, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{n}
+	for p.keyword("or") {
+		p.next()
+		n, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return Or(nodes), nil
+}
+
+func (p *parser) and() (Node, error) {
+	n, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{n}
+	for p.keyword("and") {
+		p.next()
+		n, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return And(nodes), nil
+}
+
+func (p *parser) operand() (Node, error) {
+	if p.tok.kind == tokLParen {
+		p.next()
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("expected \")\", got %s", p.tok)
+		}
+		p.next()
+		return n, nil
+	}
+	return p.cond()
+}
+
+func (p *parser) cond() (Node, error) {
+	if p.tok.kind != tokWord || p.keyword("and") || p.keyword("or") || p.keyword("in") {
+		return nil, p.errorf("expected a field, got %s", p.tok)
+	}
+	c := Cond{Field: p.tok.text}
+	p.next()
+
+	switch {
+	case p.tok.kind == tokOp:
+		c.Op = Op(p.tok.text)
+		p.next()
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		c.Values = []interface{}{v}
+	case p.keyword("in"):
+		c.Op = In
+		p.next()
+		if p.tok.kind != tokLParen {
+			return nil, p.errorf("expected \"(\", got %s", p.tok)
+		}
+		p.next()
+		for {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			c.Values = append(c.Values, v)
+			if p.tok.kind != tokComma {
+				break
+			}
+			p.next()
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("expected \",\" or \")\", got %s", p.tok)
+		}
+		p.next()
+	default:
+		return nil, p.errorf("expected an operator, got %s", p.tok)
+	}
+	return c, nil
+}
+
+func (p *parser) value() (string, error) {
+	if p.tok.kind != tokWord && p.tok.kind != tokString {
+		return "", p.errorf("expected a value, got %s", p.tok)
+	}
+	v := p.tok.text
+	p.next()
+	return v, nil
+}
+
+// next scans the next token.
+func (p *parser) next() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.s) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	switch c := p.s[p.pos]; {
+	case c == '(':
+		p.pos++
+		p.tok = token{kind: tokLParen, text: "(", pos: start}
+	case c == ')':
+		p.pos++
+		p.tok = token{kind: tokRParen, text: ")", pos: start}
+	case c == ',':
+		p.pos++
+		p.tok = token{kind: tokComma, text: ",", pos: start}
+	case c == '"':
+		p.tok = p.quoted()
+	case strings.IndexByte("=!<>~", c) >= 0:
+		for _, op := range []Op{Ne, Lte, Gte, Eq, Lt, Gt, Match} {
+			if strings.HasPrefix(p.s[p.pos:], string(op)) {
+				p.pos += len(op)
+				p.tok = token{kind: tokOp, text: string(op), pos: start}
+				return
+			}
+		}
+		p.pos++
+		p.tok = token{kind: tokInvalid, text: string(c), pos: start}
+	default:
+		for p.pos < len(p.s) && !unicode.IsSpace(rune(p.s[p.pos])) && strings.IndexByte("()\",=!<>~", p.s[p.pos]) < 0 {
+			p.pos++
+		}
+		p.tok = token{kind: tokWord, text: p.s[start:p.pos], pos: start}
+	}
+}
+
+// quoted scans a double-quoted string, unescaping its \" and \\ escapes, the other backslashes being
+// kept for the regular expressions.
+func (p *parser) quoted() token {
+	start := p.pos
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		switch c := p.s[p.pos]; c {
+		case '"':
+			p.pos++
+			return token{kind: tokString, text: b.String(), pos: start}
+		case '\\':
+			if p.pos+1 < len(p.s) && (p.s[p.pos+1] == '"' || p.s[p.pos+1] == '\\') {
+				p.pos++
+				c = p.s[p.pos]
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return token{kind: tokInvalid, text: p.s[start:], pos: start}
+}
@@ -0,0 +1,87 @@
+package filterql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func cond(field string, op Op, values ...interface{}) Cond {
+	return Cond{Field: field, Op: op, Values: values}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		want Node
+	}{
+		{expr: `name=Borzoi`, want: cond("name", Eq, "Borzoi")},
+		{expr: `  weight.min >= 10 `, want: cond("weight.min", Gte, "10")},
+		{expr: `a!=1`, want: cond("a", Ne, "1")},
+		{expr: `a<=1`, want: cond("a", Lte, "1")},
+		{expr: `a<1`, want: cond("a", Lt, "1")},
+		{expr: `a>1`, want: cond("a", Gt, "1")},
+		{expr: `createdAt>2023-01-01T10:00:00+01:00`, want: cond("createdAt", Gt, "2023-01-01T10:00:00+01:00")},
+		{expr: `group in (toy, hound)`, want: cond("group", In, "toy", "hound")},
+		{expr: `group IN (toy)`, want: cond("group", In, "toy")},
+
+		// and takes precedence over or, the parentheses group
+		{expr: `a=1 and b=2`, want: And{cond("a", Eq, "1"), cond("b", Eq, "2")}},
+		{expr: `a=1 or b=2 and c=3`, want: Or{cond("a", Eq, "1"), And{cond("b", Eq, "2"), cond("c", Eq, "3")}}},
+		{expr: `a=1 and b=2 or c=3`, want: Or{And{cond("a", Eq, "1"), cond("b", Eq, "2")}, cond("c", Eq, "3")}},
+		{expr: `(a=1 or b=2) and c=3`, want: And{Or{cond("a", Eq, "1"), cond("b", Eq, "2")}, cond("c", Eq, "3")}},
+		{expr: `a=1 AND (b=2 OR c=3)`, want: And{cond("a", Eq, "1"), Or{cond("b", Eq, "2"), cond("c", Eq, "3")}}},
+		{expr: `((a=1))`, want: cond("a", Eq, "1")},
+
+		// Quoting and escapes
+		{expr: `name="Bernese Mountain Dog"`, want: cond("name", Eq, "Bernese Mountain Dog")},
+		{expr: `name="and or in ( ) , = ~"`, want: cond("name", Eq, "and or in ( ) , = ~")},
+		{expr: `name="say \"woof\""`, want: cond("name", Eq, `say "woof"`)},
+		{expr: `name="back\\slash"`, want: cond("name", Eq, `back\slash`)},
+		{expr: `name~"^St\. Bernard"`, want: cond("name", Match, `^St\. Bernard`)},
+		{expr: `name=""`, want: cond("name", Eq, "")},
+		{expr: `name in ("a, b", c)`, want: cond("name", In, "a, b", "c")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`name`,
+		`name=`,
+		`name==Borzoi`,
+		`name=~Borzoi`,
+		`name!Borzoi`,
+		`=Borzoi`,
+		`and=1`,
+		`a=1 and`,
+		`a=1 or or b=2`,
+		`a=1 b=2`,
+		`(a=1`,
+		`a=1)`,
+		`a in toy`,
+		`a in ()`,
+		`a in (toy,)`,
+		`a in (toy hound)`,
+		`name="unterminated`,
+		`name="escaped quote\"`,
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Parse(expr); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Parse() = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package filterql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/filter"
+)
+
+// Compile compiles the checked AST to a Tigris filter.
+//
+// Tigris doesn't support the != and ~ operators: != is compiled to a < or > comparison, the opposite value for
+// the booleans, and ~ to a range for the prefixes, e.g. `name~"^Bor"` to `name>="Bor" and name<"Bos"`. The other
+// regular expressions can only be evaluated in memory, see Eval and Filter.
+func Compile(n Node) (filter.Filter, error) {
+	switch n := n.(type) {
+	case And:
+		ops, err := compileAll(n)
+		if err != nil {
+			return nil, err
+		}
+		return filter.And(ops...), nil
+	case Or:
+		ops, err := compileAll(n)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Or(ops...), nil
+	case Cond:
+		return compileCond(n)
+	default:
+		return nil, fmt.Errorf("%w: unexpected node %T", ErrInvalid, n)
+	}
+}
+
+func compileAll(nodes []Node) ([]filter.Expr, error) {
+	ops := make([]filter.Expr, len(nodes))
+	for i, n := range nodes {
+		var err error
+		if ops[i], err = Compile(n); err != nil {
+			return nil, err
+		}
+	}
+	return ops, nil
+}
+
+func compileCond(c Cond) (filter.Filter, error) {
+	if len(c.Values) == 0 {
+		return nil, fmt.Errorf("%w: %q has no value", ErrInvalid, c.Field)
+	}
+	v := c.Values[0]
+	switch c.Op {
+	case Eq:
+		return eq(c.Field, v)
+	case Ne:
+		if b, ok := v.(bool); ok {
+			return filter.Eq(c.Field, !b), nil
+		}
+		return filter.Or(filter.Lt(c.Field, v), filter.Gt(c.Field, v)), nil
+	case Lt:
+		return filter.Lt(c.Field, v), nil
+	case Lte:
+		return filter.Lte(c.Field, v), nil
+	case Gt:
+		return filter.Gt(c.Field, v), nil
+	case Gte:
+		return filter.Gte(c.Field, v), nil
+	case In:
+		ops := make([]filter.Expr, len(c.Values))
+		for i, v := range c.Values {
+			var err error
+			if ops[i], err = eq(c.Field, v); err != nil {
+				return nil, err
+			}
+		}
+		if len(ops) == 1 {
+			return ops[0], nil
+		}
+		return filter.Or(ops...), nil
+	case Match:
+		prefix, ok := literalPrefix(fmt.Sprint(v))
+		if !ok {
+			return nil, fmt.Errorf("%w: %q only supports prefix patterns, e.g. \"^Bor\"", ErrInvalid, c.Field)
+		}
+		if end, ok := prefixEnd(prefix); ok {
+			return filter.And(filter.Gte(c.Field, prefix), filter.Lt(c.Field, end)), nil
+		}
+		return filter.Gte(c.Field, prefix), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalid, c.Op)
+	}
+}
+
+func eq(field string, v interface{}) (filter.Expr, error) {
+	switch v := v.(type) {
+	case string:
+		return filter.Eq(field, v), nil
+	case float64:
+		return filter.Eq(field, v), nil
+	case time.Time:
+		return filter.Eq(field, v), nil
+	case bool:
+		return filter.Eq(field, v), nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected value %v for %q", ErrInvalid, v, field)
+	}
+}
+
+// literalPrefix returns the literal of an anchored pattern without any other metacharacter, e.g. "Bor" for "^Bor".
+func literalPrefix(pattern string) (string, bool) {
+	if !strings.HasPrefix(pattern, "^") {
+		return "", false
+	}
+	prefix := pattern[1:]
+	if prefix == "" || regexp.QuoteMeta(prefix) != prefix {
+		return "", false
+	}
+	return prefix, true
+}
+
+// prefixEnd returns the smallest string greater than all the strings with the prefix, if any.
+func prefixEnd(prefix string) (string, bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,307 @@
+package filterql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: `name=Borzoi`, want: `{"name":{"$eq":"Borzoi"}}`},
+		{expr: `weight.min<=10`, want: `{"weight.min":{"$lte":10}}`},
+		{expr: `createdAt>2023-01-05`, want: `{"createdAt":{"$gt":"2023-01-05T00:00:00Z"}}`},
+		{expr: `group in (toy)`, want: `{"group":{"$eq":"toy"}}`},
+		{expr: `group in (toy, hound)`, want: `{"$or":[{"group":{"$eq":"toy"}},{"group":{"$eq":"hound"}}]}`},
+		{
+			expr: `name=Borzoi or weight.min>5 and active=true`,
+			want: `{"$or":[{"name":{"$eq":"Borzoi"}},{"$and":[{"weight.min":{"$gt":5}},{"active":{"$eq":true}}]}]}`,
+		},
+
+		// != is rewritten to < or >, or the opposite boolean
+		{expr: `weight.min!=20`, want: `{"$or":[{"weight.min":{"$lt":20}},{"weight.min":{"$gt":20}}]}`},
+		{expr: `name!=Borzoi`, want: `{"$or":[{"name":{"$lt":"Borzoi"}},{"name":{"$gt":"Borzoi"}}]}`},
+		{expr: `active!=true`, want: `{"active":{"$eq":false}}`},
+
+		// ~ is compiled to the range of its prefix
+		{expr: `name~"^Bor"`, want: `{"$and":[{"name":{"$gte":"Bor"}},{"name":{"$lt":"Bos"}}]}`},
+		{expr: `name~"^Az"`, want: `{"$and":[{"name":{"$gte":"Az"}},{"name":{"$lt":"A{"}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			n, err := check(t, tt.expr)
+			if err != nil {
+				t.Fatalf("Check() = %v", err)
+			}
+			f, err := Compile(n)
+			if err != nil {
+				t.Fatalf("Compile() = %v", err)
+			}
+			got, err := json.Marshal(f)
+			if err != nil {
+				t.Fatalf("json.Marshal() = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Compile() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		`name~Bor`,
+		`name~"^Bor.*"`,
+		`name~"^(Bor|Akb)"`,
+		`name~"^"`,
+		`name~"zoi$"`,
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			n, err := check(t, expr)
+			if err != nil {
+				t.Fatalf("Check() = %v", err)
+			}
+			if _, err := Compile(n); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Compile() = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+		ok     bool
+	}{
+		{prefix: "Bor", want: "Bos", ok: true},
+		{prefix: "Az", want: "A{", ok: true},
+		{prefix: "a\xff", want: "b", ok: true},
+		{prefix: "\xff\xff", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := prefixEnd(tt.prefix)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("prefixEnd(%q) = %q, %v, want %q, %v", tt.prefix, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// testDoc is a document of the tests, with the fields of testFields.
+type testDoc struct {
+	Name   string   `json:"name"`
+	Group  string   `json:"group,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	Weight struct {
+		Min float64 `json:"min"`
+	} `json:"weight"`
+	CreatedAt time.Time `json:"createdAt"`
+	Active    bool      `json:"active"`
+}
+
+func testDocs() []testDoc {
+	docs := []testDoc{
+		{Name: "Akita", Group: "working", Tags: []string{"loyal", "calm"}, Active: true},
+		{Name: "Bor", Group: "hound", Active: false},
+		{Name: "Borzoi", Group: "hound", Tags: []string{"calm"}, Active: true},
+		{Name: "Bos", Group: "toy", Tags: []string{"playful"}, Active: true},
+		{Name: "borzoi", Tags: []string{"calm", "calm"}, Active: false},
+		{Name: "Chihuahua", Group: "toy", Active: true},
+		{Name: "St. Bernard", Group: "working", Tags: []string{"gentle", "calm"}, Active: false},
+	}
+	for i := range docs {
+		docs[i].Weight.Min = float64(5 * i)
+		docs[i].CreatedAt = time.Date(2023, 1, 1, 12*i, 0, 0, 0, time.UTC)
+	}
+	return docs
+}
+
+// TestEvalMatchesCompile checks that the expressions match the same documents in memory as in Tigris,
+// whose semantics are emulated over the compiled filter by tigrisMatch.
+func TestEvalMatchesCompile(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{expr: `name=Borzoi`, want: []string{"Borzoi"}},
+		{expr: `name!=Borzoi`, want: []string{"Akita", "Bor", "Bos", "borzoi", "Chihuahua", "St. Bernard"}},
+		{expr: `name~"^Bor"`, want: []string{"Bor", "Borzoi"}},
+		{expr: `name>=Bor and name<C`, want: []string{"Bor", "Borzoi", "Bos"}},
+		{expr: `name in (Akita, Bos, Poodle)`, want: []string{"Akita", "Bos"}},
+		{expr: `group=toy`, want: []string{"Bos", "Chihuahua"}},
+		{expr: `group!=toy`, want: []string{"Akita", "Bor", "Borzoi", "St. Bernard"}},
+		{expr: `tags=calm`, want: []string{"Akita", "Borzoi", "borzoi", "St. Bernard"}},
+		{expr: `tags!=calm`, want: []string{"Akita", "Bos", "St. Bernard"}},
+		{expr: `weight.min>=10 and weight.min<20`, want: []string{"Borzoi", "Bos"}},
+		{expr: `weight.min!=10`, want: []string{"Akita", "Bor", "Bos", "borzoi", "Chihuahua", "St. Bernard"}},
+		{expr: `createdAt>2023-01-02`, want: []string{"Bos", "borzoi", "Chihuahua", "St. Bernard"}},
+		{expr: `createdAt<=2023-01-01T12:00:00Z`, want: []string{"Akita", "Bor"}},
+		{expr: `createdAt!=2023-01-01T13:00:00+01:00`, want: []string{"Akita", "Borzoi", "Bos", "borzoi", "Chihuahua", "St. Bernard"}},
+		{expr: `active=false`, want: []string{"Bor", "borzoi", "St. Bernard"}},
+		{expr: `active!=true`, want: []string{"Bor", "borzoi", "St. Bernard"}},
+		{expr: `name~"^Bor" or group=toy and active=true`, want: []string{"Bor", "Borzoi", "Bos", "Chihuahua"}},
+		{expr: `(name~"^Bor" or group=toy) and active=true`, want: []string{"Borzoi", "Bos", "Chihuahua"}},
+	}
+	docs := testDocs()
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			n, err := check(t, tt.expr)
+			if err != nil {
+				t.Fatalf("Check() = %v", err)
+			}
+			f, err := Compile(n)
+			if err != nil {
+				t.Fatalf("Compile() = %v", err)
+			}
+			b, err := json.Marshal(f)
+			if err != nil {
+				t.Fatalf("json.Marshal() = %v", err)
+			}
+			var compiled map[string]interface{}
+			if err := json.Unmarshal(b, &compiled); err != nil {
+				t.Fatalf("json.Unmarshal() = %v", err)
+			}
+
+			matched, err := Filter(n, docs)
+			if err != nil {
+				t.Fatalf("Filter() = %v", err)
+			}
+			if got := names(matched); !equal(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+			var inTigris []testDoc
+			for _, doc := range docs {
+				ok, err := tigrisMatch(compiled, toMap(t, doc))
+				if err != nil {
+					t.Fatalf("tigrisMatch() = %v", err)
+				}
+				if ok {
+					inTigris = append(inTigris, doc)
+				}
+			}
+			if got := names(inTigris); !equal(got, tt.want) {
+				t.Errorf("Compile() matches %v in Tigris, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// tigrisMatch reports whether the document matches the Tigris filter, in its JSON form: the conditions of
+// an object all match, and those on an array field match any of its elements.
+func tigrisMatch(f map[string]interface{}, doc map[string]interface{}) (bool, error) {
+	for key, v := range f {
+		switch key {
+		case "$and", "$or":
+			matched := false
+			for _, sub := range v.([]interface{}) {
+				ok, err := tigrisMatch(sub.(map[string]interface{}), doc)
+				if err != nil {
+					return false, err
+				}
+				if ok && key == "$or" {
+					matched = true
+				}
+				if !ok && key == "$and" {
+					return false, nil
+				}
+			}
+			if key == "$or" && !matched {
+				return false, nil
+			}
+		default:
+			typ := testFields[key].Type
+			raw, ok := lookup(doc, key)
+			if !ok {
+				return false, nil
+			}
+			values, isArray := raw.([]interface{})
+			if !isArray {
+				values = []interface{}{raw}
+			}
+			matched := false
+			for _, raw := range values {
+				ok, err := tigrisCompare(typ, v.(map[string]interface{}), raw)
+				if err != nil {
+					return false, err
+				}
+				matched = matched || ok
+			}
+			if !matched {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func tigrisCompare(typ Type, comparison map[string]interface{}, raw interface{}) (bool, error) {
+	v, err := docValue(typ, raw)
+	if err != nil {
+		return false, err
+	}
+	for op, want := range comparison {
+		if want, err = docValue(typ, want); err != nil {
+			return false, err
+		}
+		cmp, ok := compare(v, want)
+		if !ok {
+			return false, nil
+		}
+		switch op {
+		case "$eq":
+			ok = cmp == 0
+		case "$lt":
+			ok = cmp < 0
+		case "$lte":
+			ok = cmp <= 0
+		case "$gt":
+			ok = cmp > 0
+		case "$gte":
+			ok = cmp >= 0
+		default:
+			return false, errors.New("unsupported Tigris operator " + op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func toMap(t *testing.T, doc testDoc) map[string]interface{} {
+	t.Helper()
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	return m
+}
+
+func names(docs []testDoc) []string {
+	names := []string{}
+	for _, doc := range docs {
+		names = append(names, doc.Name)
+	}
+	return names
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
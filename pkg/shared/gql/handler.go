@@ -0,0 +1,106 @@
+package gql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// maxRequestSize is the maximum size of a GraphQL request body.
+const maxRequestSize = 1 << 20
+
+type request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Handler serves GraphQL queries against the schema, over POST with a JSON body
+// or over GET with `query`, `variables` and `operationName` query params.
+//
+// Queries exceeding the limits are rejected before being executed. Mutations are only accepted over POST.
+func Handler(schema graphql.Schema, limits Limits) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		switch r.Method {
+		case http.MethodGet:
+			q := r.URL.Query()
+			req.Query = q.Get("query")
+			req.OperationName = q.Get("operationName")
+			if v := q.Get("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+					writeErrors(w, http.StatusBadRequest, "invalid variables")
+					return
+				}
+			}
+		case http.MethodPost:
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+				writeErrors(w, http.StatusBadRequest, "invalid request body")
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeErrors(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if req.Query == "" {
+			writeErrors(w, http.StatusBadRequest, "missing query")
+			return
+		}
+
+		doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+		if err != nil {
+			writeResult(w, http.StatusBadRequest, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+			return
+		}
+		if err := limits.Check(doc, req.OperationName, req.Variables); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if r.Method == http.MethodGet && hasMutation(doc, req.OperationName) {
+			w.Header().Set("Allow", "POST")
+			writeErrors(w, http.StatusMethodNotAllowed, "mutations must be sent over POST")
+			return
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        r.Context(),
+		})
+		writeResult(w, http.StatusOK, result)
+	}
+}
+
+// hasMutation reports whether the operation to execute is a mutation.
+func hasMutation(doc *ast.Document, operationName string) bool {
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (op.Name == nil || op.Name.Value != operationName) {
+			continue
+		}
+		if op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+	return false
+}
+
+func writeErrors(w http.ResponseWriter, status int, message string) {
+	writeResult(w, status, &graphql.Result{Errors: []gqlerrors.FormattedError{{Message: message}}})
+}
+
+func writeResult(w http.ResponseWriter, status int, result *graphql.Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,177 @@
+package gql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// DefaultMaxDepth is the default maximum nesting of selections in a query.
+	DefaultMaxDepth = 8
+	// DefaultMaxComplexity is the default maximum complexity score of a query.
+	DefaultMaxComplexity = 1000
+	// DefaultListSize is the number of items assumed for a paginated field without a limit.
+	DefaultListSize = 20
+	// UnpaginatedListSize is the number of items assumed for a field with `paginate: false`.
+	UnpaginatedListSize = 500
+)
+
+// Limits bounds the depth and complexity of the queries a schema accepts.
+//
+// Every field costs 1. Fields taking a `limit` argument, either directly or in a
+// `page` input, multiply the cost of their selections by that limit.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// DefaultLimits returns the limits used when none are configured.
+func DefaultLimits() Limits {
+	return Limits{MaxDepth: DefaultMaxDepth, MaxComplexity: DefaultMaxComplexity}
+}
+
+// Check returns an error if an operation of the document exceeds the limits.
+func (l Limits) Check(doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok && f.Name != nil {
+			fragments[f.Name.Value] = f
+		}
+	}
+
+	a := analyzer{fragments: fragments, variables: variables}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (op.Name == nil || op.Name.Value != operationName) {
+			continue
+		}
+		depth, complexity, err := a.selectionSet(op.SelectionSet, 0, map[string]bool{})
+		if err != nil {
+			return err
+		}
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return fmt.Errorf("query depth %d exceeds the maximum of %d", depth, l.MaxDepth)
+		}
+		if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the maximum of %d", complexity, l.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+type analyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selectionSet returns the depth and complexity of a selection set nested at depth.
+// visiting holds the fragments being expanded, to stop on fragment cycles.
+func (a analyzer) selectionSet(set *ast.SelectionSet, depth int, visiting map[string]bool) (int, int, error) {
+	if set == nil {
+		return depth, 0, nil
+	}
+	maxDepth, complexity := depth, 0
+	for _, sel := range set.Selections {
+		var (
+			d, c int
+			err  error
+		)
+		switch s := sel.(type) {
+		case *ast.Field:
+			d, c, err = a.selectionSet(s.SelectionSet, depth+1, visiting)
+			c = 1 + c*a.multiplier(s)
+		case *ast.InlineFragment:
+			d, c, err = a.selectionSet(s.SelectionSet, depth, visiting)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			f, ok := a.fragments[name]
+			if !ok {
+				return 0, 0, fmt.Errorf("unknown fragment %q", name)
+			}
+			if visiting[name] {
+				return 0, 0, fmt.Errorf("fragment %q spreads itself", name)
+			}
+			visiting[name] = true
+			d, c, err = a.selectionSet(f.SelectionSet, depth, visiting)
+			delete(visiting, name)
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		if d > maxDepth {
+			maxDepth = d
+		}
+		complexity += c
+	}
+	return maxDepth, complexity, nil
+}
+
+// multiplier returns the number of items a field is expected to return.
+func (a analyzer) multiplier(f *ast.Field) int {
+	for _, arg := range f.Arguments {
+		switch arg.Name.Value {
+		case "limit":
+			if n, ok := a.int(arg.Value); ok {
+				return n
+			}
+			return DefaultListSize
+		case "page":
+			obj, ok := a.value(arg.Value).(map[string]interface{})
+			if !ok {
+				return DefaultListSize
+			}
+			if paginate, ok := obj["paginate"].(bool); ok && !paginate {
+				return UnpaginatedListSize
+			}
+			if n, ok := toInt(obj["limit"]); ok {
+				return n
+			}
+			return DefaultListSize
+		}
+	}
+	return 1
+}
+
+func (a analyzer) int(v ast.Value) (int, bool) {
+	return toInt(a.value(v))
+}
+
+// value resolves an AST value, substituting variables, into a plain Go value.
+func (a analyzer) value(v ast.Value) interface{} {
+	switch v := v.(type) {
+	case *ast.Variable:
+		return a.variables[v.Name.Value]
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		if err != nil {
+			return nil
+		}
+		return n
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.ObjectValue:
+		obj := map[string]interface{}{}
+		for _, f := range v.Fields {
+			obj[f.Name.Value] = a.value(f.Value)
+		}
+		return obj
+	default:
+		return nil
+	}
+}
+
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, n > 0
+	case float64:
+		return int(n), n > 0
+	default:
+		return 0, false
+	}
+}
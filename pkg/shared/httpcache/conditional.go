@@ -0,0 +1,80 @@
+package httpcache
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WeakETag returns a weak entity tag hashing the parts, e.g. the query and the version of the data it reads.
+func WeakETag(parts ...interface{}) string {
+	h := fnv.New64a()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%v\x00", p)
+	}
+	return fmt.Sprintf(`W/"%x"`, h.Sum64())
+}
+
+// NotModifiedSince reports whether a request with If-Modified-Since, and without If-None-Match,
+// can be answered with a 304 for data last modified at the time. It is meant to skip reading
+// the data when the Last-Modified validator is enough.
+func NotModifiedSince(r *http.Request, lastModified time.Time) bool {
+	if r.Header.Get("If-None-Match") != "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// HTTP dates have a one second precision
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// CheckNotModified sets the ETag and Last-Modified validators of the response, and writes
+// a 304 Not Modified when the request's If-None-Match or If-Modified-Since matches them.
+// It returns true when the 304 was written, in which case no body should follow.
+//
+// As per RFC 7232, If-Modified-Since is ignored when If-None-Match is present.
+func CheckNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	notModified := false
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		notModified = etag != "" && matchesETag(inm, etag)
+	} else {
+		notModified = NotModifiedSince(r, lastModified)
+	}
+	if notModified {
+		WriteNotModified(w)
+	}
+	return notModified
+}
+
+// WriteNotModified writes a 304 Not Modified, without the headers describing a body.
+func WriteNotModified(w http.ResponseWriter) {
+	h := w.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// matchesETag compares the If-None-Match list with the entity tag, using the weak comparison.
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package httpcache
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Policies maps route path templates, e.g. `/breeds/{id}`, to their Cache-Control header value.
+type Policies map[string]string
+
+// ParsePolicies parses policies written as `<path template>=<Cache-Control>` pairs separated by semicolons,
+// e.g. `/breeds=public, max-age=60;/breeds/{id}=public, max-age=300`.
+func ParsePolicies(s string) (Policies, error) {
+	policies := Policies{}
+	for _, pair := range strings.Split(s, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		route, policy, ok := strings.Cut(pair, "=")
+		if !ok || !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("invalid cache policy %q, expected <path template>=<Cache-Control>", pair)
+		}
+		policies[strings.TrimSpace(route)] = strings.TrimSpace(policy)
+	}
+	return policies, nil
+}
+
+// Middleware sets the Cache-Control header of the successful GET and HEAD responses of the routes having a policy.
+// Handlers setting their own Cache-Control header, and error responses, are left alone.
+func (p Policies) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		tpl, err := route.GetPathTemplate()
+		policy, ok := p[tpl]
+		if err != nil || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&policyWriter{ResponseWriter: w, policy: policy}, r)
+	})
+}
+
+// policyWriter sets the Cache-Control header when the response status is written.
+type policyWriter struct {
+	http.ResponseWriter
+	policy      string
+	wroteHeader bool
+}
+
+func (w *policyWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		h := w.Header()
+		if h.Get("Cache-Control") == "" && (status < 300 || status == http.StatusNotModified) {
+			h.Set("Cache-Control", w.policy)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *policyWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush lets the streaming handlers, e.g. Server-Sent Events, flush through the writer.
+func (w *policyWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the WebSocket handlers take over the connection through the writer.
+func (w *policyWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer doesn't support hijacking")
+	}
+	return h.Hijack()
+}
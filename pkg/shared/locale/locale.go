@@ -0,0 +1,72 @@
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseAcceptLanguage returns the language tags of an Accept-Language header,
+// most preferred first. Wildcards and tags with a zero quality are dropped.
+//
+// e.g. "de-CH, fr;q=0.8, en;q=0.9" returns ["de-CH", "en", "fr"]
+func ParseAcceptLanguage(header string) []string {
+	type tag struct {
+		name    string
+		quality float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		name, q, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.TrimSpace(name)
+		if name == "" || name == "*" {
+			continue
+		}
+		quality := 1.0
+		if q = strings.TrimSpace(q); strings.HasPrefix(q, "q=") {
+			f, err := strconv.ParseFloat(strings.TrimPrefix(q, "q="), 64)
+			if err != nil {
+				continue
+			}
+			quality = f
+		}
+		if quality <= 0 {
+			continue
+		}
+		tags = append(tags, tag{name: name, quality: quality})
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.name)
+	}
+	return names
+}
+
+// Match returns the value for the first language tag found in the values,
+// falling back from a regional tag to its base language (e.g. "de-CH" to "de").
+// Keys are compared case-insensitively.
+func Match(tags []string, values map[string]string) (string, bool) {
+	if len(values) == 0 {
+		return "", false
+	}
+	lower := make(map[string]string, len(values))
+	for k, v := range values {
+		lower[strings.ToLower(k)] = v
+	}
+	for _, t := range tags {
+		t = strings.ToLower(t)
+		if v, ok := lower[t]; ok {
+			return v, true
+		}
+		if base, _, ok := strings.Cut(t, "-"); ok {
+			if v, ok := lower[base]; ok {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
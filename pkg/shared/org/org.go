@@ -0,0 +1,212 @@
+// Package org resolves the organization a request is made for, and carries its ID in the request's context.
+package org
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Header is the request header naming the organization, when it isn't resolved from the client's token.
+	Header = "X-Org-ID"
+	// APIKeyHeader is the request header carrying the client's API key, an alternative to a bearer token.
+	APIKeyHeader = "X-API-Key"
+)
+
+var (
+	// ErrInvalidID is returned for the organization IDs which aren't 1 to 64 letters, digits, dashes or underscores.
+	ErrInvalidID = errors.New("invalid organization ID")
+	// ErrMismatch is returned when the X-Org-ID header names another organization than the client's token.
+	ErrMismatch = errors.New("organization doesn't match the token")
+	// ErrUnverified is returned when the X-Org-ID header is sent without an organization token, and isn't trusted.
+	ErrUnverified = errors.New("an organization token is required to name the organization")
+)
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the organization ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the organization ID carried by the context, empty when the request isn't made for an organization.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// ParseTokens parses the organizations of the client tokens, e.g. `token1=acme;token2=globex`.
+func ParseTokens(s string) (map[string]string, error) {
+	tokens := map[string]string{}
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid organization token %q, expected token=organization", entry)
+		}
+		token, id := entry[:i], entry[i+1:]
+		if !validID.MatchString(id) {
+			return nil, fmt.Errorf("%w %q", ErrInvalidID, id)
+		}
+		tokens[token] = id
+	}
+	return tokens, nil
+}
+
+// Option configures the Resolver.
+type Option func(*Resolver)
+
+// WithTrustedHeader sets whether the X-Org-ID header is trusted without an organization token,
+// e.g. behind a gateway authenticating the clients.
+func WithTrustedHeader(trusted bool) Option {
+	return func(res *Resolver) {
+		res.trustHeader = trusted
+	}
+}
+
+// Resolver resolves the organization of the requests.
+type Resolver struct {
+	tokens      map[string]string
+	trustHeader bool
+}
+
+// NewResolver returns a Resolver mapping the client tokens to their organization.
+func NewResolver(tokens map[string]string, opts ...Option) *Resolver {
+	res := &Resolver{tokens: tokens}
+	for _, opt := range opts {
+		opt(res)
+	}
+	return res
+}
+
+// Resolve returns the organization of the client's bearer token or API key, or else the one named by the header
+// when it's trusted. It returns an empty ID when the request isn't made for an organization.
+func (res *Resolver) Resolve(token, header string) (string, error) {
+	if id, ok := res.tokens[token]; ok && token != "" {
+		if header != "" && header != id {
+			return "", ErrMismatch
+		}
+		return id, nil
+	}
+	if header == "" {
+		return "", nil
+	}
+	if !res.trustHeader {
+		return "", ErrUnverified
+	}
+	if !validID.MatchString(header) {
+		return "", ErrInvalidID
+	}
+	return header, nil
+}
+
+// Middleware carries the organization of the requests in their context, rejecting the requests with an invalid
+// X-Org-ID header with a 422, the ones naming an organization without its token with a 401, and the ones naming
+// another organization than their token with a 403.
+func (res *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The responses depend on the organization, so the shared caches mustn't serve them to the others
+		w.Header().Add("Vary", strings.Join([]string{"Authorization", APIKeyHeader, Header}, ", "))
+		token := r.Header.Get(APIKeyHeader)
+		if v := r.Header.Get("Authorization"); strings.HasPrefix(v, "Bearer ") {
+			token = strings.TrimPrefix(v, "Bearer ")
+		}
+		id, err := res.Resolve(token, r.Header.Get(Header))
+		if err != nil {
+			status := http.StatusUnprocessableEntity
+			switch {
+			case errors.Is(err, ErrUnverified):
+				status = http.StatusUnauthorized
+			case errors.Is(err, ErrMismatch):
+				status = http.StatusForbidden
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(response{Status: status, Message: err.Error()})
+			return
+		}
+		if id != "" {
+			r = r.WithContext(NewContext(r.Context(), id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnaryServerInterceptor carries the organization of the gRPC calls in their context,
+// resolved from their `authorization` or `x-api-key` and `x-org-id` metadata.
+func (res *Resolver) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := res.resolveMetadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the UnaryServerInterceptor of the streaming gRPC calls.
+func (res *Resolver) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := res.resolveMetadata(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (res *Resolver) resolveMetadata(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	token := first(APIKeyHeader)
+	if v := first("authorization"); strings.HasPrefix(v, "Bearer ") {
+		token = strings.TrimPrefix(v, "Bearer ")
+	}
+	id, err := res.Resolve(token, first(Header))
+	switch {
+	case errors.Is(err, ErrUnverified):
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, ErrMismatch):
+		return ctx, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return ctx, status.Error(codes.InvalidArgument, err.Error())
+	case id != "":
+		return NewContext(ctx, id), nil
+	}
+	return ctx, nil
+}
+
+// serverStream overrides the context of a gRPC stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+type response struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
@@ -1,10 +1,42 @@
 package pagination
 
+// PaginationData describes a page of a list.
+//
+// The prev and next pages are 0 when there are none. The URLs are only set by the HTTP handlers, see SetLinks.
 type PaginationData struct {
-	Total     int64 `json:"total"`
-	Page      int64 `json:"page"`
-	PerPage   int64 `json:"perPage"`
-	Prev      int64 `json:"prev"`
-	Next      int64 `json:"next"`
-	TotalPage int64 `json:"totalPage"`
+	Total     int64  `json:"total"`
+	Page      int64  `json:"page"`
+	PerPage   int64  `json:"perPage"`
+	Prev      int64  `json:"prev"`
+	Next      int64  `json:"next"`
+	TotalPage int64  `json:"totalPage"`
+	Self      string `json:"self,omitempty"`
+	First     string `json:"first,omitempty"`
+	Last      string `json:"last,omitempty"`
+}
+
+// New returns the pagination data of the page of perPage items out of the total.
+//
+// There is always at least one page, the empty one of an empty list, and the prev page of a page past the last one is
+// the last one.
+func New(page, perPage, total int64) PaginationData {
+	m := PaginationData{
+		Total:     total,
+		Page:      page,
+		PerPage:   perPage,
+		TotalPage: 1,
+	}
+	if perPage > 0 && total > perPage {
+		m.TotalPage = (total + perPage - 1) / perPage
+	}
+	if page > 1 {
+		m.Prev = page - 1
+		if m.Prev > m.TotalPage {
+			m.Prev = m.TotalPage
+		}
+	}
+	if page < m.TotalPage {
+		m.Next = page + 1
+	}
+	return m
 }
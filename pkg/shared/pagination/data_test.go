@@ -0,0 +1,34 @@
+package pagination
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name                    string
+		page, perPage, total    int64
+		wantTotalPage, wantPrev int64
+		wantNext                int64
+	}{
+		{name: "empty", page: 1, perPage: 10, total: 0, wantTotalPage: 1},
+		{name: "single page", page: 1, perPage: 10, total: 7, wantTotalPage: 1},
+		{name: "exact pages", page: 1, perPage: 10, total: 20, wantTotalPage: 2, wantNext: 2},
+		{name: "first page", page: 1, perPage: 10, total: 25, wantTotalPage: 3, wantNext: 2},
+		{name: "middle page", page: 2, perPage: 10, total: 25, wantTotalPage: 3, wantPrev: 1, wantNext: 3},
+		{name: "last page", page: 3, perPage: 10, total: 25, wantTotalPage: 3, wantPrev: 2},
+		{name: "past the end", page: 5, perPage: 10, total: 25, wantTotalPage: 3, wantPrev: 3},
+		{name: "unpaginated", page: 1, perPage: 25, total: 25, wantTotalPage: 1},
+		{name: "unpaginated empty", page: 1, perPage: 0, total: 0, wantTotalPage: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(tt.page, tt.perPage, tt.total)
+			if m.Page != tt.page || m.PerPage != tt.perPage || m.Total != tt.total {
+				t.Errorf("New() = %+v, want page %d of %d items out of %d", m, tt.page, tt.perPage, tt.total)
+			}
+			if m.TotalPage != tt.wantTotalPage || m.Prev != tt.wantPrev || m.Next != tt.wantNext {
+				t.Errorf("New() total page, prev, next = %d, %d, %d, want %d, %d, %d",
+					m.TotalPage, m.Prev, m.Next, tt.wantTotalPage, tt.wantPrev, tt.wantNext)
+			}
+		})
+	}
+}
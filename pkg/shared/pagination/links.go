@@ -0,0 +1,42 @@
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SetLinks sets the self, first and last URLs of the page, and writes them with the prev and next ones
+// in the RFC 8288 Link header of the response.
+//
+// The URLs are relative to the request's, with its query string and the page param set.
+func SetLinks(w http.ResponseWriter, r *http.Request, m *PaginationData) {
+	if m == nil {
+		return
+	}
+	m.Self = pageURL(r, m.Page)
+	m.First = pageURL(r, 1)
+	m.Last = pageURL(r, m.TotalPage)
+
+	links := []string{link(m.First, "first")}
+	if m.Prev > 0 {
+		links = append(links, link(pageURL(r, m.Prev), "prev"))
+	}
+	if m.Next > 0 {
+		links = append(links, link(pageURL(r, m.Next), "next"))
+	}
+	links = append(links, link(m.Last, "last"))
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// pageURL returns the URL of the request with its page param set.
+func pageURL(r *http.Request, page int64) string {
+	q := r.URL.Query()
+	q.Set("page", strconv.FormatInt(page, 10))
+	return r.URL.Path + "?" + q.Encode()
+}
+
+func link(url, rel string) string {
+	return fmt.Sprintf("<%s>; rel=%q", url, rel)
+}
@@ -0,0 +1,59 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetLinks(t *testing.T) {
+	tests := []struct {
+		name                string
+		data                PaginationData
+		wantSelf, wantFirst string
+		wantLast, wantLink  string
+	}{
+		{
+			name:      "middle page",
+			data:      New(2, 10, 25),
+			wantSelf:  "/breeds?limit=10&page=2&sort=name",
+			wantFirst: "/breeds?limit=10&page=1&sort=name",
+			wantLast:  "/breeds?limit=10&page=3&sort=name",
+			wantLink: `</breeds?limit=10&page=1&sort=name>; rel="first", ` +
+				`</breeds?limit=10&page=1&sort=name>; rel="prev", ` +
+				`</breeds?limit=10&page=3&sort=name>; rel="next", ` +
+				`</breeds?limit=10&page=3&sort=name>; rel="last"`,
+		},
+		{
+			name:      "single page",
+			data:      New(1, 10, 0),
+			wantSelf:  "/breeds?limit=10&page=1&sort=name",
+			wantFirst: "/breeds?limit=10&page=1&sort=name",
+			wantLast:  "/breeds?limit=10&page=1&sort=name",
+			wantLink: `</breeds?limit=10&page=1&sort=name>; rel="first", ` +
+				`</breeds?limit=10&page=1&sort=name>; rel="last"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/breeds?limit=10&page=2&sort=name", nil)
+			m := tt.data
+			SetLinks(w, r, &m)
+			if m.Self != tt.wantSelf || m.First != tt.wantFirst || m.Last != tt.wantLast {
+				t.Errorf("SetLinks() self, first, last = %q, %q, %q, want %q, %q, %q",
+					m.Self, m.First, m.Last, tt.wantSelf, tt.wantFirst, tt.wantLast)
+			}
+			if got := w.Header().Get("Link"); got != tt.wantLink {
+				t.Errorf("Link = %q, want %q", got, tt.wantLink)
+			}
+		})
+	}
+
+	t.Run("no pagination", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		SetLinks(w, httptest.NewRequest("GET", "/breeds", nil), nil)
+		if got := w.Header().Get("Link"); got != "" {
+			t.Errorf("Link = %q, want none", got)
+		}
+	})
+}
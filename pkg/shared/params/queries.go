@@ -3,7 +3,7 @@ package params
 import (
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"github.com/google/uuid"
 )
 
 type PaginationQueryParams struct {
@@ -13,22 +13,37 @@ type PaginationQueryParams struct {
 }
 
 type CustomerQueryParams struct {
-	CustomerID *primitive.ObjectID `validate:"omitempty,min=1"`
+	CustomerID *uuid.UUID `json:"customerId"`
+	Name       *string    `json:"name" validate:"omitempty,max=100"`
+	Email      *string    `json:"email" validate:"omitempty,email"`
+	Sort       *string    `json:"sort" enums:"name,-name,email,-email,createdAt,-createdAt" validate:"omitempty,oneof=name -name email -email createdAt -createdAt"`
 }
 
 type BreedQueryParams struct {
-	CreationType *string `json:"creationType" bson:"creationType" enums:"original,custom"  validate:"omitempty,oneof=original custom"`
+	CreationType *string  `json:"creationType" bson:"creationType" enums:"original,custom"  validate:"omitempty,oneof=original custom"`
+	Group        *string  `json:"group" bson:"group" enums:"herding,hound,non_sporting,sporting,terrier,toy,working,miscellaneous" validate:"omitempty,oneof=herding hound non_sporting sporting terrier toy working miscellaneous"`
+	Origin       *string  `json:"origin" bson:"origin" validate:"omitempty,iso3166_1_alpha2"`
+	Size         *string  `json:"size" bson:"size" enums:"toy,small,medium,large,giant" validate:"omitempty,oneof=toy small medium large giant"`
+	Temperament  *string  `json:"temperament" bson:"temperament" validate:"omitempty,max=32"`
+	MinWeight    *float64 `json:"minWeight" validate:"omitempty,gte=0"`
+	MaxWeight    *float64 `json:"maxWeight" validate:"omitempty,gte=0"`
+	MinHeight    *float64 `json:"minHeight" validate:"omitempty,gte=0"`
+	MaxHeight    *float64 `json:"maxHeight" validate:"omitempty,gte=0"`
+	MinLifespan  *float64 `json:"minLifespan" validate:"omitempty,gte=0"`
+	MaxLifespan  *float64 `json:"maxLifespan" validate:"omitempty,gte=0"`
+	Sort         *string  `json:"sort" enums:"name,-name,uniqueName,-uniqueName,createdAt,-createdAt,updatedAt,-updatedAt" validate:"omitempty,oneof=name -name uniqueName -uniqueName createdAt -createdAt updatedAt -updatedAt"`
+	Filter       *string  `json:"filter" validate:"omitempty,max=1000"`
 }
 
 type BookingQueryParams struct {
 	BookingState       *string    `json:"bookingState" bson:"bookingState" enums:"created,approved,paid,started,completed,cancelled,deleted,rescheduled"  validate:"omitempty,oneof=created approved paid started completed cancelled deleted rescheduled"`
-	FromDate           *time.Time `validate:"omitempty,min=1"`
-	ToDate             *time.Time `validate:"omitempty,min=1"`
-	Approved           *bool      `validate:"omitempty"`
-	Paid               *bool      `validate:"omitempty"`
-	BookedForOrder     *int       `validate:"omitempty"`
-	BookedForTimeOrder *int       `validate:"omitempty"`
-	CreatedAtOrder     *int       `validate:"omitempty"`
+	FromDate           *time.Time `json:"fromDate" validate:"omitempty"`
+	ToDate             *time.Time `json:"toDate" validate:"omitempty"`
+	Approved           *bool      `json:"approved" validate:"omitempty"`
+	Paid               *bool      `json:"paid" validate:"omitempty"`
+	BookedForOrder     *int       `json:"bookedForOrder" enums:"1,-1" validate:"omitempty,oneof=1 -1"`
+	BookedForTimeOrder *int       `json:"bookedForTimeOrder" enums:"1,-1" validate:"omitempty,oneof=1 -1"`
+	CreatedAtOrder     *int       `json:"createdAtOrder" enums:"1,-1" validate:"omitempty,oneof=1 -1"`
 }
 
 type PaystackQueryParams struct {
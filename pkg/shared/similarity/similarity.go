@@ -0,0 +1,85 @@
+package similarity
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Levenshtein returns the minimum number of single rune insertions,
+// deletions or substitutions needed to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Ratio returns how similar a and b are, from 0 (nothing in common) to 1 (equal),
+// as their edit distance normalized by the length of the longer string.
+func Ratio(a, b string) float64 {
+	n := utf8.RuneCountInString(a)
+	if m := utf8.RuneCountInString(b); m > n {
+		n = m
+	}
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(n)
+}
+
+// CanonicalWikipediaURL normalizes a Wikipedia article URL so that different spellings
+// of the same article compare equal, e.g. the mobile site, http, percent-encoding,
+// spaces, a lowercase first letter, fragments and index.php?title= links.
+//
+// URLs which aren't Wikipedia articles are returned trimmed and lowercased.
+func CanonicalWikipediaURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || !strings.HasSuffix(strings.ToLower(u.Host), "wikipedia.org") {
+		return strings.ToLower(raw)
+	}
+
+	host := strings.ToLower(u.Host)
+	host = strings.Replace(host, ".m.wikipedia.org", ".wikipedia.org", 1)
+
+	var title string
+	switch {
+	case strings.HasPrefix(u.Path, "/wiki/"):
+		title = strings.TrimPrefix(u.Path, "/wiki/")
+	case u.Query().Get("title") != "":
+		title = u.Query().Get("title")
+	default:
+		return "https://" + host + strings.TrimSuffix(u.Path, "/")
+	}
+	title = strings.Trim(strings.ReplaceAll(title, " ", "_"), "_/")
+	if r, size := utf8.DecodeRuneInString(title); r != utf8.RuneError {
+		title = string(unicode.ToUpper(r)) + title[size:]
+	}
+	return "https://" + host + "/wiki/" + title
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
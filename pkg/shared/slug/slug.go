@@ -0,0 +1,50 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations covers the letters which do not decompose into an ASCII letter and a mark,
+// and drops apostrophes so "St. Bernard's" becomes st_bernards.
+var transliterations = strings.NewReplacer(
+	"ß", "ss",
+	"æ", "ae", "Æ", "ae",
+	"œ", "oe", "Œ", "oe",
+	"ø", "o", "Ø", "o",
+	"đ", "d", "Đ", "d",
+	"ł", "l", "Ł", "l",
+	"þ", "th", "Þ", "th",
+	"ı", "i",
+	"'", "", "’", "",
+)
+
+// Make converts a name to a lowercase, underscore separated ASCII slug.
+//
+// e.g. "Chow Chow" returns "chow_chow" and "Épagneul Breton" returns "epagneul_breton"
+func Make(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	s, _, err := transform.String(t, transliterations.Replace(name))
+	if err != nil {
+		s = name
+	}
+
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			underscore = false
+			b.WriteRune(r)
+			continue
+		}
+		underscore = true
+	}
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var alphaUnderscoreRegex = regexp.MustCompile("^[a-z0-9_]+$")
+
+// Validate is the shared validator instance used to check DTOs and query params
+// against their `validate` struct tags.
+var Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// alpha_underscore only allows lowercase letters, digits and underscores (e.g. chow_chow).
+	v.RegisterValidation("alpha_underscore", func(fl validator.FieldLevel) bool {
+		return alphaUnderscoreRegex.MatchString(fl.Field().String())
+	})
+	return v
+}
+
+// Struct validates the exported fields of a struct.
+func Struct(s interface{}) error {
+	return Validate.Struct(s)
+}
+
+// IsValidationError reports whether the error is a failed validation returned by Struct.
+func IsValidationError(err error) bool {
+	var verrs validator.ValidationErrors
+	return errors.As(err, &verrs)
+}
@@ -0,0 +1,198 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Limiter is a token bucket per client: each client can spend up to limit tokens at once,
+// and the bucket refills at limit tokens per window.
+type Limiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a Limiter allowing limit tokens per window.
+// Both must be positive, an empty window refilling the buckets instantly.
+func NewLimiter(limit int, window time.Duration) (*Limiter, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("invalid rate limit %d, it must be positive", limit)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid rate limit window %v, it must be positive", window)
+	}
+	return &Limiter{limit: limit, window: window, buckets: map[string]*bucket{}}, nil
+}
+
+// Limit returns the size of the buckets.
+func (l *Limiter) Limit() int {
+	return l.limit
+}
+
+// Window returns the time it takes an empty bucket to refill.
+func (l *Limiter) Window() time.Duration {
+	return l.window
+}
+
+// Cost returns the cost capped to the limit, so every request can eventually be made.
+func (l *Limiter) Cost(cost int) int {
+	if cost > l.limit {
+		return l.limit
+	}
+	return cost
+}
+
+// Take takes cost tokens from the client's bucket, see Cost. It returns whether they were available,
+// the tokens remaining and, when they weren't, how long until they are.
+func (l *Limiter) Take(client string, cost int) (bool, int, time.Duration) {
+	cost = l.Cost(cost)
+	now := time.Now()
+	rate := float64(l.limit) / l.window.Seconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit), last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(float64(l.limit), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens < float64(cost) {
+		wait := time.Duration((float64(cost) - b.tokens) / rate * float64(time.Second))
+		return false, int(b.tokens), wait
+	}
+	b.tokens -= float64(cost)
+	return true, int(b.tokens), 0
+}
+
+// Refund gives back the tokens taken for a request which wasn't made after all, see Cost.
+func (l *Limiter) Refund(client string, cost int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[client]; ok {
+		b.tokens = math.Min(float64(l.limit), b.tokens+float64(l.Cost(cost)))
+	}
+}
+
+// UntilFull returns how long until the client's bucket is full again.
+func (l *Limiter) UntilFull(client string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[client]
+	if !ok {
+		return 0
+	}
+	missing := float64(l.limit) - b.tokens
+	return time.Duration(missing / float64(l.limit) * float64(l.window))
+}
+
+// DeleteIdle forgets the buckets which have refilled since, as they are the same as new ones.
+func (l *Limiter) DeleteIdle(now time.Time) {
+	rate := float64(l.limit) / l.window.Seconds()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*rate >= float64(l.limit) {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// Costs maps routes to the number of tokens their requests cost, 1 for the routes without one.
+type Costs []Cost
+
+// Cost is the cost of the requests to a route, optionally only those having the given query params.
+type Cost struct {
+	Method string
+	Route  string
+	Query  url.Values
+	Cost   int
+}
+
+// ParseCosts parses costs written as `<METHOD> <path template>[?<query>]=<cost>` pairs separated by semicolons,
+// e.g. `GET /breeds?paginate=false=20;GET /breeds/search=2`.
+func ParseCosts(s string) (Costs, error) {
+	var costs Costs
+	for _, pair := range strings.Split(s, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, "=")
+		method, target, ok := strings.Cut(strings.TrimSpace(pair[:max(i, 0)]), " ")
+		if i < 0 || !ok || !strings.HasPrefix(target, "/") {
+			return nil, fmt.Errorf("invalid route cost %q, expected <METHOD> <path template>=<cost>", pair)
+		}
+		cost, err := strconv.Atoi(strings.TrimSpace(pair[i+1:]))
+		if err != nil || cost < 0 {
+			return nil, fmt.Errorf("invalid route cost %q, the cost must be a positive integer", pair)
+		}
+		route, rawQuery, _ := strings.Cut(target, "?")
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route cost %q: %w", pair, err)
+		}
+		costs = append(costs, Cost{Method: strings.ToUpper(method), Route: route, Query: query, Cost: cost})
+	}
+	return costs, nil
+}
+
+// Of returns the cost of a request, that of the matching route cost with the most query params.
+func (c Costs) Of(r *http.Request) int {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return 1
+	}
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return 1
+	}
+	cost, params := 1, -1
+	for _, rc := range c {
+		if rc.Method == r.Method && rc.Route == tpl && len(rc.Query) > params && rc.matchesQuery(r.URL.Query()) {
+			cost, params = rc.Cost, len(rc.Query)
+		}
+	}
+	return cost
+}
+
+// matchesQuery reports whether the query has the cost's params, booleans being compared by value.
+func (rc Cost) matchesQuery(query url.Values) bool {
+	for name, values := range rc.Query {
+		want, got := values[0], query.Get(name)
+		if wb, err := strconv.ParseBool(want); err == nil {
+			if gb, err := strconv.ParseBool(got); err == nil && wb == gb {
+				continue
+			}
+			return false
+		}
+		if got != want {
+			return false
+		}
+	}
+	return true
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
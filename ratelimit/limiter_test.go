@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func newLimiter(t *testing.T, limit int, window time.Duration) *Limiter {
+	t.Helper()
+	l, err := NewLimiter(limit, window)
+	if err != nil {
+		t.Fatalf("NewLimiter() = %v", err)
+	}
+	return l
+}
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		limit   int
+		window  time.Duration
+		wantErr bool
+	}{
+		{limit: 120, window: time.Minute},
+		{limit: 1, window: time.Nanosecond},
+		{limit: 0, window: time.Minute, wantErr: true},
+		{limit: -1, window: time.Minute, wantErr: true},
+		{limit: 120, window: 0, wantErr: true},
+		{limit: 120, window: -time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := NewLimiter(tt.limit, tt.window)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewLimiter(%d, %v) = %v, want error %v", tt.limit, tt.window, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTake(t *testing.T) {
+	l := newLimiter(t, 3, time.Hour)
+
+	if ok, remaining, _ := l.Take("a", 1); !ok || remaining != 2 {
+		t.Errorf("Take(1) = %v, %d, want allowed with 2 remaining", ok, remaining)
+	}
+	if ok, remaining, _ := l.Take("a", 2); !ok || remaining != 0 {
+		t.Errorf("Take(2) = %v, %d, want allowed with 0 remaining", ok, remaining)
+	}
+	ok, remaining, wait := l.Take("a", 1)
+	if ok || remaining != 0 {
+		t.Errorf("Take(1) on an empty bucket = %v, %d, want refused", ok, remaining)
+	}
+	// A token comes back every 20 minutes
+	if wait <= 19*time.Minute || wait > 20*time.Minute {
+		t.Errorf("Take(1) on an empty bucket waits %v, want 20m", wait)
+	}
+
+	// The buckets are per client, and a cost over the limit is capped to it
+	if ok, remaining, _ := l.Take("b", 10); !ok || remaining != 0 {
+		t.Errorf("Take(10) = %v, %d, want allowed with 0 remaining", ok, remaining)
+	}
+}
+
+func TestTakeRefills(t *testing.T) {
+	l := newLimiter(t, 10, 100*time.Millisecond)
+	if ok, _, _ := l.Take("a", 10); !ok {
+		t.Fatal("Take(10) refused, want allowed")
+	}
+	if ok, _, _ := l.Take("a", 1); ok {
+		t.Fatal("Take(1) on an empty bucket allowed, want refused")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if ok, _, _ := l.Take("a", 1); !ok {
+		t.Error("Take(1) after half the window refused, want allowed")
+	}
+}
+
+func TestRefund(t *testing.T) {
+	l := newLimiter(t, 5, time.Hour)
+	l.Take("a", 4)
+	l.Refund("a", 3)
+	if _, remaining, _ := l.Take("a", 0); remaining != 4 {
+		t.Errorf("%d tokens remaining after the refund, want 4", remaining)
+	}
+	// A refund never overfills the bucket
+	l.Refund("a", 10)
+	if _, remaining, _ := l.Take("a", 0); remaining != 5 {
+		t.Errorf("%d tokens remaining after the refund, want 5", remaining)
+	}
+}
+
+func TestParseCosts(t *testing.T) {
+	costs, err := ParseCosts(" GET /breeds?paginate=false=20; get /breeds/search=2 ;")
+	if err != nil {
+		t.Fatalf("ParseCosts() = %v", err)
+	}
+	if len(costs) != 2 {
+		t.Fatalf("ParseCosts() = %+v, want 2 costs", costs)
+	}
+	if c := costs[0]; c.Method != "GET" || c.Route != "/breeds" || c.Query.Get("paginate") != "false" || c.Cost != 20 {
+		t.Errorf("ParseCosts()[0] = %+v", c)
+	}
+	if c := costs[1]; c.Method != "GET" || c.Route != "/breeds/search" || len(c.Query) != 0 || c.Cost != 2 {
+		t.Errorf("ParseCosts()[1] = %+v", c)
+	}
+
+	for _, s := range []string{"GET /breeds", "/breeds=2", "GET breeds=2", "GET /breeds=two", "GET /breeds=-1"} {
+		if _, err := ParseCosts(s); err == nil {
+			t.Errorf("ParseCosts(%q) = nil, want an error", s)
+		}
+	}
+}
+
+func TestCostsOf(t *testing.T) {
+	costs, err := ParseCosts("GET /breeds=2;GET /breeds?paginate=false=20;GET /breeds/{id}=3")
+	if err != nil {
+		t.Fatalf("ParseCosts() = %v", err)
+	}
+	tests := []struct {
+		method, target string
+		want           int
+	}{
+		{method: "GET", target: "/breeds", want: 2},
+		{method: "GET", target: "/breeds?paginate=false", want: 20},
+		{method: "GET", target: "/breeds?paginate=0", want: 20},
+		{method: "GET", target: "/breeds?paginate=true", want: 2},
+		{method: "GET", target: "/breeds/borzoi", want: 3},
+		{method: "POST", target: "/breeds", want: 1},
+		{method: "GET", target: "/customers", want: 1},
+	}
+	var got int
+	router := mux.NewRouter()
+	handler := func(w http.ResponseWriter, r *http.Request) { got = costs.Of(r) }
+	router.HandleFunc("/breeds", handler)
+	router.HandleFunc("/breeds/{id}", handler)
+	router.HandleFunc("/customers", handler)
+	for _, tt := range tests {
+		got = 0
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.target, nil))
+		if got != tt.want {
+			t.Errorf("cost of %s %s = %d, want %d", tt.method, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLimit is the number of tokens the clients can spend per window by default.
+	DefaultLimit = 120
+	// DefaultWindow is the time it takes a bucket to refill by default.
+	DefaultWindow = time.Minute
+	// APIKeyHeader is the request header carrying the client's API key.
+	APIKeyHeader = "X-API-Key"
+)
+
+// ClientFunc identifies the client a request comes from, the tokens and quotas being counted per client.
+type ClientFunc func(r *http.Request) string
+
+// Option configures the RateLimiter.
+type Option func(*RateLimiter)
+
+// WithCosts sets the cost of the requests to the routes.
+func WithCosts(costs Costs) Option {
+	return func(rl *RateLimiter) {
+		rl.costs = costs
+	}
+}
+
+// WithDailyQuota limits the tokens each client can spend per day, tracked in the given store.
+func WithDailyQuota(store QuotaStore, quota int) Option {
+	return func(rl *RateLimiter) {
+		rl.quotas = store
+		rl.quota = quota
+	}
+}
+
+// WithClientFunc sets how the clients are identified.
+func WithClientFunc(fn ClientFunc) Option {
+	return func(rl *RateLimiter) {
+		rl.client = fn
+	}
+}
+
+// RateLimiter rejects the requests of the clients which have spent their tokens, or their daily quota,
+// with a 429 Too Many Requests.
+type RateLimiter struct {
+	limiter *Limiter
+	costs   Costs
+	quotas  QuotaStore
+	quota   int
+	client  ClientFunc
+}
+
+// NewRateLimiter returns a RateLimiter taking the cost of the requests from the limiter's buckets.
+func NewRateLimiter(limiter *Limiter, opts ...Option) *RateLimiter {
+	rl := &RateLimiter{limiter: limiter, client: DefaultClient}
+	for _, opt := range opts {
+		opt(rl)
+	}
+	return rl
+}
+
+// DefaultClient identifies the clients by their IP address.
+func DefaultClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// KeyClient identifies the clients by their API key, or bearer token, when verify accepts it, and by their IP
+// address otherwise, so a client can't get fresh buckets by making up keys.
+// The keys are hashed, so they aren't kept in the quota store.
+func KeyClient(verify func(key string) bool) ClientFunc {
+	return func(r *http.Request) string {
+		key := r.Header.Get(APIKeyHeader)
+		if v := r.Header.Get("Authorization"); strings.HasPrefix(v, "Bearer ") {
+			key = strings.TrimPrefix(v, "Bearer ")
+		}
+		if key == "" || !verify(key) {
+			return DefaultClient(r)
+		}
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:])
+	}
+}
+
+// Run forgets the idle buckets and the past days' usage every interval, until the context is done.
+func (rl *RateLimiter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			rl.limiter.DeleteIdle(now)
+			if rl.quotas == nil {
+				continue
+			}
+			if err := rl.quotas.DeleteExpired(ctx, Day(now)); err != nil && ctx.Err() == nil {
+				log.Printf("Unable to delete the expired quota usage: %+v\n", err)
+			}
+		}
+	}
+}
+
+// Middleware applies the RateLimiter to the requests, and sets their RateLimit-* headers.
+//
+// The headers describe the policy closest to being exhausted: the token bucket, whose reset is the time until
+// it is full again, or the daily quota, which resets at midnight UTC.
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := rl.client(r)
+		// Both the bucket and the quota count the cost capped by the bucket
+		cost := rl.limiter.Cost(rl.costs.Of(r))
+
+		policy := fmt.Sprintf("%d;w=%d", rl.limiter.Limit(), int(rl.limiter.Window().Seconds()))
+		if rl.quotas != nil {
+			policy += fmt.Sprintf(", %d;w=%d", rl.quota, int((24 * time.Hour).Seconds()))
+		}
+		w.Header().Set("RateLimit-Policy", policy)
+
+		allowed, remaining, wait := rl.limiter.Take(client, cost)
+		if !allowed {
+			writeLimit(w, rl.limiter.Limit(), remaining, rl.limiter.UntilFull(client))
+			writeTooManyRequests(w, wait, "rate limit exceeded")
+			return
+		}
+		limit, reset := rl.limiter.Limit(), rl.limiter.UntilFull(client)
+
+		if rl.quotas != nil {
+			now := time.Now()
+			day := Day(now)
+			untilReset := day.Add(24 * time.Hour).Sub(now)
+			ok, used, err := rl.quotas.Consume(r.Context(), client, day, cost, rl.quota)
+			switch {
+			case err != nil:
+				// Let the request through rather than failing it when the usage can't be counted.
+				log.Printf("Unable to count the quota usage: %+v\n", err)
+			case !ok:
+				// The request isn't made, so it doesn't spend the client's bucket either
+				rl.limiter.Refund(client, cost)
+				writeLimit(w, rl.quota, rl.quota-used, untilReset)
+				writeTooManyRequests(w, untilReset, "daily quota exceeded")
+				return
+			case float64(rl.quota-used)/float64(rl.quota) < float64(remaining)/float64(limit):
+				limit, remaining, reset = rl.quota, rl.quota-used, untilReset
+			}
+		}
+
+		writeLimit(w, limit, remaining, reset)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeLimit(w http.ResponseWriter, limit, remaining int, reset time.Duration) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(reset)))
+}
+
+func writeTooManyRequests(w http.ResponseWriter, retryAfter time.Duration, message string) {
+	w.Header().Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	err := json.NewEncoder(w).Encode(response{Status: http.StatusTooManyRequests, Message: message})
+	if err != nil {
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+	}
+}
+
+// seconds rounds a duration up to whole seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+type response struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// serve serves the request to a router answering 200 on /breeds, behind the RateLimiter.
+func serve(rl *RateLimiter, r *http.Request) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.Use(rl.Middleware)
+	router.HandleFunc("/breeds", func(w http.ResponseWriter, r *http.Request) {})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func get(rl *RateLimiter, target string) *httptest.ResponseRecorder {
+	return serve(rl, httptest.NewRequest("GET", target, nil))
+}
+
+func header(t *testing.T, w *httptest.ResponseRecorder, name string) int {
+	t.Helper()
+	v, err := strconv.Atoi(w.Header().Get(name))
+	if err != nil {
+		t.Fatalf("%s = %q, want a number", name, w.Header().Get(name))
+	}
+	return v
+}
+
+func TestMiddlewareHeaders(t *testing.T) {
+	rl := NewRateLimiter(newLimiter(t, 5, time.Minute))
+
+	w := get(rl, "/breeds")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("RateLimit-Policy"); got != "5;w=60" {
+		t.Errorf("RateLimit-Policy = %q, want %q", got, "5;w=60")
+	}
+	if got := header(t, w, "RateLimit-Limit"); got != 5 {
+		t.Errorf("RateLimit-Limit = %d, want 5", got)
+	}
+	if got := header(t, w, "RateLimit-Remaining"); got != 4 {
+		t.Errorf("RateLimit-Remaining = %d, want 4", got)
+	}
+	// The bucket is full again once the token is back, after 12s
+	if got := header(t, w, "RateLimit-Reset"); got < 11 || got > 13 {
+		t.Errorf("RateLimit-Reset = %d, want 12", got)
+	}
+}
+
+func TestMiddlewareRejects(t *testing.T) {
+	rl := NewRateLimiter(newLimiter(t, 2, time.Minute))
+	get(rl, "/breeds")
+	get(rl, "/breeds")
+
+	w := get(rl, "/breeds")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	if got := header(t, w, "RateLimit-Remaining"); got != 0 {
+		t.Errorf("RateLimit-Remaining = %d, want 0", got)
+	}
+	if got := header(t, w, "Retry-After"); got < 29 || got > 31 {
+		t.Errorf("Retry-After = %d, want 30", got)
+	}
+	var body response
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding the body: %v", err)
+	}
+	if body.Status != http.StatusTooManyRequests || body.Message != "rate limit exceeded" {
+		t.Errorf("body = %+v, want the rate limit exceeded", body)
+	}
+
+	// The other clients have their own bucket
+	r := httptest.NewRequest("GET", "/breeds", nil)
+	r.RemoteAddr = "198.51.100.7:1234"
+	if w := serve(rl, r); w.Code != http.StatusOK {
+		t.Errorf("status of another client = %d, want 200", w.Code)
+	}
+}
+
+func TestMiddlewareCosts(t *testing.T) {
+	costs, err := ParseCosts("GET /breeds?paginate=false=20")
+	if err != nil {
+		t.Fatalf("ParseCosts() = %v", err)
+	}
+	store := NewMemoryQuotaStore()
+	rl := NewRateLimiter(newLimiter(t, 10, time.Hour), WithCosts(costs), WithDailyQuota(store, 100))
+
+	// The cost over the bucket size is capped to it, for the bucket and the quota alike
+	w := get(rl, "/breeds?paginate=false")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := header(t, w, "RateLimit-Remaining"); got != 0 {
+		t.Errorf("RateLimit-Remaining = %d, want 0", got)
+	}
+	_, used, err := store.Consume(context.Background(), DefaultClient(httptest.NewRequest("GET", "/", nil)), Day(time.Now()), 0, 100)
+	if err != nil {
+		t.Fatalf("Consume() = %v", err)
+	}
+	if used != 10 {
+		t.Errorf("%d tokens of the quota used, want 10", used)
+	}
+}
+
+func TestMiddlewareDailyQuota(t *testing.T) {
+	limiter := newLimiter(t, 10, time.Hour)
+	rl := NewRateLimiter(limiter, WithDailyQuota(NewMemoryQuotaStore(), 3))
+	client := DefaultClient(httptest.NewRequest("GET", "/", nil))
+
+	w := get(rl, "/breeds")
+	if got := w.Header().Get("RateLimit-Policy"); got != "10;w=3600, 3;w=86400" {
+		t.Errorf("RateLimit-Policy = %q, want both policies", got)
+	}
+	// The headers describe the quota once it is closer to being exhausted than the bucket
+	w = get(rl, "/breeds")
+	if limit, remaining := header(t, w, "RateLimit-Limit"), header(t, w, "RateLimit-Remaining"); limit != 3 || remaining != 1 {
+		t.Errorf("RateLimit-Limit, RateLimit-Remaining = %d, %d, want the quota's 3, 1", limit, remaining)
+	}
+	get(rl, "/breeds")
+
+	w = get(rl, "/breeds")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	if remaining := header(t, w, "RateLimit-Remaining"); remaining != 0 {
+		t.Errorf("RateLimit-Remaining = %d, want 0", remaining)
+	}
+	untilMidnight := int(Day(time.Now()).Add(24 * time.Hour).Sub(time.Now()).Seconds())
+	if got := header(t, w, "Retry-After"); got < untilMidnight || got > untilMidnight+2 {
+		t.Errorf("Retry-After = %d, want the %d seconds until midnight UTC", got, untilMidnight)
+	}
+	var body response
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding the body: %v", err)
+	}
+	if body.Message != "daily quota exceeded" {
+		t.Errorf("body = %+v, want the daily quota exceeded", body)
+	}
+
+	// The requests refused by the quota don't spend the bucket
+	if _, remaining, _ := limiter.Take(client, 0); remaining != 7 {
+		t.Errorf("%d tokens remaining in the bucket, want 7", remaining)
+	}
+}
+
+func TestKeyClient(t *testing.T) {
+	client := KeyClient(func(key string) bool { return key == "valid" })
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{name: "no key", want: "ip:192.0.2.1"},
+		{name: "unknown key", headers: map[string]string{APIKeyHeader: "made-up"}, want: "ip:192.0.2.1"},
+		{name: "API key", headers: map[string]string{APIKeyHeader: "valid"}, want: "key:"},
+		{name: "bearer token", headers: map[string]string{"Authorization": "Bearer valid"}, want: "key:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/breeds", nil)
+			for name, v := range tt.headers {
+				r.Header.Set(name, v)
+			}
+			got := client(r)
+			if got[:len(tt.want)] != tt.want || (tt.want == "key:" && len(got) != len("key:")+64) {
+				t.Errorf("client = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+// QuotaStore tracks the tokens spent by the clients each day.
+type QuotaStore interface {
+	// Consume adds cost to the tokens the client spent on the day, unless that would exceed the limit.
+	// It returns whether the cost was added, and the tokens spent on the day.
+	Consume(ctx context.Context, client string, day time.Time, cost, limit int) (bool, int, error)
+	// DeleteExpired forgets the days before the given one.
+	DeleteExpired(ctx context.Context, day time.Time) error
+}
+
+// QuotaUsage is the tokens a client spent on a day.
+// The type name makes Tigris name the collection `quota_usages`.
+type QuotaUsage struct {
+	ID        string    `json:"id" tigris:"primaryKey:1"`
+	Client    string    `json:"client"`
+	Day       time.Time `json:"day" tigris:"index"`
+	Used      int       `json:"used"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Day returns the UTC day of the given time, the quotas resetting at midnight UTC.
+func Day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+type memoryQuotaStore struct {
+	mu    sync.Mutex
+	usage map[string]QuotaUsage
+}
+
+// NewMemoryQuotaStore returns a QuotaStore keeping the usage in memory,
+// which is therefore counted per instance and reset on restarts.
+func NewMemoryQuotaStore() QuotaStore {
+	return &memoryQuotaStore{usage: map[string]QuotaUsage{}}
+}
+
+func (s *memoryQuotaStore) Consume(ctx context.Context, client string, day time.Time, cost, limit int) (bool, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usage[client]
+	if !u.Day.Equal(day) {
+		u = QuotaUsage{Client: client, Day: day}
+	}
+	if u.Used+cost > limit {
+		return false, u.Used, nil
+	}
+	u.Used += cost
+	u.UpdatedAt = time.Now().UTC()
+	s.usage[client] = u
+	return true, u.Used, nil
+}
+
+func (s *memoryQuotaStore) DeleteExpired(ctx context.Context, day time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client, u := range s.usage {
+		if u.Day.Before(day) {
+			delete(s.usage, client)
+		}
+	}
+	return nil
+}
+
+type tigrisQuotaStore struct {
+	db         *tigris.Database
+	collection *tigris.Collection[QuotaUsage]
+}
+
+// NewTigrisQuotaStore returns a QuotaStore keeping the usage in the `quota_usages` collection,
+// shared between all the instances of the server.
+func NewTigrisQuotaStore(db *tigris.Database) QuotaStore {
+	return &tigrisQuotaStore{db: db, collection: tigris.GetCollection[QuotaUsage](db)}
+}
+
+// Consume reads and writes the usage in a transaction, retried on conflicts,
+// so the concurrent requests of a client are all counted.
+func (s tigrisQuotaStore) Consume(ctx context.Context, client string, day time.Time, cost, limit int) (bool, int, error) {
+	id := client + "|" + day.Format("2006-01-02")
+	var allowed bool
+	var used int
+	err := s.db.Tx(ctx, func(ctx context.Context) error {
+		u, err := s.collection.ReadOne(ctx, filter.Eq("id", id))
+		if errors.Is(err, tigris.ErrNotFound) {
+			u, err = &QuotaUsage{ID: id, Client: client, Day: day}, nil
+		}
+		if err != nil {
+			return err
+		}
+		if u.Used+cost > limit {
+			allowed, used = false, u.Used
+			return nil
+		}
+		u.Used += cost
+		u.UpdatedAt = time.Now().UTC()
+		if _, err := s.collection.InsertOrReplace(ctx, u); err != nil {
+			return err
+		}
+		allowed, used = true, u.Used
+		return nil
+	}, tigris.TxOptions{AutoRetry: true})
+	return allowed, used, err
+}
+
+func (s tigrisQuotaStore) DeleteExpired(ctx context.Context, day time.Time) error {
+	_, err := s.collection.Delete(ctx, filter.Lt("day", day))
+	return err
+}
@@ -0,0 +1,124 @@
+package resource
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSlotInterval is the number of minutes between the starts of the slots offered by default.
+const DefaultSlotInterval = 30
+
+// DefaultCurrency is the currency of the resources which don't give one.
+const DefaultCurrency = "NGN"
+
+// Resource struct
+//
+// A resource is what the bookings are made on, e.g. a grooming table, and can take up to Capacity
+// bookings at the same time, within its working hours. The working hours and the bookings' days
+// and times are in the resource's TimeZone. The bookings are charged HourlyRate per hour, in the
+// currency's minor unit, e.g. kobo for NGN, and are free when it is zero.
+type Resource struct {
+	ID           uuid.UUID      `json:"id" tigris:"primaryKey:1" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	Name         string         `json:"name" tigris:"index" example:"Grooming table 1"`
+	Capacity     int            `json:"capacity" example:"1"`
+	TimeZone     string         `json:"timeZone" example:"Europe/Berlin"`
+	SlotInterval int            `json:"slotInterval" example:"30"`
+	WorkingHours []WorkingHours `json:"workingHours"`
+	HourlyRate   int64          `json:"hourlyRate" example:"500000"`
+	Currency     string         `json:"currency" example:"NGN"`
+	CreatedAt    time.Time      `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt    time.Time      `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// WorkingHours struct
+//
+// The hours a resource is open on a day of the week, a day having several of them for breaks.
+type WorkingHours struct {
+	Weekday string `json:"weekday" validate:"required,oneof=sunday monday tuesday wednesday thursday friday saturday" example:"monday"`
+	Open    string `json:"open" validate:"required,datetime=15:04" example:"09:00"`
+	Close   string `json:"close" validate:"required,datetime=15:04" example:"17:00"`
+}
+
+// Price returns the price of a booking of the minutes on the resource, rounded to the nearest minor unit.
+func (r Resource) Price(minutes int) int64 {
+	return (r.HourlyRate*int64(minutes) + 30) / 60
+}
+
+// Location returns the time zone of the resource, UTC when it's unset or unknown.
+func (r Resource) Location() *time.Location {
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Windows returns the working hours of the resource on the day, as [open, close) time ranges.
+func (r Resource) Windows(day time.Time) [][2]time.Time {
+	loc := r.Location()
+	y, m, d := day.Date()
+	var windows [][2]time.Time
+	for _, wh := range r.WorkingHours {
+		if wh.Weekday != weekdays[time.Date(y, m, d, 0, 0, 0, 0, loc).Weekday()] {
+			continue
+		}
+		open, err := At(day, wh.Open, loc)
+		if err != nil {
+			continue
+		}
+		close, err := At(day, wh.Close, loc)
+		if err != nil {
+			continue
+		}
+		windows = append(windows, [2]time.Time{open, close})
+	}
+	return windows
+}
+
+// Open reports whether the [start, end) range lies within one of the working hours of the resource.
+func (r Resource) Open(start, end time.Time) bool {
+	for _, w := range r.Windows(start.In(r.Location())) {
+		if !start.Before(w[0]) && !end.After(w[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// At returns the time of day (15:04) on the day, in the location.
+func At(day time.Time, clock string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	y, m, d := day.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, loc), nil
+}
+
+var weekdays = [...]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// CreateResource struct
+type CreateResource struct {
+	Name         string         `json:"name" validate:"required,max=100" example:"Grooming table 1"`
+	Capacity     int            `json:"capacity" validate:"required,min=1" example:"1"`
+	TimeZone     string         `json:"timeZone" validate:"omitempty,timezone" example:"Europe/Berlin"`
+	SlotInterval int            `json:"slotInterval" validate:"omitempty,min=5,max=1440" example:"30"`
+	WorkingHours []WorkingHours `json:"workingHours" validate:"required,min=1,dive"`
+	HourlyRate   int64          `json:"hourlyRate" validate:"min=0" example:"500000"`
+	Currency     string         `json:"currency" validate:"omitempty,oneof=NGN GHS ZAR KES USD" example:"NGN"`
+	CreatedAt    time.Time      `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt    time.Time      `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// UpdateResource struct
+type UpdateResource struct {
+	Name         string         `json:"name" validate:"omitempty,max=100" example:"Grooming table 1"`
+	Capacity     int            `json:"capacity" validate:"omitempty,min=1" example:"2"`
+	TimeZone     string         `json:"timeZone" validate:"omitempty,timezone" example:"Europe/Berlin"`
+	SlotInterval int            `json:"slotInterval" validate:"omitempty,min=5,max=1440" example:"15"`
+	WorkingHours []WorkingHours `json:"workingHours" validate:"omitempty,min=1,dive"`
+	HourlyRate   *int64         `json:"hourlyRate" validate:"omitempty,min=0" example:"750000"`
+	Currency     string         `json:"currency" validate:"omitempty,oneof=NGN GHS ZAR KES USD" example:"NGN"`
+	UpdatedAt    time.Time      `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
@@ -0,0 +1,181 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+func GetAllResources(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qp := crud.PaginationQueryParams(r)
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /resources - PaginationQueryParams: %+v - Took: %v\n", qp, time.Since(begin))
+		}(time.Now())
+		data, metadata, err := s.GetAllResources(r.Context(), qp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data, Metadata: metadata})
+	}
+}
+
+func GetSingleResource(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := resourceID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /resources/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.GetSingleResource(r.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func CreateSingleResource(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var dto CreateResource
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+		// Set default timestamps
+		now := time.Now().UTC()
+		dto.CreatedAt = now
+		dto.UpdatedAt = now
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /resources - Took: %v\n", time.Since(begin))
+		}(time.Now())
+		data, err := s.CreateSingleResource(r.Context(), dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusCreated, Message: "success", Data: data})
+	}
+}
+
+func UpdateSingleResource(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := resourceID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		var dto UpdateResource
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+		// Set default timestamps
+		dto.UpdatedAt = time.Now().UTC()
+
+		defer func(begin time.Time) {
+			fmt.Printf("PATCH /resources/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.UpdateSingleResource(r.Context(), id, dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func DeleteSingleResource(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := resourceID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("DELETE /resources/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		if err := s.DeleteSingleResource(r.Context(), id); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success"})
+	}
+}
+
+// resourceID parses the `id` path variable, an invalid ID being reported as not found.
+func resourceID(r *http.Request) (uuid.UUID, error) {
+	v, ok := mux.Vars(r)["id"]
+	if !ok {
+		panic(ErrBadRouting)
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return uuid.Nil, ErrNotFound
+	}
+	return id, nil
+}
+
+// writeError maps the error to its HTTP status code and writes it as the response.
+func writeError(w http.ResponseWriter, err error) {
+	var status int
+	message := err.Error()
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case validation.IsValidationError(err), errors.Is(err, ErrInvalidWorkingHours):
+		status = http.StatusUnprocessableEntity
+	default:
+		log.Printf("Unable to handle resource request: %+v\n", err)
+		status = http.StatusInternalServerError
+		message = "internal error"
+	}
+	writeResponse(w, Response{Status: status, Message: message})
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	// set the content type to application/json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
+
+	// encode the response struct as JSON and write it to the response writer
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		// handle the error
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+		return
+	}
+}
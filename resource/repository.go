@@ -0,0 +1,152 @@
+package resource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/sort"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+// ErrNotFound is returned when no resource has the given ID.
+var ErrNotFound = errors.New("resource not found")
+
+// Repository is responsible for managing the persistence layer of the resources. (e.g. database operations)
+type Repository interface {
+	GetAllResources(ctx context.Context, qp params.PaginationQueryParams) ([]Resource, *pagination.PaginationData, error)
+	GetSingleResource(ctx context.Context, id uuid.UUID) (Resource, error)
+	CreateSingleResource(ctx context.Context, dto CreateResource) (Resource, error)
+	UpdateSingleResource(ctx context.Context, id uuid.UUID, dto UpdateResource) (Resource, error)
+	DeleteSingleResource(ctx context.Context, id uuid.UUID) error
+}
+
+type resourceRepository struct {
+	db         *tigris.Database
+	collection *tigris.Collection[Resource]
+}
+
+// NewResourceRepository returns a concrete implementation of the Repository interface.
+func NewResourceRepository(db *tigris.Database) Repository {
+	return &resourceRepository{db: db, collection: tigris.GetCollection[Resource](db)}
+}
+
+func (r resourceRepository) GetAllResources(ctx context.Context, qp params.PaginationQueryParams) ([]Resource, *pagination.PaginationData, error) {
+	var resources []Resource = []Resource{}
+
+	// Add initial pagination data
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	c, err := r.collection.Count(ctx, filter.All)
+	if err != nil {
+		return resources, &m, err
+	}
+	if !qp.Paginate {
+		m.Page = 1
+		m.PerPage = c
+	}
+	options := tigris.ReadOptions{
+		Skip:  (m.Page - 1) * m.PerPage,
+		Limit: m.PerPage,
+		Sort:  sort.Ascending("name"),
+	}
+	it, err := r.collection.ReadWithOptions(ctx, filter.All, fields.All, &options)
+	if err != nil {
+		return resources, &m, err
+	}
+	defer it.Close()
+
+	var resource Resource
+	for it.Next(&resource) {
+		resources = append(resources, resource)
+	}
+
+	m = pagination.New(m.Page, m.PerPage, c)
+
+	return resources, &m, it.Err()
+}
+
+func (r resourceRepository) GetSingleResource(ctx context.Context, id uuid.UUID) (Resource, error) {
+	resource, err := r.collection.ReadOne(ctx, filter.Eq("id", id))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return Resource{}, ErrNotFound
+	}
+	if err != nil {
+		return Resource{}, err
+	}
+	return *resource, nil
+}
+
+func (r resourceRepository) CreateSingleResource(ctx context.Context, dto CreateResource) (Resource, error) {
+	resource := Resource{
+		ID:           uuid.New(),
+		Name:         dto.Name,
+		Capacity:     dto.Capacity,
+		TimeZone:     dto.TimeZone,
+		SlotInterval: dto.SlotInterval,
+		WorkingHours: dto.WorkingHours,
+		HourlyRate:   dto.HourlyRate,
+		Currency:     dto.Currency,
+		CreatedAt:    dto.CreatedAt,
+		UpdatedAt:    dto.UpdatedAt,
+	}
+	if _, err := r.collection.Insert(ctx, &resource); err != nil {
+		return Resource{}, err
+	}
+	return resource, nil
+}
+
+func (r resourceRepository) UpdateSingleResource(ctx context.Context, id uuid.UUID, dto UpdateResource) (Resource, error) {
+	var updated Resource
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		resource, err := r.GetSingleResource(ctx, id)
+		if err != nil {
+			return err
+		}
+		if dto.Name != "" {
+			resource.Name = dto.Name
+		}
+		if dto.Capacity != 0 {
+			resource.Capacity = dto.Capacity
+		}
+		if dto.TimeZone != "" {
+			resource.TimeZone = dto.TimeZone
+		}
+		if dto.SlotInterval != 0 {
+			resource.SlotInterval = dto.SlotInterval
+		}
+		if dto.WorkingHours != nil {
+			resource.WorkingHours = dto.WorkingHours
+		}
+		if dto.HourlyRate != nil {
+			resource.HourlyRate = *dto.HourlyRate
+		}
+		if dto.Currency != "" {
+			resource.Currency = dto.Currency
+		}
+		resource.UpdatedAt = dto.UpdatedAt
+		if _, err := r.collection.InsertOrReplace(ctx, &resource); err != nil {
+			return err
+		}
+		updated = resource
+		return nil
+	})
+	if err != nil {
+		return Resource{}, err
+	}
+	return updated, nil
+}
+
+func (r resourceRepository) DeleteSingleResource(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.GetSingleResource(ctx, id); err != nil {
+		return err
+	}
+	_, err := r.collection.DeleteOne(ctx, filter.Eq("id", id))
+	return err
+}
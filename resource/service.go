@@ -0,0 +1,136 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+// ErrInvalidWorkingHours is returned when working hours don't close after they open.
+var ErrInvalidWorkingHours = errors.New("working hours must close after they open")
+
+type Service struct {
+	r Repository
+}
+
+// NewResourceService returns a service
+func NewResourceService(r Repository) *Service {
+	return &Service{r: r}
+}
+
+// GetAllResources godoc
+// @Summary Get all bookable resources
+// @Description Get all the resources the bookings are made on, sorted by name
+// @Security Bearer
+// @Tags Resource
+// @Accept json
+// @Produce json
+// @Success 200 {object} JSONResultSuccess{data=[]Resource} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /resources [get]
+func (s *Service) GetAllResources(ctx context.Context, qp params.PaginationQueryParams) ([]Resource, *pagination.PaginationData, error) {
+	return s.r.GetAllResources(ctx, qp)
+}
+
+// GetSingleResource godoc
+// @Summary Get single bookable resource
+// @Description Get a single resource, with its capacity and working hours
+// @Security Bearer
+// @Tags Resource
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the resource"
+// @Success 200 {object} JSONResultSuccess{data=Resource} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /resources/{id} [get]
+func (s *Service) GetSingleResource(ctx context.Context, id uuid.UUID) (Resource, error) {
+	return s.r.GetSingleResource(ctx, id)
+}
+
+// CreateSingleResource godoc
+// @Summary Create single bookable resource
+// @Description Create a single resource, in UTC and offering slots every 30 minutes unless set otherwise
+// @Security Bearer
+// @Tags Resource
+// @Accept json
+// @Produce json
+// @Param body body CreateResource true "JSON body to create a resource"
+// @Success 201 {object} JSONResultSuccess{data=Resource} "Created"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /resources [post]
+func (s *Service) CreateSingleResource(ctx context.Context, dto CreateResource) (Resource, error) {
+	if err := checkWorkingHours(dto.WorkingHours); err != nil {
+		return Resource{}, err
+	}
+	if dto.TimeZone == "" {
+		dto.TimeZone = "UTC"
+	}
+	if dto.SlotInterval == 0 {
+		dto.SlotInterval = DefaultSlotInterval
+	}
+	if dto.Currency == "" {
+		dto.Currency = DefaultCurrency
+	}
+	return s.r.CreateSingleResource(ctx, dto)
+}
+
+// UpdateSingleResource godoc
+// @Summary Update single bookable resource
+// @Description Update a single resource, the existing bookings are kept even when they no longer fit
+// @Security Bearer
+// @Tags Resource
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the resource"
+// @Param body body UpdateResource true "JSON body to update a resource"
+// @Success 200 {object} JSONResultSuccess{data=Resource} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /resources/{id} [patch]
+func (s *Service) UpdateSingleResource(ctx context.Context, id uuid.UUID, dto UpdateResource) (Resource, error) {
+	if err := checkWorkingHours(dto.WorkingHours); err != nil {
+		return Resource{}, err
+	}
+	return s.r.UpdateSingleResource(ctx, id, dto)
+}
+
+// DeleteSingleResource godoc
+// @Summary Delete single bookable resource
+// @Description Delete a single resource
+// @Security Bearer
+// @Tags Resource
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the resource"
+// @Success 200 {object} JSONResultSuccess{} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /resources/{id} [delete]
+func (s *Service) DeleteSingleResource(ctx context.Context, id uuid.UUID) error {
+	return s.r.DeleteSingleResource(ctx, id)
+}
+
+// checkWorkingHours returns ErrInvalidWorkingHours for the working hours not closing after they open.
+func checkWorkingHours(hours []WorkingHours) error {
+	for _, wh := range hours {
+		open, _ := time.Parse("15:04", wh.Open)
+		close, _ := time.Parse("15:04", wh.Close)
+		if !close.After(open) {
+			return fmt.Errorf("%w: %s %s-%s", ErrInvalidWorkingHours, wh.Weekday, wh.Open, wh.Close)
+		}
+	}
+	return nil
+}
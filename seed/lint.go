@@ -0,0 +1,39 @@
+package seed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/simply-alliv/tigris-go-explore/breed"
+)
+
+// Lint checks the breeds file for near-duplicate breeds, printing each one found.
+func Lint(breedsFile string, threshold float64) ([]breed.Duplicate, error) {
+	breeds, err := readBreeds(breedsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := breed.FindDuplicates(breeds, threshold)
+	for _, d := range duplicates {
+		fmt.Printf("%s: near-duplicate of %s (reason: %s, name similarity: %.2f)\n", d.UniqueName, d.DuplicateOf, d.Reason, d.NameSimilarity)
+	}
+	fmt.Printf("Linted %d breeds from %s: %d near-duplicates found\n", len(breeds), breedsFile, len(duplicates))
+	return duplicates, nil
+}
+
+func readBreeds(breedsFile string) ([]breed.Breed, error) {
+	// Read the JSON file
+	data, err := os.ReadFile(breedsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	// Unmarshal the JSON data into a slice of Breed structs
+	var breeds []breed.Breed
+	if err := json.Unmarshal(data, &breeds); err != nil {
+		return nil, fmt.Errorf("error unmarshalling JSON: %w", err)
+	}
+	return breeds, nil
+}
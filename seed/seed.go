@@ -2,10 +2,8 @@ package seed
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"github.com/simply-alliv/tigris-go-explore/breed"
@@ -14,18 +12,10 @@ import (
 
 func SeedData(ctx context.Context, breedsFile string, collection *tigris.Collection[breed.Breed]) {
 	fmt.Println("Seeding of the data will now start")
-	// Read the JSON file
-	data, err := os.ReadFile(breedsFile)
+	// Read the JSON file into a slice of Breed structs
+	originalBreeds, err := readBreeds(breedsFile)
 	if err != nil {
-		log.Fatal("Error reading file: ", err)
-		return
-	}
-
-	// Unmarshal the JSON data into a slice of Breed structs
-	var originalBreeds []breed.Breed
-	err = json.Unmarshal(data, &originalBreeds)
-	if err != nil {
-		log.Fatal("Error unmarshalling JSON: ", err)
+		log.Fatal(err)
 		return
 	}
 
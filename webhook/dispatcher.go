@@ -0,0 +1,438 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/events"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of attempts at delivering an event before it is dead-lettered.
+	DefaultMaxAttempts = 8
+	// DefaultInitialBackoff is the default delay before the first retry, doubled on every following one.
+	DefaultInitialBackoff = 5 * time.Second
+	// DefaultMaxBackoff caps the delay between two retries.
+	DefaultMaxBackoff = 30 * time.Minute
+	// DefaultTimeout is the default time a receiver has to answer a delivery.
+	DefaultTimeout = 10 * time.Second
+	// DefaultWorkers is the default number of deliveries sent concurrently.
+	DefaultWorkers = 4
+	// DefaultPollInterval is the default delay between two reads of the pending deliveries.
+	DefaultPollInterval = time.Second
+	// DefaultClaimLease is the default time a dispatcher has to make an attempt before another one may retry it.
+	DefaultClaimLease = time.Minute
+)
+
+// PingEvent is the type of the event sent by the test endpoint.
+const PingEvent = "webhook.ping"
+
+// job is an event to deliver to a subscription. The payload is the event marshaled when it was published.
+type job struct {
+	sub     Subscription
+	event   events.Event
+	payload []byte
+	attempt int
+}
+
+// Dispatcher delivers the events relayed from the outbox to the matching webhook subscriptions.
+//
+// The deliveries are persisted as pending before the event is acknowledged, and read back from
+// the store by the workers, so they survive a restart. Every attempt is recorded in the delivery
+// log. Failed deliveries are retried with an exponential backoff, and dead-lettered once the
+// maximum number of attempts is reached.
+type Dispatcher struct {
+	r               Repository
+	client          *http.Client
+	maxAttempts     int
+	initialBackoff  time.Duration
+	maxBackoff      time.Duration
+	workers         int
+	pollInterval    time.Duration
+	claimLease      time.Duration
+	privateNetworks bool
+	jobs            chan PendingDelivery
+	wake            chan struct{}
+
+	mu     sync.Mutex
+	queued map[uuid.UUID]bool
+}
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithHTTPClient sets the client the deliveries are sent with, e.g. the one of an httptest.Server.
+// The default client only connects to public addresses, see WithPrivateNetworks.
+func WithHTTPClient(client *http.Client) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// WithPrivateNetworks allows the subscription URLs resolving to loopback, link-local and private addresses,
+// e.g. to deliver to a receiver running next to the server in development.
+func WithPrivateNetworks() DispatcherOption {
+	return func(d *Dispatcher) {
+		d.privateNetworks = true
+	}
+}
+
+// WithMaxAttempts sets the number of attempts at delivering an event before it is dead-lettered.
+func WithMaxAttempts(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the delay before the first retry, and the cap of the following exponential delays.
+func WithBackoff(initial, max time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.initialBackoff = initial
+		d.maxBackoff = max
+	}
+}
+
+// WithWorkers sets the number of deliveries sent concurrently.
+func WithWorkers(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.workers = n
+	}
+}
+
+// WithPollInterval sets the delay between two reads of the pending deliveries.
+func WithPollInterval(d time.Duration) DispatcherOption {
+	return func(disp *Dispatcher) {
+		disp.pollInterval = d
+	}
+}
+
+// WithClaimLease sets the time a dispatcher has to make an attempt before another one may retry it.
+func WithClaimLease(d time.Duration) DispatcherOption {
+	return func(disp *Dispatcher) {
+		disp.claimLease = d
+	}
+}
+
+// NewDispatcher returns a dispatcher
+func NewDispatcher(r Repository, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		r:              r,
+		maxAttempts:    DefaultMaxAttempts,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		workers:        DefaultWorkers,
+		pollInterval:   DefaultPollInterval,
+		claimLease:     DefaultClaimLease,
+		wake:           make(chan struct{}, 1),
+		queued:         map[uuid.UUID]bool{},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.client == nil {
+		d.client = publicClient(DefaultTimeout)
+		if d.privateNetworks {
+			d.client = &http.Client{Timeout: DefaultTimeout}
+		}
+	}
+	d.jobs = make(chan PendingDelivery, d.workers*16)
+	return d
+}
+
+// Run sends the pending deliveries as they fall due, until the context is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		d.poll(ctx)
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Publish persists a pending delivery of the event for every active subscription it matches.
+// It makes the dispatcher an outbox.Publisher, the event being relayed again when they can't be persisted.
+func (d *Dispatcher) Publish(ctx context.Context, e events.Event) error {
+	subs, err := d.r.GetActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	orgID := organization(e)
+	var pds []PendingDelivery
+	for _, sub := range subs {
+		if sub.Matches(e.Type, orgID) {
+			pds = append(pds, PendingDelivery{
+				ID:             pendingID(sub.ID, e.Key),
+				SubscriptionID: sub.ID,
+				OrganizationID: sub.OrganizationID,
+				EventKey:       e.Key,
+				EventType:      e.Type,
+				Payload:        string(payload),
+				NextAttemptAt:  now,
+				CreatedAt:      now,
+			})
+		}
+	}
+	if err := d.r.CreatePendingDeliveries(ctx, pds); err != nil {
+		return err
+	}
+	if len(pds) > 0 {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// pendingID returns the ID of the pending delivery of an event to a subscription,
+// the same when the event is published again.
+func pendingID(subscriptionID uuid.UUID, eventKey string) uuid.UUID {
+	if eventKey == "" {
+		return uuid.New()
+	}
+	return uuid.NewSHA1(subscriptionID, []byte(eventKey))
+}
+
+// organization returns the organizationId of the event's data, empty for the shared resources.
+func organization(e events.Event) string {
+	raw, ok := e.Data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(e.Data); err != nil {
+			return ""
+		}
+	}
+	var data struct {
+		OrganizationID string `json:"organizationId"`
+	}
+	_ = json.Unmarshal(raw, &data)
+	return data.OrganizationID
+}
+
+// poll queues the due pending deliveries which aren't queued yet, as many as the queue holds.
+func (d *Dispatcher) poll(ctx context.Context) {
+	pds, err := d.r.GetDuePendingDeliveries(ctx, time.Now().UTC(), cap(d.jobs))
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Unable to read the pending webhook deliveries: %+v\n", err)
+		}
+		return
+	}
+	for _, pd := range pds {
+		d.mu.Lock()
+		queued := d.queued[pd.ID]
+		d.queued[pd.ID] = true
+		d.mu.Unlock()
+		if queued {
+			continue
+		}
+		select {
+		case d.jobs <- pd:
+		case <-ctx.Done():
+			return
+		default:
+			// The queue is full, the delivery is read again on the next poll
+			d.dequeued(pd.ID)
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) dequeued(id uuid.UUID) {
+	d.mu.Lock()
+	delete(d.queued, id)
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case pd := <-d.jobs:
+			d.process(ctx, pd)
+			d.dequeued(pd.ID)
+		}
+	}
+}
+
+// process claims the pending delivery and makes an attempt at delivering it, deleting it once delivered.
+// On failure, the next attempt is scheduled after the backoff, or the event is dead-lettered.
+func (d *Dispatcher) process(ctx context.Context, pd PendingDelivery) {
+	pd, claimed, err := d.r.ClaimPendingDelivery(ctx, pd, time.Now().UTC().Add(d.claimLease))
+	if err != nil {
+		log.Printf("Unable to claim delivery %s of webhook %s: %+v\n", pd.ID, pd.SubscriptionID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// The subscription is read again, as its URL or secret may have changed since the event was published
+	sub, err := d.r.GetSingleSubscription(org.NewContext(ctx, pd.OrganizationID), pd.SubscriptionID)
+	if errors.Is(err, ErrNotFound) || (err == nil && !sub.Active) {
+		d.delete(ctx, pd)
+		return
+	}
+	if err != nil {
+		log.Printf("Unable to read webhook %s: %+v\n", pd.SubscriptionID, err)
+		return
+	}
+
+	j := job{
+		sub:     sub,
+		event:   events.Event{Type: pd.EventType, Key: pd.EventKey},
+		payload: []byte(pd.Payload),
+		attempt: int(pd.Attempt) + 1,
+	}
+	delivery := d.attempt(ctx, j)
+	if ctx.Err() != nil {
+		// The claim lapses and the attempt is made again
+		return
+	}
+	if delivery.Succeeded {
+		d.delete(ctx, pd)
+		return
+	}
+	if j.attempt >= d.maxAttempts {
+		dl := DeadLetter{
+			ID:             uuid.New(),
+			SubscriptionID: sub.ID,
+			EventKey:       pd.EventKey,
+			EventType:      pd.EventType,
+			Payload:        pd.Payload,
+			Attempts:       int64(j.attempt),
+			LastError:      delivery.Error,
+			CreatedAt:      time.Now().UTC(),
+		}
+		if err := d.r.CreateDeadLetter(ctx, dl); err != nil {
+			log.Printf("Unable to dead-letter event %s for webhook %s: %+v\n", pd.EventKey, sub.ID, err)
+			return
+		}
+		d.delete(ctx, pd)
+		return
+	}
+
+	pd.Attempt = int64(j.attempt)
+	pd.NextAttemptAt = time.Now().UTC().Add(d.backoff(j.attempt + 1))
+	if err := d.r.SavePendingDelivery(ctx, pd); err != nil {
+		log.Printf("Unable to schedule the retry of delivery %s of webhook %s: %+v\n", pd.ID, sub.ID, err)
+	}
+}
+
+func (d *Dispatcher) delete(ctx context.Context, pd PendingDelivery) {
+	if err := d.r.DeletePendingDelivery(ctx, pd.ID); err != nil {
+		log.Printf("Unable to delete delivery %s of webhook %s, it will be sent again: %+v\n", pd.ID, pd.SubscriptionID, err)
+	}
+}
+
+// backoff returns the delay before the attempt, doubling from the initial backoff for the second attempt.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.initialBackoff
+	for i := 2; i < attempt; i++ {
+		delay *= 2
+		if delay >= d.maxBackoff {
+			return d.maxBackoff
+		}
+	}
+	return delay
+}
+
+// Deliver makes a single attempt at delivering the event to the subscription, without retrying it.
+func (d *Dispatcher) Deliver(ctx context.Context, sub Subscription, e events.Event) Delivery {
+	return d.attempt(ctx, job{sub: sub, event: e, attempt: 1})
+}
+
+// attempt sends the job's event and records the attempt in the delivery log.
+func (d *Dispatcher) attempt(ctx context.Context, j job) Delivery {
+	delivery := Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: j.sub.ID,
+		EventKey:       j.event.Key,
+		EventType:      j.event.Type,
+		Attempt:        int64(j.attempt),
+		CreatedAt:      time.Now().UTC(),
+	}
+	payload := j.payload
+	if payload == nil {
+		var err error
+		if payload, err = json.Marshal(j.event); err != nil {
+			delivery.Error = err.Error()
+			return delivery
+		}
+	}
+
+	begin := time.Now()
+	status, err := d.send(ctx, j.sub, delivery.ID, j.event, payload)
+	delivery.DurationMs = time.Since(begin).Milliseconds()
+	delivery.StatusCode = int64(status)
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		delivery.Succeeded = true
+	}
+
+	if err := d.r.CreateDelivery(ctx, delivery); err != nil {
+		log.Printf("Unable to log delivery %s of webhook %s: %+v\n", delivery.ID, j.sub.ID, err)
+	}
+	return delivery
+}
+
+// send posts the signed payload to the subscription URL, failing on any non-2xx status code.
+func (d *Dispatcher) send(ctx context.Context, sub Subscription, deliveryID uuid.UUID, e events.Event, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tigris-go-explore-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", sub.ID.String())
+	req.Header.Set("X-Webhook-Delivery", deliveryID.String())
+	req.Header.Set("X-Webhook-Event", e.Type)
+	if e.Key != "" {
+		req.Header.Set("X-Webhook-Event-Key", e.Key)
+	}
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, time.Now(), payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
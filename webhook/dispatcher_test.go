@@ -0,0 +1,465 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/events"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+// memoryRepository is a Repository keeping everything in memory, for the tests.
+type memoryRepository struct {
+	mu            sync.Mutex
+	subscriptions map[uuid.UUID]Subscription
+	pending       map[uuid.UUID]PendingDelivery
+	deliveries    []Delivery
+	deadLetters   []DeadLetter
+}
+
+func newMemoryRepository(subs ...Subscription) *memoryRepository {
+	r := &memoryRepository{
+		subscriptions: map[uuid.UUID]Subscription{},
+		pending:       map[uuid.UUID]PendingDelivery{},
+	}
+	for _, sub := range subs {
+		r.subscriptions[sub.ID] = sub
+	}
+	return r
+}
+
+func (r *memoryRepository) GetAllSubscriptions(ctx context.Context, qp params.PaginationQueryParams) ([]Subscription, *pagination.PaginationData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subs := []Subscription{}
+	for _, sub := range r.subscriptions {
+		if sub.OrganizationID == org.FromContext(ctx) {
+			subs = append(subs, sub)
+		}
+	}
+	m := pagination.New(1, int64(len(subs)), int64(len(subs)))
+	return subs, &m, nil
+}
+
+func (r *memoryRepository) GetActiveSubscriptions(ctx context.Context) ([]Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subs := []Subscription{}
+	for _, sub := range r.subscriptions {
+		if sub.Active {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *memoryRepository) GetSingleSubscription(ctx context.Context, id uuid.UUID) (Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub, ok := r.subscriptions[id]
+	if !ok || sub.OrganizationID != org.FromContext(ctx) {
+		return Subscription{}, ErrNotFound
+	}
+	return sub, nil
+}
+
+func (r *memoryRepository) CreateSingleSubscription(ctx context.Context, sub Subscription) (Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions[sub.ID] = sub
+	return sub, nil
+}
+
+func (r *memoryRepository) UpdateSingleSubscription(ctx context.Context, id uuid.UUID, dto UpdateSubscription) (Subscription, error) {
+	sub, err := r.GetSingleSubscription(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if dto.URL != "" {
+		sub.URL = dto.URL
+	}
+	if dto.Active != nil {
+		sub.Active = *dto.Active
+	}
+	r.subscriptions[id] = sub
+	return sub, nil
+}
+
+func (r *memoryRepository) DeleteSingleSubscription(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.GetSingleSubscription(ctx, id); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subscriptions, id)
+	return nil
+}
+
+func (r *memoryRepository) GetSubscriptionDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deliveries := []Delivery{}
+	for _, d := range r.deliveries {
+		if d.SubscriptionID == id {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *memoryRepository) CreateDelivery(ctx context.Context, d Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+func (r *memoryRepository) GetDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]DeadLetter{}, r.deadLetters...), nil
+}
+
+func (r *memoryRepository) CreateDeadLetter(ctx context.Context, dl DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deadLetters = append(r.deadLetters, dl)
+	return nil
+}
+
+func (r *memoryRepository) CreatePendingDeliveries(ctx context.Context, pds []PendingDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, pd := range pds {
+		if _, ok := r.pending[pd.ID]; !ok {
+			r.pending[pd.ID] = pd
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) GetDuePendingDeliveries(ctx context.Context, now time.Time, limit int) ([]PendingDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pds := []PendingDelivery{}
+	for _, pd := range r.pending {
+		if !pd.NextAttemptAt.After(now) {
+			pds = append(pds, pd)
+		}
+	}
+	sort.Slice(pds, func(i, j int) bool { return pds[i].NextAttemptAt.Before(pds[j].NextAttemptAt) })
+	if len(pds) > limit {
+		pds = pds[:limit]
+	}
+	return pds, nil
+}
+
+func (r *memoryRepository) ClaimPendingDelivery(ctx context.Context, pd PendingDelivery, until time.Time) (PendingDelivery, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.pending[pd.ID]
+	if !ok || current.Attempt != pd.Attempt || !current.NextAttemptAt.Equal(pd.NextAttemptAt) {
+		return pd, false, nil
+	}
+	current.NextAttemptAt = until
+	r.pending[pd.ID] = current
+	return current, true, nil
+}
+
+func (r *memoryRepository) SavePendingDelivery(ctx context.Context, pd PendingDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[pd.ID] = pd
+	return nil
+}
+
+func (r *memoryRepository) DeletePendingDelivery(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, id)
+	return nil
+}
+
+func (r *memoryRepository) counts() (pending, deliveries, deadLetters int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending), len(r.deliveries), len(r.deadLetters)
+}
+
+// receiver is a webhook receiver answering the statuses in turn, then 200 OK.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+	at     time.Time
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, receivedRequest{header: r.Header.Clone(), body: body, at: time.Now()})
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) received() []receivedRequest {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]receivedRequest{}, rc.requests...)
+}
+
+// startDispatcher runs a dispatcher polling the repository every few milliseconds, until the test ends.
+func startDispatcher(t *testing.T, r Repository, srv *httptest.Server, opts ...DispatcherOption) *Dispatcher {
+	t.Helper()
+	opts = append([]DispatcherOption{
+		WithHTTPClient(srv.Client()),
+		WithPollInterval(5 * time.Millisecond),
+		WithBackoff(20*time.Millisecond, time.Second),
+	}, opts...)
+	d := NewDispatcher(r, opts...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return d
+}
+
+// eventually fails the test when the condition isn't met within a few seconds.
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 5s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newEvent() events.Event {
+	return events.Event{
+		ID:      1,
+		Key:     uuid.NewString(),
+		Type:    "breed.created",
+		Subject: "42",
+		Time:    time.Now().UTC(),
+		Data:    json.RawMessage(`{"id":42,"name":"Beagle","organizationId":""}`),
+	}
+}
+
+func TestDispatcherDeliversSignedEvents(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	sub := Subscription{ID: uuid.New(), URL: srv.URL, Secret: "secret", Active: true}
+	r := newMemoryRepository(sub)
+	d := startDispatcher(t, r, srv)
+
+	e := newEvent()
+	if err := d.Publish(context.Background(), e); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+	eventually(t, func() bool {
+		pending, deliveries, _ := r.counts()
+		return pending == 0 && deliveries == 1
+	})
+
+	requests := rc.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+	want, _ := json.Marshal(e)
+	if string(req.body) != string(want) {
+		t.Errorf("body = %s, want %s", req.body, want)
+	}
+	if err := Verify("secret", req.header.Get(SignatureHeader), req.body, time.Minute); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+	if got := req.header.Get("X-Webhook-Event"); got != e.Type {
+		t.Errorf("X-Webhook-Event = %q, want %q", got, e.Type)
+	}
+	if got := req.header.Get("X-Webhook-Event-Key"); got != e.Key {
+		t.Errorf("X-Webhook-Event-Key = %q, want %q", got, e.Key)
+	}
+	if got := req.header.Get("X-Webhook-ID"); got != sub.ID.String() {
+		t.Errorf("X-Webhook-ID = %q, want %q", got, sub.ID)
+	}
+}
+
+func TestDispatcherPublishPersistsMatchingDeliveriesOnce(t *testing.T) {
+	matching := Subscription{ID: uuid.New(), URL: "https://a.example.com", OrganizationID: "acme", Events: []string{"breed.created"}, Active: true}
+	otherType := Subscription{ID: uuid.New(), URL: "https://b.example.com", Events: []string{"breed.deleted"}, Active: true}
+	inactive := Subscription{ID: uuid.New(), URL: "https://c.example.com", Active: false}
+	otherOrg := Subscription{ID: uuid.New(), URL: "https://d.example.com", OrganizationID: "globex", Active: true}
+	r := newMemoryRepository(matching, otherType, inactive, otherOrg)
+	d := NewDispatcher(r)
+
+	e := newEvent()
+	e.Data = json.RawMessage(`{"id":42,"organizationId":"acme"}`)
+
+	// The outbox relays an event again when another publisher failed
+	for i := 0; i < 2; i++ {
+		if err := d.Publish(context.Background(), e); err != nil {
+			t.Fatalf("Publish() = %v", err)
+		}
+	}
+
+	if len(r.pending) != 1 {
+		t.Fatalf("%d pending deliveries, want 1", len(r.pending))
+	}
+	for _, pd := range r.pending {
+		if pd.SubscriptionID != matching.ID || pd.OrganizationID != "acme" || pd.EventKey != e.Key || pd.Attempt != 0 {
+			t.Errorf("pending delivery = %+v, want the first attempt of the event to the matching subscription", pd)
+		}
+	}
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusInternalServerError}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	sub := Subscription{ID: uuid.New(), URL: srv.URL, Secret: "secret", Active: true}
+	r := newMemoryRepository(sub)
+	d := startDispatcher(t, r, srv, WithMaxAttempts(5))
+
+	if err := d.Publish(context.Background(), newEvent()); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+	eventually(t, func() bool {
+		pending, deliveries, _ := r.counts()
+		return pending == 0 && deliveries == 3
+	})
+
+	requests := rc.received()
+	if len(requests) != 3 {
+		t.Fatalf("received %d requests, want 3", len(requests))
+	}
+	// The delay doubles from the initial backoff
+	for i, min := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond} {
+		if gap := requests[i+1].at.Sub(requests[i].at); gap < min {
+			t.Errorf("attempt %d sent %v after the previous one, want at least %v", i+2, gap, min)
+		}
+	}
+
+	deliveries, _ := r.GetSubscriptionDeliveries(context.Background(), sub.ID, DefaultLogLimit)
+	for i, delivery := range deliveries {
+		if delivery.Attempt != int64(i+1) {
+			t.Errorf("delivery %d attempt = %d, want %d", i, delivery.Attempt, i+1)
+		}
+		if succeeded := i == 2; delivery.Succeeded != succeeded {
+			t.Errorf("delivery %d succeeded = %v, want %v", i, delivery.Succeeded, succeeded)
+		}
+	}
+	if _, _, deadLetters := r.counts(); deadLetters != 0 {
+		t.Errorf("%d dead letters, want 0", deadLetters)
+	}
+}
+
+func TestDispatcherDeadLetters(t *testing.T) {
+	rc := &receiver{statuses: []int{500, 500, 500, 500}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	sub := Subscription{ID: uuid.New(), URL: srv.URL, Secret: "secret", Active: true}
+	r := newMemoryRepository(sub)
+	d := startDispatcher(t, r, srv, WithMaxAttempts(3))
+
+	e := newEvent()
+	if err := d.Publish(context.Background(), e); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+	eventually(t, func() bool {
+		pending, _, deadLetters := r.counts()
+		return pending == 0 && deadLetters == 1
+	})
+
+	if got := len(rc.received()); got != 3 {
+		t.Errorf("received %d requests, want 3", got)
+	}
+	dl := r.deadLetters[0]
+	want, _ := json.Marshal(e)
+	if dl.SubscriptionID != sub.ID || dl.EventKey != e.Key || dl.Attempts != 3 || dl.Payload != string(want) {
+		t.Errorf("dead letter = %+v, want the 3 attempts of the event", dl)
+	}
+	if dl.LastError != "unexpected status code 500" {
+		t.Errorf("dead letter last error = %q, want the status code", dl.LastError)
+	}
+}
+
+func TestDispatcherDropsDeliveriesOfDeletedSubscriptions(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	sub := Subscription{ID: uuid.New(), URL: srv.URL, Secret: "secret", Active: true}
+	r := newMemoryRepository(sub)
+	d := NewDispatcher(r, WithHTTPClient(srv.Client()), WithPollInterval(5*time.Millisecond))
+	if err := d.Publish(context.Background(), newEvent()); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+	if err := r.DeleteSingleSubscription(context.Background(), sub.ID); err != nil {
+		t.Fatalf("DeleteSingleSubscription() = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+	eventually(t, func() bool {
+		pending, _, _ := r.counts()
+		return pending == 0
+	})
+	if got := len(rc.received()); got != 0 {
+		t.Errorf("received %d requests, want 0", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(newMemoryRepository(), WithBackoff(time.Second, 10*time.Second))
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 2, want: time.Second},
+		{attempt: 3, want: 2 * time.Second},
+		{attempt: 4, want: 4 * time.Second},
+		{attempt: 5, want: 8 * time.Second},
+		{attempt: 6, want: 10 * time.Second},
+		{attempt: 20, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
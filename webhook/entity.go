@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription struct
+//
+// A subscription receives the events of the listed types, or all of them when Events is empty.
+// The secret signs the deliveries and is only returned when the subscription is created.
+// A subscription belongs to the organization it was created for, and only receives the events of the breeds
+// it sees: the shared ones, and its own.
+type Subscription struct {
+	ID             uuid.UUID `json:"id" tigris:"primaryKey:1" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	OrganizationID string    `json:"organizationId" tigris:"index" example:"acme"`
+	URL            string    `json:"url" example:"https://partner.example.com/hooks/breeds"`
+	Events         []string  `json:"events" example:"breed.created,breed.deleted"`
+	Description    string    `json:"description" example:"Partner catalogue sync"`
+	Secret         string    `json:"secret,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Active         bool      `json:"active" tigris:"index" example:"true"`
+	CreatedAt      time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt      time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// Matches reports whether the subscription receives the events of the type, about a resource of the organization,
+// the shared resources having none.
+func (s Subscription) Matches(eventType, organizationID string) bool {
+	if !s.Active {
+		return false
+	}
+	if organizationID != "" && organizationID != s.OrganizationID {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, t := range s.Events {
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateSubscription struct
+type CreateSubscription struct {
+	URL         string    `json:"url" validate:"required,url,startswith=http" example:"https://partner.example.com/hooks/breeds"`
+	Events      []string  `json:"events" validate:"omitempty,dive,oneof=* breed.created breed.updated breed.renamed breed.deleted" example:"breed.created,breed.deleted"`
+	Description string    `json:"description" validate:"omitempty,max=255" example:"Partner catalogue sync"`
+	Active      *bool     `json:"active" example:"true"`
+	CreatedAt   time.Time `json:"createdAt" example:"2023-01-05T00:00:00.000Z"`
+	UpdatedAt   time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// UpdateSubscription struct
+type UpdateSubscription struct {
+	URL         string    `json:"url" validate:"omitempty,url,startswith=http" example:"https://partner.example.com/hooks/breeds"`
+	Events      []string  `json:"events" validate:"omitempty,dive,oneof=* breed.created breed.updated breed.renamed breed.deleted" example:"breed.created,breed.deleted"`
+	Description *string   `json:"description" validate:"omitempty,max=255" example:"Partner catalogue sync"`
+	Active      *bool     `json:"active" example:"false"`
+	UpdatedAt   time.Time `json:"updatedAt" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// Delivery struct
+//
+// A delivery is a single attempt at sending an event to a subscription, kept as the delivery log.
+// The events relayed from the outbox are identified by their EventKey, EventID is only set
+// on the deliveries logged before.
+type Delivery struct {
+	ID             uuid.UUID `json:"id" tigris:"primaryKey:1" example:"0b6f3f5e-2a6e-4c4e-9d3b-1b1e6a7e4c4e"`
+	SubscriptionID uuid.UUID `json:"subscriptionId" tigris:"index" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	EventID        int64     `json:"eventId,omitempty" example:"42"`
+	EventKey       string    `json:"eventKey" example:"2c1f3a4b-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	EventType      string    `json:"eventType" example:"breed.created"`
+	Attempt        int64     `json:"attempt" example:"1"`
+	StatusCode     int64     `json:"statusCode" example:"200"`
+	Error          string    `json:"error,omitempty" example:"unexpected status code 503"`
+	Succeeded      bool      `json:"succeeded" example:"true"`
+	DurationMs     int64     `json:"durationMs" example:"87"`
+	CreatedAt      time.Time `json:"createdAt" tigris:"index" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// DeadLetter struct
+//
+// A dead letter is an event which couldn't be delivered to a subscription within the maximum number of attempts.
+// As for deliveries, EventKey identifies the event and EventID is only set on the older dead letters.
+type DeadLetter struct {
+	ID             uuid.UUID `json:"id" tigris:"primaryKey:1" example:"7a1c2d3e-2a6e-4c4e-9d3b-1b1e6a7e4c4e"`
+	SubscriptionID uuid.UUID `json:"subscriptionId" tigris:"index" example:"5d3b1b1e-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	EventID        int64     `json:"eventId,omitempty" example:"42"`
+	EventKey       string    `json:"eventKey" example:"2c1f3a4b-6a7e-4c4e-9d3b-1b1e6a7e4c4e"`
+	EventType      string    `json:"eventType" example:"breed.created"`
+	Payload        string    `json:"payload" example:"{\"id\":42,\"type\":\"breed.created\"}"`
+	Attempts       int64     `json:"attempts" example:"8"`
+	LastError      string    `json:"lastError" example:"unexpected status code 503"`
+	CreatedAt      time.Time `json:"createdAt" tigris:"index" example:"2023-01-05T00:00:00.000Z"`
+}
+
+// PendingDelivery struct
+//
+// A pending delivery is an event accepted from the outbox and not yet delivered to a subscription.
+// It is kept until the event is delivered or dead-lettered, so the retries survive a restart.
+// Attempt is the number of attempts already made, and NextAttemptAt when the next one is due.
+type PendingDelivery struct {
+	ID             uuid.UUID `json:"id" tigris:"primaryKey:1"`
+	SubscriptionID uuid.UUID `json:"subscriptionId" tigris:"index"`
+	OrganizationID string    `json:"organizationId"`
+	EventKey       string    `json:"eventKey"`
+	EventType      string    `json:"eventType"`
+	Payload        string    `json:"payload"`
+	Attempt        int64     `json:"attempt"`
+	NextAttemptAt  time.Time `json:"nextAttemptAt" tigris:"index"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/simply-alliv/tigris-go-explore/pkg/crud"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/validation"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+func GetAllSubscriptions(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qp := crud.PaginationQueryParams(r)
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /webhooks - PaginationQueryParams: %+v - Took: %v\n", qp, time.Since(begin))
+		}(time.Now())
+		data, metadata, err := s.GetAllSubscriptions(r.Context(), qp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pagination.SetLinks(w, r, metadata)
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data, Metadata: metadata})
+	}
+}
+
+func GetSingleSubscription(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := subscriptionID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /webhooks/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.GetSingleSubscription(r.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func CreateSingleSubscription(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var dto CreateSubscription
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		now := time.Now().UTC()
+		dto.CreatedAt = now
+		dto.UpdatedAt = now
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /webhooks - URL: %s - Took: %v\n", dto.URL, time.Since(begin))
+		}(time.Now())
+		data, err := s.CreateSingleSubscription(r.Context(), dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusCreated, Message: "success", Data: data})
+	}
+}
+
+func UpdateSingleSubscription(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := subscriptionID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		var dto UpdateSubscription
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			writeResponse(w, Response{Status: http.StatusUnprocessableEntity, Message: "invalid request body"})
+			return
+		}
+		dto.UpdatedAt = time.Now().UTC()
+		if err := validation.Struct(dto); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("PATCH /webhooks/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.UpdateSingleSubscription(r.Context(), id, dto)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func DeleteSingleSubscription(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := subscriptionID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("DELETE /webhooks/%s - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		if err := s.DeleteSingleSubscription(r.Context(), id); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success"})
+	}
+}
+
+func TestSubscription(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := subscriptionID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("POST /webhooks/%s/test - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.TestSubscription(r.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func GetSubscriptionDeliveries(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := subscriptionID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer func(begin time.Time) {
+			fmt.Printf("GET /webhooks/%s/deliveries - Took: %v\n", id, time.Since(begin))
+		}(time.Now())
+		data, err := s.GetSubscriptionDeliveries(r.Context(), id, logLimit(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+func GetDeadLetters(s *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func(begin time.Time) {
+			fmt.Printf("GET /webhooks/dead-letters - Took: %v\n", time.Since(begin))
+		}(time.Now())
+		data, err := s.GetDeadLetters(r.Context(), logLimit(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, Response{Status: http.StatusOK, Message: "success", Data: data})
+	}
+}
+
+// subscriptionID parses the `id` path variable, an unknown ID being reported as not found.
+func subscriptionID(r *http.Request) (uuid.UUID, error) {
+	v, ok := mux.Vars(r)["id"]
+	if !ok {
+		panic(ErrBadRouting)
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return uuid.Nil, ErrNotFound
+	}
+	return id, nil
+}
+
+// logLimit reads the `limit` query param of the delivery log and dead letters, capped at 500.
+func logLimit(r *http.Request) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		return DefaultLogLimit
+	}
+	if limit > 500 {
+		return 500
+	}
+	return limit
+}
+
+// writeError maps the error to its HTTP status code and writes it as the response.
+func writeError(w http.ResponseWriter, err error) {
+	var status int
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNoOrganization):
+		status = http.StatusUnauthorized
+	case validation.IsValidationError(err), errors.Is(err, ErrForbiddenURL):
+		status = http.StatusUnprocessableEntity
+	default:
+		log.Printf("Unable to handle webhook request: %+v\n", err)
+		status = http.StatusInternalServerError
+	}
+	writeResponse(w, Response{Status: status, Message: err.Error()})
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	// set the content type to application/json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
+
+	// encode the response struct as JSON and write it to the response writer
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		// handle the error
+		http.Error(w, "error encoding JSON response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenURL is returned when a subscription URL resolves to a loopback, link-local, private or reserved address.
+var ErrForbiddenURL = errors.New("webhook URL must resolve to a public address")
+
+var (
+	// thisNetwork is the 0.0.0.0/8 range, which some stacks route to the server itself.
+	thisNetwork = &net.IPNet{IP: net.IPv4(0, 0, 0, 0), Mask: net.CIDRMask(8, 32)}
+	// sharedAddressSpace is the carrier-grade NAT range, private although net.IP.IsPrivate doesn't report it.
+	sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+	// nat64 is the well-known NAT64 prefix, whose addresses are translated to the IPv4 address embedded in their last 32 bits.
+	nat64 = &net.IPNet{IP: net.ParseIP("64:ff9b::"), Mask: net.CIDRMask(96, 128)}
+	// localNAT64 is the local-use NAT64 prefix, translated by the network's own gateways to any of its addresses.
+	localNAT64 = &net.IPNet{IP: net.ParseIP("64:ff9b:1::"), Mask: net.CIDRMask(48, 128)}
+)
+
+// public reports whether the IP is reachable on the internet, rather than the server itself or its network,
+// e.g. the 169.254.169.254 metadata endpoint of the cloud providers.
+// The IPv4-mapped and NAT64 addresses are checked for the IPv4 address they embed.
+func public(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	} else if nat64.Contains(ip) {
+		ip = ip[net.IPv6len-net.IPv4len:]
+	}
+	return !(localNAT64.Contains(ip) ||
+		thisNetwork.Contains(ip) ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+// CheckURL resolves the host of a subscription URL, failing with ErrForbiddenURL when any of its
+// addresses isn't public, unless the dispatcher allows the private networks.
+func (d *Dispatcher) CheckURL(ctx context.Context, rawURL string) error {
+	if d.privateNetworks {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrForbiddenURL, err)
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !public(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenURL, host)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w: unable to resolve %s", ErrForbiddenURL, host)
+	}
+	for _, addr := range addrs {
+		if !public(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenURL, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// publicClient returns a client which only connects to public addresses, checked once resolved,
+// so a host resolving to another address after its subscription was checked, or a redirect, is refused too.
+func publicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !public(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenURL, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the receiver, bypassing the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/events"
+)
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://93.184.216.34/hooks", wantErr: false},
+		{url: "https://[2606:2800:220:1:248:1893:25c8:1946]/hooks", wantErr: false},
+		{url: "http://127.0.0.1:8080/hooks", wantErr: true},
+		{url: "http://localhost/hooks", wantErr: true},
+		{url: "http://[::1]/hooks", wantErr: true},
+		{url: "http://10.0.0.12/hooks", wantErr: true},
+		{url: "http://172.16.3.4/hooks", wantErr: true},
+		{url: "http://192.168.1.1/hooks", wantErr: true},
+		{url: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{url: "http://100.64.0.1/hooks", wantErr: true},
+		{url: "http://0.0.0.0/hooks", wantErr: true},
+		{url: "http://[fe80::1]/hooks", wantErr: true},
+		{url: "http://[fd00::1]/hooks", wantErr: true},
+		{url: "http://[::ffff:127.0.0.1]/hooks", wantErr: true},
+		{url: "http://[::ffff:10.0.0.12]/hooks", wantErr: true},
+		{url: "http://[::ffff:169.254.169.254]/hooks", wantErr: true},
+		{url: "http://[::ffff:5db8:d822]/hooks", wantErr: false},
+		{url: "http://0.1.2.3/hooks", wantErr: true},
+		{url: "http://[::ffff:0.1.2.3]/hooks", wantErr: true},
+		{url: "http://[64:ff9b::7f00:1]/hooks", wantErr: true},
+		{url: "http://[64:ff9b::10.0.0.12]/hooks", wantErr: true},
+		{url: "http://[64:ff9b::a9fe:a9fe]/hooks", wantErr: true},
+		{url: "http://[64:ff9b::93.184.216.34]/hooks", wantErr: false},
+		{url: "http://[64:ff9b:1::5db8:d822]/hooks", wantErr: true},
+	}
+	d := NewDispatcher(newMemoryRepository())
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := d.CheckURL(context.Background(), tt.url)
+			if tt.wantErr && !errors.Is(err, ErrForbiddenURL) {
+				t.Errorf("CheckURL() = %v, want ErrForbiddenURL", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("CheckURL() = %v, want nil", err)
+			}
+		})
+	}
+
+	t.Run("private networks allowed", func(t *testing.T) {
+		d := NewDispatcher(newMemoryRepository(), WithPrivateNetworks())
+		if err := d.CheckURL(context.Background(), "http://127.0.0.1:8080/hooks"); err != nil {
+			t.Errorf("CheckURL() = %v, want nil", err)
+		}
+	})
+}
+
+func TestDeliverRefusesPrivateAddresses(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	// The default client refuses the loopback address of the server when dialing it
+	d := NewDispatcher(newMemoryRepository())
+	sub := Subscription{ID: uuid.New(), URL: srv.URL, Secret: "secret", Active: true}
+	delivery := d.Deliver(context.Background(), sub, events.Event{Type: PingEvent})
+	if delivery.Succeeded || called {
+		t.Fatalf("Deliver() succeeded = %v, server called = %v, want the delivery refused", delivery.Succeeded, called)
+	}
+	if !strings.Contains(delivery.Error, ErrForbiddenURL.Error()) {
+		t.Errorf("Deliver() error = %q, want %q", delivery.Error, ErrForbiddenURL)
+	}
+}
@@ -0,0 +1,319 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+	"github.com/tigrisdata/tigris-client-go/fields"
+	"github.com/tigrisdata/tigris-client-go/filter"
+	"github.com/tigrisdata/tigris-client-go/sort"
+	"github.com/tigrisdata/tigris-client-go/tigris"
+)
+
+var (
+	// ErrNotFound is returned when no webhook subscription matches the given ID.
+	ErrNotFound = errors.New("webhook subscription not found")
+	// ErrNoOrganization is returned when the subscriptions are managed by a request which isn't made for an organization.
+	ErrNoOrganization = errors.New("webhook subscriptions are managed by an organization, send its token")
+)
+
+// Repository is responsible for persisting the webhook subscriptions, their pending deliveries,
+// delivery log and dead letters.
+//
+// The subscriptions are scoped to the organization carried by the context, see the org package,
+// but the active ones which are read for all the organizations to dispatch the events.
+type Repository interface {
+	GetAllSubscriptions(ctx context.Context, qp params.PaginationQueryParams) ([]Subscription, *pagination.PaginationData, error)
+	GetActiveSubscriptions(ctx context.Context) ([]Subscription, error)
+	GetSingleSubscription(ctx context.Context, id uuid.UUID) (Subscription, error)
+	CreateSingleSubscription(ctx context.Context, sub Subscription) (Subscription, error)
+	UpdateSingleSubscription(ctx context.Context, id uuid.UUID, dto UpdateSubscription) (Subscription, error)
+	DeleteSingleSubscription(ctx context.Context, id uuid.UUID) error
+	GetSubscriptionDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]Delivery, error)
+	CreateDelivery(ctx context.Context, d Delivery) error
+	GetDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error)
+	CreateDeadLetter(ctx context.Context, dl DeadLetter) error
+	CreatePendingDeliveries(ctx context.Context, pds []PendingDelivery) error
+	GetDuePendingDeliveries(ctx context.Context, now time.Time, limit int) ([]PendingDelivery, error)
+	ClaimPendingDelivery(ctx context.Context, pd PendingDelivery, until time.Time) (PendingDelivery, bool, error)
+	SavePendingDelivery(ctx context.Context, pd PendingDelivery) error
+	DeletePendingDelivery(ctx context.Context, id uuid.UUID) error
+}
+
+type webhookRepository struct {
+	db            *tigris.Database
+	subscriptions *tigris.Collection[Subscription]
+	pending       *tigris.Collection[PendingDelivery]
+	deliveries    *tigris.Collection[Delivery]
+	deadLetters   *tigris.Collection[DeadLetter]
+}
+
+// NewWebhookRepository returns a concrete implementation of the Repository interface.
+func NewWebhookRepository(db *tigris.Database) Repository {
+	return &webhookRepository{
+		db:            db,
+		subscriptions: tigris.GetCollection[Subscription](db),
+		pending:       tigris.GetCollection[PendingDelivery](db),
+		deliveries:    tigris.GetCollection[Delivery](db),
+		deadLetters:   tigris.GetCollection[DeadLetter](db),
+	}
+}
+
+func (r webhookRepository) GetAllSubscriptions(ctx context.Context, qp params.PaginationQueryParams) ([]Subscription, *pagination.PaginationData, error) {
+	var subs []Subscription = []Subscription{}
+	f := owned(ctx)
+
+	// Add initial pagination data
+	m := pagination.PaginationData{
+		Page:    int64(qp.Page),
+		PerPage: int64(qp.Limit),
+	}
+	c, err := r.subscriptions.Count(ctx, f)
+	if err != nil {
+		return subs, &m, err
+	}
+	if !qp.Paginate {
+		m.Page = 1
+		m.PerPage = c
+	}
+	options := tigris.ReadOptions{
+		Skip:  (m.Page - 1) * m.PerPage,
+		Limit: m.PerPage,
+	}
+	it, err := r.subscriptions.ReadWithOptions(ctx, f, fields.All, &options)
+	if err != nil {
+		return subs, &m, err
+	}
+	defer it.Close()
+
+	var sub Subscription
+	for it.Next(&sub) {
+		subs = append(subs, sub)
+	}
+
+	m = pagination.New(m.Page, m.PerPage, c)
+
+	return subs, &m, it.Err()
+}
+
+// GetActiveSubscriptions returns the active subscriptions of all the organizations.
+func (r webhookRepository) GetActiveSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return r.readSubscriptions(ctx, filter.Eq("active", true))
+}
+
+func (r webhookRepository) readSubscriptions(ctx context.Context, f filter.Filter) ([]Subscription, error) {
+	var subs []Subscription = []Subscription{}
+	it, err := r.subscriptions.Read(ctx, f, fields.All)
+	if err != nil {
+		return subs, err
+	}
+	defer it.Close()
+
+	var sub Subscription
+	for it.Next(&sub) {
+		subs = append(subs, sub)
+	}
+	return subs, it.Err()
+}
+
+func (r webhookRepository) GetSingleSubscription(ctx context.Context, id uuid.UUID) (Subscription, error) {
+	sub, err := r.subscriptions.ReadOne(ctx, filter.And(filter.Eq("id", id), owned(ctx)))
+	if errors.Is(err, tigris.ErrNotFound) {
+		return Subscription{}, ErrNotFound
+	}
+	if err != nil {
+		return Subscription{}, err
+	}
+	return *sub, nil
+}
+
+func (r webhookRepository) CreateSingleSubscription(ctx context.Context, sub Subscription) (Subscription, error) {
+	_, err := r.subscriptions.Insert(ctx, &sub)
+	return sub, err
+}
+
+func (r webhookRepository) UpdateSingleSubscription(ctx context.Context, id uuid.UUID, dto UpdateSubscription) (Subscription, error) {
+	update := fields.Update{}
+	set := map[string]interface{}{}
+	set["updatedAt"] = dto.UpdatedAt
+	if dto.URL != "" {
+		set["url"] = dto.URL
+	}
+	if dto.Events != nil {
+		set["events"] = dto.Events
+	}
+	if dto.Description != nil {
+		set["description"] = *dto.Description
+	}
+	if dto.Active != nil {
+		set["active"] = *dto.Active
+	}
+	update.SetF = set
+	if _, err := r.GetSingleSubscription(ctx, id); err != nil {
+		return Subscription{}, err
+	}
+	if _, err := r.subscriptions.UpdateOne(ctx, filter.Eq("id", id), &update); err != nil {
+		return Subscription{}, err
+	}
+	return r.GetSingleSubscription(ctx, id)
+}
+
+func (r webhookRepository) DeleteSingleSubscription(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.GetSingleSubscription(ctx, id); err != nil {
+		return err
+	}
+	_, err := r.subscriptions.DeleteOne(ctx, filter.Eq("id", id))
+	return err
+}
+
+// GetSubscriptionDeliveries returns the latest delivery attempts of a subscription, newest first.
+func (r webhookRepository) GetSubscriptionDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]Delivery, error) {
+	var deliveries []Delivery = []Delivery{}
+	options := tigris.ReadOptions{
+		Limit: int64(limit),
+		Sort:  sort.Descending("createdAt"),
+	}
+	it, err := r.deliveries.ReadWithOptions(ctx, filter.Eq("subscriptionId", id), fields.All, &options)
+	if err != nil {
+		return deliveries, err
+	}
+	defer it.Close()
+
+	var d Delivery
+	for it.Next(&d) {
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, it.Err()
+}
+
+func (r webhookRepository) CreateDelivery(ctx context.Context, d Delivery) error {
+	_, err := r.deliveries.Insert(ctx, &d)
+	return err
+}
+
+// GetDeadLetters returns the latest dead letters of the organization's subscriptions, newest first.
+func (r webhookRepository) GetDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	var deadLetters []DeadLetter = []DeadLetter{}
+	subs, err := r.readSubscriptions(ctx, owned(ctx))
+	if err != nil || len(subs) == 0 {
+		return deadLetters, err
+	}
+	ids := make([]filter.Expr, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, filter.Eq("subscriptionId", sub.ID))
+	}
+	f := ids[0]
+	if len(ids) > 1 {
+		f = filter.Or(ids...)
+	}
+
+	options := tigris.ReadOptions{
+		Limit: int64(limit),
+		Sort:  sort.Descending("createdAt"),
+	}
+	it, err := r.deadLetters.ReadWithOptions(ctx, f, fields.All, &options)
+	if err != nil {
+		return deadLetters, err
+	}
+	defer it.Close()
+
+	var dl DeadLetter
+	for it.Next(&dl) {
+		deadLetters = append(deadLetters, dl)
+	}
+	return deadLetters, it.Err()
+}
+
+func (r webhookRepository) CreateDeadLetter(ctx context.Context, dl DeadLetter) error {
+	_, err := r.deadLetters.Insert(ctx, &dl)
+	return err
+}
+
+// CreatePendingDeliveries inserts the pending deliveries in a transaction, skipping the ones
+// already pending, e.g. when the outbox relays an event again.
+func (r webhookRepository) CreatePendingDeliveries(ctx context.Context, pds []PendingDelivery) error {
+	if len(pds) == 0 {
+		return nil
+	}
+	return r.db.Tx(ctx, func(ctx context.Context) error {
+		for i := range pds {
+			_, err := r.pending.ReadOne(ctx, filter.Eq("id", pds[i].ID))
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, tigris.ErrNotFound) {
+				return err
+			}
+			if _, err := r.pending.Insert(ctx, &pds[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, tigris.TxOptions{AutoRetry: true})
+}
+
+// GetDuePendingDeliveries returns the pending deliveries whose next attempt is due, the most overdue first.
+func (r webhookRepository) GetDuePendingDeliveries(ctx context.Context, now time.Time, limit int) ([]PendingDelivery, error) {
+	var pds []PendingDelivery = []PendingDelivery{}
+	options := tigris.ReadOptions{
+		Limit: int64(limit),
+		Sort:  sort.Ascending("nextAttemptAt"),
+	}
+	it, err := r.pending.ReadWithOptions(ctx, filter.Lte("nextAttemptAt", now), fields.All, &options)
+	if err != nil {
+		return pds, err
+	}
+	defer it.Close()
+
+	var pd PendingDelivery
+	for it.Next(&pd) {
+		pds = append(pds, pd)
+	}
+	return pds, it.Err()
+}
+
+// ClaimPendingDelivery postpones the next attempt of the pending delivery until the given time, in a transaction,
+// unless it changed since it was read. Only one of the dispatchers reading the same pending delivery claims it.
+func (r webhookRepository) ClaimPendingDelivery(ctx context.Context, pd PendingDelivery, until time.Time) (PendingDelivery, bool, error) {
+	claimed := false
+	err := r.db.Tx(ctx, func(ctx context.Context) error {
+		current, err := r.pending.ReadOne(ctx, filter.Eq("id", pd.ID))
+		if errors.Is(err, tigris.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if current.Attempt != pd.Attempt || !current.NextAttemptAt.Equal(pd.NextAttemptAt) {
+			return nil
+		}
+		pd = *current
+		pd.NextAttemptAt = until
+		if _, err := r.pending.UpdateOne(ctx, filter.Eq("id", pd.ID), fields.UpdateBuilder().Set("nextAttemptAt", until)); err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	}, tigris.TxOptions{AutoRetry: true})
+	return pd, claimed, err
+}
+
+func (r webhookRepository) SavePendingDelivery(ctx context.Context, pd PendingDelivery) error {
+	_, err := r.pending.InsertOrReplace(ctx, &pd)
+	return err
+}
+
+func (r webhookRepository) DeletePendingDelivery(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pending.DeleteOne(ctx, filter.Eq("id", id))
+	return err
+}
+
+// owned returns the filter of the subscriptions of the organization of the context.
+func owned(ctx context.Context) filter.Expr {
+	return filter.Eq("organizationId", org.FromContext(ctx))
+}
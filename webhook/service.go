@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/events"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/pagination"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+// DefaultLogLimit is the number of deliveries and dead letters returned by default.
+const DefaultLogLimit = 50
+
+type Service struct {
+	r Repository
+	d *Dispatcher
+}
+
+// NewWebhookService returns a service
+func NewWebhookService(r Repository, d *Dispatcher) *Service {
+	return &Service{r: r, d: d}
+}
+
+// GetAllSubscriptions godoc
+// @Summary Get all webhook subscriptions
+// @Description Get all the webhook subscriptions of the organization, without their secrets
+// @Security Bearer
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Success 200 {object} JSONResultSuccess{data=[]Subscription} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /webhooks [get]
+func (s *Service) GetAllSubscriptions(ctx context.Context, qp params.PaginationQueryParams) ([]Subscription, *pagination.PaginationData, error) {
+	if err := requireOrganization(ctx); err != nil {
+		return nil, nil, err
+	}
+	subs, m, err := s.r.GetAllSubscriptions(ctx, qp)
+	if err != nil {
+		return nil, m, err
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, m, nil
+}
+
+// GetSingleSubscription godoc
+// @Summary Get single webhook subscription
+// @Description Get a single webhook subscription, without its secret
+// @Security Bearer
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the webhook subscription"
+// @Success 200 {object} JSONResultSuccess{data=Subscription} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /webhooks/{id} [get]
+func (s *Service) GetSingleSubscription(ctx context.Context, id uuid.UUID) (Subscription, error) {
+	if err := requireOrganization(ctx); err != nil {
+		return Subscription{}, err
+	}
+	sub, err := s.r.GetSingleSubscription(ctx, id)
+	sub.Secret = ""
+	return sub, err
+}
+
+// CreateSingleSubscription godoc
+// @Summary Create single webhook subscription
+// @Description Subscribe a URL to the breed events, the returned secret signs the deliveries and isn't shown again. The URL must resolve to a public address
+// @Security Bearer
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param body body CreateSubscription true "JSON body to create a webhook subscription"
+// @Success 201 {object} JSONResultSuccess{data=Subscription} "Created"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /webhooks [post]
+func (s *Service) CreateSingleSubscription(ctx context.Context, dto CreateSubscription) (Subscription, error) {
+	if err := requireOrganization(ctx); err != nil {
+		return Subscription{}, err
+	}
+	if err := s.d.CheckURL(ctx, dto.URL); err != nil {
+		return Subscription{}, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return Subscription{}, err
+	}
+	sub := Subscription{
+		ID:             uuid.New(),
+		OrganizationID: org.FromContext(ctx),
+		URL:            dto.URL,
+		Events:         dto.Events,
+		Description:    dto.Description,
+		Secret:         secret,
+		Active:         dto.Active == nil || *dto.Active,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+	}
+	if sub.Events == nil {
+		sub.Events = []string{}
+	}
+	return s.r.CreateSingleSubscription(ctx, sub)
+}
+
+// UpdateSingleSubscription godoc
+// @Summary Update single webhook subscription
+// @Description Update a single webhook subscription, e.g. to pause it by setting active to false
+// @Security Bearer
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the webhook subscription"
+// @Param body body UpdateSubscription true "JSON body to update a webhook subscription"
+// @Success 200 {object} JSONResultSuccess{data=Subscription} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 422 {object} JSONResultFailure "Error: Unprocessable Entity"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /webhooks/{id} [patch]
+func (s *Service) UpdateSingleSubscription(ctx context.Context, id uuid.UUID, dto UpdateSubscription) (Subscription, error) {
+	if err := requireOrganization(ctx); err != nil {
+		return Subscription{}, err
+	}
+	if dto.URL != "" {
+		if err := s.d.CheckURL(ctx, dto.URL); err != nil {
+			return Subscription{}, err
+		}
+	}
+	sub, err := s.r.UpdateSingleSubscription(ctx, id, dto)
+	sub.Secret = ""
+	return sub, err
+}
+
+// DeleteSingleSubscription godoc
+// @Summary Delete single webhook subscription
+// @Description Delete a single webhook subscription, its delivery log and dead letters are kept
+// @Security Bearer
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the webhook subscription"
+// @Success 200 {object} JSONResultSuccess{} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /webhooks/{id} [delete]
+func (s *Service) DeleteSingleSubscription(ctx context.Context, id uuid.UUID) error {
+	if err := requireOrganization(ctx); err != nil {
+		return err
+	}
+	return s.r.DeleteSingleSubscription(ctx, id)
+}
+
+// TestSubscription godoc
+// @Summary Ping a webhook subscription
+// @Description Send a signed webhook.ping event to the subscription URL once, without retrying it
+// @Security Bearer
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the webhook subscription"
+// @Success 200 {object} JSONResultSuccess{data=Delivery} "OK: the delivery, which may have failed"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /webhooks/{id}/test [post]
+func (s *Service) TestSubscription(ctx context.Context, id uuid.UUID) (Delivery, error) {
+	if err := requireOrganization(ctx); err != nil {
+		return Delivery{}, err
+	}
+	sub, err := s.r.GetSingleSubscription(ctx, id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	ping := events.Event{
+		Type:    PingEvent,
+		Subject: sub.ID.String(),
+		Time:    time.Now().UTC(),
+		Data:    map[string]string{"message": "pong"},
+	}
+	return s.d.Deliver(ctx, sub, ping), nil
+}
+
+// GetSubscriptionDeliveries godoc
+// @Summary Get the delivery log of a webhook subscription
+// @Description Get the latest delivery attempts of a webhook subscription, newest first
+// @Security Bearer
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the webhook subscription"
+// @Param limit query int false "Maximum number of deliveries, defaults to 50"
+// @Success 200 {object} JSONResultSuccess{data=[]Delivery} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 404 {object} JSONResultFailure "Error: Not Found"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /webhooks/{id}/deliveries [get]
+func (s *Service) GetSubscriptionDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]Delivery, error) {
+	if err := requireOrganization(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.r.GetSingleSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.r.GetSubscriptionDeliveries(ctx, id, limit)
+}
+
+// GetDeadLetters godoc
+// @Summary Get the webhook dead letters
+// @Description Get the latest events which couldn't be delivered to the organization's subscriptions within the maximum number of attempts, newest first
+// @Security Bearer
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param limit query int false "Maximum number of dead letters, defaults to 50"
+// @Success 200 {object} JSONResultSuccess{data=[]DeadLetter} "OK"
+// @Failure 401 {object} JSONResultFailure "Error: Unauthorized"
+// @Failure 500 {object} JSONResultFailure "Error: Internal Server Error"
+// @Router /webhooks/dead-letters [get]
+func (s *Service) GetDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	if err := requireOrganization(ctx); err != nil {
+		return nil, err
+	}
+	return s.r.GetDeadLetters(ctx, limit)
+}
+
+// requireOrganization returns ErrNoOrganization unless the context carries an organization,
+// the requests made for none sharing the subscriptions of the empty organization otherwise.
+func requireOrganization(ctx context.Context) error {
+	if org.FromContext(ctx) == "" {
+		return ErrNoOrganization
+	}
+	return nil
+}
+
+// newSecret returns a random 256-bit signing secret, hex-encoded.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
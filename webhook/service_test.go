@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/org"
+	"github.com/simply-alliv/tigris-go-explore/pkg/shared/params"
+)
+
+func TestServiceRequiresOrganization(t *testing.T) {
+	r := newMemoryRepository()
+	s := NewWebhookService(r, NewDispatcher(r, WithPrivateNetworks()))
+	ctx := context.Background()
+	id := uuid.New()
+
+	calls := map[string]func() error{
+		"GetAllSubscriptions": func() error {
+			_, _, err := s.GetAllSubscriptions(ctx, params.PaginationQueryParams{})
+			return err
+		},
+		"GetSingleSubscription": func() error {
+			_, err := s.GetSingleSubscription(ctx, id)
+			return err
+		},
+		"CreateSingleSubscription": func() error {
+			_, err := s.CreateSingleSubscription(ctx, CreateSubscription{URL: "http://127.0.0.1/hooks"})
+			return err
+		},
+		"UpdateSingleSubscription": func() error {
+			_, err := s.UpdateSingleSubscription(ctx, id, UpdateSubscription{})
+			return err
+		},
+		"DeleteSingleSubscription": func() error { return s.DeleteSingleSubscription(ctx, id) },
+		"TestSubscription": func() error {
+			_, err := s.TestSubscription(ctx, id)
+			return err
+		},
+		"GetSubscriptionDeliveries": func() error {
+			_, err := s.GetSubscriptionDeliveries(ctx, id, DefaultLogLimit)
+			return err
+		},
+		"GetDeadLetters": func() error {
+			_, err := s.GetDeadLetters(ctx, DefaultLogLimit)
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrNoOrganization) {
+			t.Errorf("%s() without an organization = %v, want ErrNoOrganization", name, err)
+		}
+	}
+	if len(r.subscriptions) != 0 {
+		t.Errorf("%d subscriptions created, want none", len(r.subscriptions))
+	}
+
+	sub, err := s.CreateSingleSubscription(org.NewContext(ctx, "acme"), CreateSubscription{URL: "http://127.0.0.1/hooks"})
+	if err != nil || sub.OrganizationID != "acme" {
+		t.Errorf("CreateSingleSubscription() for acme = %+v, %v, want the subscription of acme", sub, err)
+	}
+}
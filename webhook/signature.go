@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the header carrying the signature of a delivery.
+const SignatureHeader = "X-Webhook-Signature"
+
+// ErrInvalidSignature is returned by Verify when a signature doesn't match its payload or is too old.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the signature of a payload sent at the time, in the form `t=<unix seconds>,v1=<hex HMAC-SHA256>`.
+//
+// The HMAC is computed with the subscription secret over `<unix seconds>.<payload>`,
+// so a receiver can reject replayed deliveries by checking the timestamp.
+func Sign(secret string, t time.Time, payload []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, payload))
+}
+
+// Verify checks a signature produced by Sign, rejecting the ones older than the tolerance.
+// It is meant for receivers written in Go, and for testing them.
+func Verify(secret, signature string, payload []byte, tolerance time.Duration) error {
+	var ts, v1 string
+	for _, part := range strings.Split(signature, ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			v1 = v
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 && time.Since(time.Unix(unix, 0)) > tolerance {
+		return ErrInvalidSignature
+	}
+	sig, err := hex.DecodeString(v1)
+	if err != nil || !hmac.Equal(sig, mac(secret, ts, payload)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func mac(secret, ts string, payload []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(payload)
+	return h.Sum(nil)
+}
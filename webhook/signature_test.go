@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	payload := []byte(`{"type":"breed.created"}`)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		signature string
+		secret    string
+		payload   []byte
+		wantErr   bool
+	}{
+		{name: "valid", signature: Sign("secret", now, payload), secret: "secret", payload: payload},
+		{name: "other secret", signature: Sign("secret", now, payload), secret: "other", payload: payload, wantErr: true},
+		{name: "tampered payload", signature: Sign("secret", now, payload), secret: "secret", payload: []byte(`{"type":"breed.deleted"}`), wantErr: true},
+		{name: "too old", signature: Sign("secret", now.Add(-time.Hour), payload), secret: "secret", payload: payload, wantErr: true},
+		{name: "malformed", signature: "v1=abc", secret: "secret", payload: payload, wantErr: true},
+		{name: "empty", signature: "", secret: "secret", payload: payload, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.signature, tt.payload, 5*time.Minute)
+			if tt.wantErr && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify() = %v, want ErrInvalidSignature", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Verify() = %v, want nil", err)
+			}
+		})
+	}
+}